@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+	_ "time/tzdata" // embed zone data so the business time zone loads on minimal images
 
 	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
@@ -17,27 +23,64 @@ import (
 	"github.com/Jose-Ig/lavalo-backend/internal/common"
 
 	addressModels "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	authModels "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
 	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
 	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
 	slotModels "github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	waitlistModels "github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/models"
 
 	addressHttp "github.com/Jose-Ig/lavalo-backend/internal/addresses/application/http"
+	authHttp "github.com/Jose-Ig/lavalo-backend/internal/auth/application/http"
+	clientHttp "github.com/Jose-Ig/lavalo-backend/internal/clients/application/http"
 	paymentHttp "github.com/Jose-Ig/lavalo-backend/internal/payments/application/http"
 	reservationHttp "github.com/Jose-Ig/lavalo-backend/internal/reservations/application/http"
+	serviceHttp "github.com/Jose-Ig/lavalo-backend/internal/services/application/http"
 	slotHttp "github.com/Jose-Ig/lavalo-backend/internal/slots/application/http"
-
+	waitlistHttp "github.com/Jose-Ig/lavalo-backend/internal/waitlist/application/http"
+
+	addressUsecases "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/usecases"
+	addressRepos "github.com/Jose-Ig/lavalo-backend/internal/addresses/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	authUsecases "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/ratelimit"
+	authRepos "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/repositories"
+	authSenders "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/senders"
+	authTokens "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/tokens"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago/mercadopagotest"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationWorkers "github.com/Jose-Ig/lavalo-backend/internal/reservations/application/workers"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	reservationRepos "github.com/Jose-Ig/lavalo-backend/internal/reservations/infrastructure/repositories"
+	serviceUsecases "github.com/Jose-Ig/lavalo-backend/internal/services/domain/usecases"
+	serviceRepos "github.com/Jose-Ig/lavalo-backend/internal/services/infrastructure/repositories"
 	slotUsecases "github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
 	slotRepos "github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/repositories"
+	waitlistUsecases "github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/usecases"
+	waitlistNotifiers "github.com/Jose-Ig/lavalo-backend/internal/waitlist/infrastructure/notifiers"
+	waitlistRepos "github.com/Jose-Ig/lavalo-backend/internal/waitlist/infrastructure/repositories"
 )
 
 const defaultDSN = "data/lavalo.db"
 
+// tokenIssuer is the issuer of access tokens signed by this API
+const tokenIssuer = "lavalo-api"
+
+// fakeMercadoPagoPath is where the fake Mercado Pago is served with PAYMENTS_PROVIDER=fake
+const fakeMercadoPagoPath = "/_fake/mercadopago"
+
 // dbPath stores the resolved database path for debug endpoint
 var dbPath string
 
 func main() {
 	// Parse command line flags
 	migrateOnly := flag.Bool("migrate-only", false, "Run migrations and exit")
+	grantAdmin := flag.String("grant-admin", "", "Run migrations, give the client with this email the admin role and exit")
 	flag.Parse()
 
 	// Initialize logger
@@ -51,8 +94,15 @@ func main() {
 	common.Logger.Info("Configuration loaded",
 		zap.String("port", cfg.Server.Port),
 		zap.String("mode", cfg.Server.Mode),
+		zap.String("business_timezone", cfg.Business.TimeZone),
 	)
 
+	location, err := cfg.Business.Location()
+	if err != nil {
+		common.Logger.Error("Failed to load business time zone", zap.Error(err))
+		os.Exit(1)
+	}
+
 	// Initialize database
 	db, err := initDatabase(cfg)
 	if err != nil {
@@ -72,14 +122,37 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Bootstrap an admin, who can then grant roles through the API
+	if *grantAdmin != "" {
+		clientUseCase := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+		if err := grantAdminRole(context.Background(), clientUseCase, *grantAdmin); err != nil {
+			common.Logger.Error("Failed to grant admin role", zap.Error(err))
+			os.Exit(1)
+		}
+		common.Logger.Info("Admin role granted", zap.String("email", *grantAdmin))
+		os.Exit(0)
+	}
+
+	jwtSecret, err := resolveJWTSecret(cfg)
+	if err != nil {
+		common.Logger.Error("Failed to load auth configuration", zap.Error(err))
+		os.Exit(1)
+	}
+
 	// Setup Gin
 	gin.SetMode(cfg.Server.Mode)
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(ginLogger())
 
+	paymentProvider, err := newPaymentProvider(router, cfg)
+	if err != nil {
+		common.Logger.Error("Failed to load payments configuration", zap.Error(err))
+		os.Exit(1)
+	}
+
 	// Register routes
-	setupRoutes(router, db)
+	setupRoutes(router, db, cfg, location, jwtSecret, paymentProvider)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
@@ -91,6 +164,98 @@ func main() {
 	}
 }
 
+// resolveJWTSecret returns the access token signing secret.
+// Release mode requires AUTH_JWT_SECRET; other modes fall back to a random secret,
+// which invalidates every access token on restart.
+func resolveJWTSecret(cfg *common.Config) ([]byte, error) {
+	if cfg.Auth.JWTSecret != "" {
+		return []byte(cfg.Auth.JWTSecret), nil
+	}
+	if cfg.Server.Mode == gin.ReleaseMode {
+		return nil, errors.New("AUTH_JWT_SECRET is required in release mode")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate JWT secret: %w", err)
+	}
+	common.Logger.Warn("AUTH_JWT_SECRET not set, using an ephemeral secret; tokens will not survive a restart")
+	return secret, nil
+}
+
+// newPaymentProvider returns the payment provider configured by PAYMENTS_PROVIDER.
+// The fake provider is a Mercado Pago fake served by this API itself, so checkouts can be
+// paid by hand during development and are notified to the webhook; it is refused in release mode.
+func newPaymentProvider(router *gin.Engine, cfg *common.Config) (paymentUsecases.PaymentProvider, error) {
+	publicURL := strings.TrimSuffix(cfg.Payments.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = "http://localhost:" + cfg.Server.Port
+	}
+
+	mpConfig := mercadopago.Config{
+		BaseURL:          cfg.Payments.MercadoPagoBaseURL,
+		AccessToken:      cfg.Payments.MercadoPagoAccessToken,
+		NotificationURL:  publicURL + "/api/v1/payments/webhook",
+		ReturnURL:        cfg.Payments.ReturnURL,
+		WebhookSecret:    cfg.Payments.WebhookSecret,
+		WebhookTolerance: time.Duration(cfg.Payments.WebhookToleranceSeconds) * time.Second,
+	}
+
+	switch cfg.Payments.Provider {
+	case "mercadopago":
+		if mpConfig.AccessToken == "" {
+			return nil, errors.New("MERCADOPAGO_ACCESS_TOKEN is required for the mercadopago provider")
+		}
+		if mpConfig.WebhookSecret == "" {
+			if cfg.Server.Mode == gin.ReleaseMode {
+				return nil, errors.New("PAYMENTS_WEBHOOK_SECRET is required in release mode")
+			}
+			common.Logger.Warn("PAYMENTS_WEBHOOK_SECRET not set; payment webhooks will be rejected")
+		}
+	case "fake":
+		if cfg.Server.Mode == gin.ReleaseMode {
+			return nil, errors.New("the fake payment provider cannot be used in release mode")
+		}
+		common.Logger.Warn("Using the fake payment provider; checkouts are paid by hand", zap.String("url", publicURL+fakeMercadoPagoPath))
+
+		if mpConfig.WebhookSecret == "" {
+			mpConfig.WebhookSecret = "fake-webhook-secret"
+		}
+		mpConfig.AccessToken = "fake-access-token"
+		mpConfig.BaseURL = publicURL + fakeMercadoPagoPath
+		fakeServer := mercadopagotest.NewServer(mpConfig.AccessToken)
+		fakeServer.EnableWebhooks(mpConfig.WebhookSecret)
+		router.Any(fakeMercadoPagoPath+"/*path", gin.WrapH(http.StripPrefix(fakeMercadoPagoPath, fakeServer)))
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Payments.Provider)
+	}
+
+	return mercadopago.NewProvider(mpConfig), nil
+}
+
+// newCodeSender returns where one-time login codes are delivered.
+// Until an email/SMS provider is integrated, codes go to the outbox file if configured, or to the log.
+func newCodeSender(cfg *common.Config) authUsecases.CodeSender {
+	if cfg.Server.Mode == gin.ReleaseMode {
+		common.Logger.Warn("Login codes are written locally instead of being delivered to clients")
+	}
+	if cfg.Auth.OTPOutboxFile != "" {
+		return authSenders.NewFileSender(cfg.Auth.OTPOutboxFile)
+	}
+	return authSenders.NewLogSender()
+}
+
+// grantAdminRole gives the client registered with email the admin role
+func grantAdminRole(ctx context.Context, clients *clientUsecases.ClientUseCase, email string) error {
+	client, err := clients.GetClientByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	_, err = clients.SetRole(ctx, client.ID, clientModels.RoleAdmin)
+	return err
+}
+
 // initDatabase initializes the SQLite database connection
 func initDatabase(cfg *common.Config) (*gorm.DB, error) {
 	// Set default DSN if empty
@@ -120,8 +285,13 @@ func initDatabase(cfg *common.Config) (*gorm.DB, error) {
 	)
 
 	// Open database connection
-	db, err := gorm.Open(sqlite.Open(absPath), &gorm.Config{
-		Logger: gormlogger.Default.LogMode(gormlogger.Info),
+	// Timestamps are stored in UTC so SQLite's text comparisons order them correctly.
+	// Foreign keys are enforced on every pooled connection and constraint errors are
+	// translated so repositories can map them to domain errors.
+	db, err := gorm.Open(sqlite.Open(absPath+"?_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger:         gormlogger.Default.LogMode(gormlogger.Info),
+		NowFunc:        func() time.Time { return time.Now().UTC() },
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -139,49 +309,212 @@ func initDatabase(cfg *common.Config) (*gorm.DB, error) {
 func runMigrations(db *gorm.DB) error {
 	common.Logger.Info("Running database migrations...")
 
+	// Email and DNI used to be unique across deleted clients too; replace those
+	// indexes with ones that only cover clients that have not been deleted
+	for _, index := range []string{"idx_clients_email", "idx_clients_document_number"} {
+		if db.Migrator().HasIndex(&clientModels.Client{}, index) {
+			if err := db.Migrator().DropIndex(&clientModels.Client{}, index); err != nil {
+				return fmt.Errorf("failed to drop index %s: %w", index, err)
+			}
+		}
+	}
+
+	// Clients go first so rows created before the clients table existed can be
+	// backfilled before the foreign keys from reservations and addresses are added
+	if err := db.AutoMigrate(&clientModels.Client{}); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	if err := backfillClients(db); err != nil {
+		return fmt.Errorf("failed to backfill clients: %w", err)
+	}
+
 	err := db.AutoMigrate(
+		&clientModels.Vehicle{},
 		&reservationModels.Reservation{},
+		&reservationModels.ReservationStatusChange{},
+		&reservationModels.ReservationReschedule{},
+		&reservationModels.ReservationSeries{},
+		&reservationModels.ReservationCancellation{},
 		&slotModels.Slot{},
+		&slotModels.BusinessHours{},
+		&slotModels.Closure{},
+		&serviceModels.Service{},
 		&addressModels.Address{},
 		&paymentModels.Payment{},
+		&paymentModels.WebhookEvent{},
+		&paymentModels.Refund{},
+		&waitlistModels.WaitlistEntry{},
+		&authModels.RefreshToken{},
+		&authModels.OneTimeCode{},
+		&authModels.APIKey{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Amounts used to be decimal columns; move them to the integer Money columns added above
+	err = common.MigrateDecimalMoney(db,
+		common.DecimalMoneyColumn{Table: "payments", Column: "amount", Prefix: "amount_", CurrencyColumn: "currency"},
+		common.DecimalMoneyColumn{Table: "payments", Column: "refunded_amount", Prefix: "refunded_", CurrencyColumn: "currency"},
+		common.DecimalMoneyColumn{Table: "reservations", Column: "price", Prefix: "price_"},
+		common.DecimalMoneyColumn{Table: "services", Column: "base_price", Prefix: "base_price_"},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to migrate money columns: %w", err)
+	}
+
 	common.Logger.Info("Database migrations completed successfully")
 	return nil
 }
 
+// backfillClients creates a placeholder client for every user_id referenced by
+// reservations or addresses that has no client yet, keeping the same ID.
+// Placeholders use an undeliverable .invalid email until staff complete them.
+func backfillClients(db *gorm.DB) error {
+	now := time.Now().UTC()
+	for _, table := range []string{"reservations", "addresses"} {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+
+		err := db.Exec(fmt.Sprintf(`INSERT INTO clients (id, name, email, created_at, updated_at)
+			SELECT DISTINCT user_id, 'Cliente ' || user_id, 'cliente-' || user_id || '@lavalo.invalid', ?, ?
+			FROM %s WHERE user_id NOT IN (SELECT id FROM clients)`, table), now, now).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // setupRoutes configures all API routes
-func setupRoutes(router *gin.Engine, db *gorm.DB) {
+func setupRoutes(router *gin.Engine, db *gorm.DB, cfg *common.Config, location *time.Location, jwtSecret []byte, paymentProvider paymentUsecases.PaymentProvider) {
 	// Health check
 	router.GET("/health", healthHandler)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+		clientRepo := clientRepos.NewClientRepository(db)
+		clientUseCase := clientUsecases.NewClientUseCase(clientRepo)
+
+		// Authentication: signup, login, one-time codes and token refresh are public, everything
+		// registered on the protected group requires a bearer access token
+		refreshTokenRepo := authRepos.NewRefreshTokenRepository(db)
+		signer := authTokens.NewJWTSigner(jwtSecret, tokenIssuer)
+		accessTTL := time.Duration(cfg.Auth.AccessTokenMinutes) * time.Minute
+		refreshTTL := time.Duration(cfg.Auth.RefreshTokenDays) * 24 * time.Hour
+		authUseCase := authUsecases.NewAuthUseCase(clientUseCase, refreshTokenRepo, signer, accessTTL, refreshTTL, time.Now)
+		authHandler := authHttp.NewAuthHandler(authUseCase)
+		authHandler.RegisterRoutes(v1)
+
+		otpRepo := authRepos.NewOneTimeCodeRepository(db)
+		otpTTL := time.Duration(cfg.Auth.OTPMinutes) * time.Minute
+		otpUseCase := authUsecases.NewOTPUseCase(authUseCase, otpRepo, newCodeSender(cfg), otpTTL, cfg.Auth.OTPMaxAttempts)
+		otpHandler := authHttp.NewOTPHandler(otpUseCase)
+		otpHandler.RegisterRoutes(v1)
+
+		// Partner systems may authenticate with an API key instead, limited to its scopes
+		apiKeyRepo := authRepos.NewAPIKeyRepository(db)
+		apiKeyLimiter := ratelimit.NewFixedWindowLimiter(time.Minute)
+		apiKeyUseCase := authUsecases.NewAPIKeyUseCase(apiKeyRepo, clientUseCase, apiKeyLimiter, cfg.Auth.APIKeyRateLimitPerMinute, time.Now)
+		clientUseCase.AddClientDeletionListener(apiKeyUseCase)
+
+		protected := v1.Group("", authHttp.RequireAuth(authUseCase, apiKeyUseCase))
+		authHandler.RegisterProtectedRoutes(protected)
+		apiKeyHandler := authHttp.NewAPIKeyHandler(apiKeyUseCase)
+		apiKeyHandler.RegisterRoutes(protected)
+
 		// Availability endpoint - wired with usecase
 		availabilityRepo := slotRepos.NewAvailabilityRepository(db)
-		availabilityUseCase := slotUsecases.NewAvailabilityUseCase(availabilityRepo)
+		leadTime := time.Duration(cfg.Reservations.MinLeadMinutes) * time.Minute
+		availabilityUseCase := slotUsecases.NewAvailabilityUseCase(availabilityRepo, location, leadTime, time.Now)
 		availabilityHandler := reservationHttp.NewAvailabilityHandler(availabilityUseCase)
-		v1.GET("/availability", availabilityHandler.GetAvailability)
+		v1.GET("/availability", authHttp.OptionalAuth(authUseCase, apiKeyUseCase), availabilityHandler.GetAvailability)
+
+		serviceRepo := serviceRepos.NewServiceRepository(db)
+		serviceUseCase := serviceUsecases.NewServiceUseCase(serviceRepo)
+
+		paymentRepo := paymentRepos.NewPaymentRepository(db)
+		paymentUseCase := paymentUsecases.NewPaymentUseCase(paymentRepo)
+		// Cancellations refund through the payment provider, recording each refund
+		refundUseCase := paymentUsecases.NewRefundUseCase(paymentRepo, paymentRepos.NewRefundRepository(db), paymentProvider)
+
+		vehicleRepo := clientRepos.NewVehicleRepository(db)
+		vehicleUseCase := clientUsecases.NewVehicleUseCase(vehicleRepo)
+
+		addressRepo := addressRepos.NewAddressRepository(db)
+		addressUseCase := addressUsecases.NewAddressUseCase(addressRepo, clientUseCase)
 
 		// Register domain handlers
-		reservationHandler := reservationHttp.NewReservationHandler()
-		reservationHandler.RegisterRoutes(v1)
+		cancellationPolicy := reservationModels.CancellationPolicy{
+			FreeCancellationWindow:     time.Duration(cfg.Reservations.FreeCancellationHours) * time.Hour,
+			LateCancellationFeePercent: cfg.Reservations.LateCancellationFeePercent,
+		}
+		pricingPolicy := reservationModels.PricingPolicy{
+			SizeSurchargePercent: map[clientModels.VehicleSize]int{
+				clientModels.VehicleSizeSUV: cfg.Pricing.SUVSurchargePercent,
+				clientModels.VehicleSizeVan: cfg.Pricing.VanSurchargePercent,
+			},
+		}
+		reservationRepo := reservationRepos.NewReservationRepository(db)
+		holdTTL := time.Duration(cfg.Reservations.HoldMinutes) * time.Minute
+		reservationUseCase := reservationUsecases.NewReservationUseCase(reservationRepo, availabilityUseCase, serviceUseCase, vehicleUseCase, addressUseCase, refundUseCase, cancellationPolicy, pricingPolicy, holdTTL, time.Now)
+		reservationHandler := reservationHttp.NewReservationHandler(reservationUseCase)
+		reservationHandler.RegisterRoutes(protected)
+
+		// Offer slots freed by cancellations and expired holds to the waitlist
+		waitlistRepo := waitlistRepos.NewWaitlistRepository(db)
+		waitlistUseCase := waitlistUsecases.NewWaitlistUseCase(waitlistRepo, reservationUseCase, waitlistNotifiers.NewLogNotifier(), time.Now)
+		reservationUseCase.AddSlotReleaseListener(waitlistUseCase)
+		reservationUseCase.AddConfirmationListener(waitlistUseCase)
+		waitlistHandler := waitlistHttp.NewWaitlistHandler(waitlistUseCase)
+		waitlistHandler.RegisterRoutes(protected)
+
+		// Release reservation holds that expire before being confirmed
+		holdSweeper := reservationWorkers.NewHoldSweeper(reservationUseCase, time.Duration(cfg.Reservations.HoldSweepSeconds)*time.Second)
+		go holdSweeper.Run(context.Background())
+
+		// Retry refunds that failed when their reservation was cancelled
+		refundRetrier := reservationWorkers.NewRefundRetrier(reservationUseCase, time.Duration(cfg.Reservations.RefundRetrySeconds)*time.Second)
+		go refundRetrier.Run(context.Background())
+
+		serviceHandler := serviceHttp.NewServiceHandler(serviceUseCase)
+		serviceHandler.RegisterRoutes(protected)
 
 		slotHandler := slotHttp.NewSlotHandler()
-		slotHandler.RegisterRoutes(v1)
+		slotHandler.RegisterRoutes(protected)
+
+		businessHoursRepo := slotRepos.NewBusinessHoursRepository(db)
+		businessHoursUseCase := slotUsecases.NewBusinessHoursUseCase(businessHoursRepo, slotRepos.NewSlotRepository(db))
+		businessHoursHandler := slotHttp.NewBusinessHoursHandler(businessHoursUseCase)
+		businessHoursHandler.RegisterRoutes(protected)
+
+		closureRepo := slotRepos.NewClosureRepository(db)
+		closureUseCase := slotUsecases.NewClosureUseCase(closureRepo)
+		closureHandler := slotHttp.NewClosureHandler(closureUseCase, location)
+		closureHandler.RegisterRoutes(protected)
 
-		addressHandler := addressHttp.NewAddressHandler()
-		addressHandler.RegisterRoutes(v1)
+		clientHandler := clientHttp.NewClientHandler(clientUseCase)
+		clientHandler.RegisterRoutes(protected)
 
-		paymentHandler := paymentHttp.NewPaymentHandler()
-		paymentHandler.RegisterRoutes(v1)
+		vehicleHandler := clientHttp.NewVehicleHandler(vehicleUseCase)
+		vehicleHandler.RegisterRoutes(protected)
+
+		addressHandler := addressHttp.NewAddressHandler(addressUseCase)
+		addressHandler.RegisterRoutes(protected)
+
+		// Payments are charged through a hosted checkout of the payment provider, in full or as a
+		// deposit, or taken in cash at the bay
+		depositPolicy := paymentModels.DepositPolicy{Percent: cfg.Payments.DepositPercent}
+		checkoutUseCase := paymentUsecases.NewCheckoutUseCase(paymentRepo, paymentProvider, reservationUseCase, depositPolicy)
+		webhookUseCase := paymentUsecases.NewWebhookUseCase(paymentRepo, paymentRepos.NewWebhookEventRepository(db), paymentProvider, reservationUseCase, refundUseCase)
+		paymentHandler := paymentHttp.NewPaymentHandler(paymentUseCase, checkoutUseCase, webhookUseCase, refundUseCase, reservationUseCase)
+		paymentHandler.RegisterRoutes(protected)
+		paymentHandler.RegisterWebhookRoutes(v1)
 
 		// Debug endpoints
-		debug := v1.Group("/_debug")
+		debug := protected.Group("/_debug")
 		{
 			debug.GET("/db", DebugDBHandler(db))
 		}
@@ -196,9 +529,14 @@ func healthHandler(c *gin.Context) {
 	})
 }
 
-// DebugDBHandler returns database debug information
+// DebugDBHandler returns database debug information to admins
 func DebugDBHandler(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+			common.RespondError(c, err)
+			return
+		}
+
 		tables, err := common.ListTables(db)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
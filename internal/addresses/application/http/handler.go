@@ -1,19 +1,61 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 )
 
 // AddressHandler handles HTTP requests for addresses
 type AddressHandler struct {
-	// TODO: Add use cases dependency injection
+	useCase *usecases.AddressUseCase
 }
 
 // NewAddressHandler creates a new address handler
-func NewAddressHandler() *AddressHandler {
-	return &AddressHandler{}
+func NewAddressHandler(useCase *usecases.AddressUseCase) *AddressHandler {
+	return &AddressHandler{
+		useCase: useCase,
+	}
+}
+
+// addressRequest is the request body for creating and updating addresses
+type addressRequest struct {
+	UserID       uint    `json:"user_id" binding:"required"`
+	Street       string  `json:"street" binding:"required"`
+	Number       string  `json:"number"`
+	Apartment    string  `json:"apartment"`
+	City         string  `json:"city" binding:"required"`
+	State        string  `json:"state"`
+	ZipCode      string  `json:"zip_code"`
+	Country      string  `json:"country"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Instructions string  `json:"instructions"`
+	IsDefault    bool    `json:"is_default"`
+}
+
+// toInput converts the request body to a use case input
+func (r addressRequest) toInput() usecases.AddressInput {
+	return usecases.AddressInput{
+		UserID:       r.UserID,
+		Street:       r.Street,
+		Number:       r.Number,
+		Apartment:    r.Apartment,
+		City:         r.City,
+		State:        r.State,
+		ZipCode:      r.ZipCode,
+		Country:      r.Country,
+		Latitude:     r.Latitude,
+		Longitude:    r.Longitude,
+		Instructions: r.Instructions,
+		IsDefault:    r.IsDefault,
+	}
 }
 
 // RegisterRoutes registers all address routes
@@ -28,45 +70,159 @@ func (h *AddressHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	}
 }
 
-// List returns all addresses
+// List returns a client's addresses
+// @Summary List addresses
+// @Tags addresses
+// @Produce json
+// @Param user_id query int false "Owner; required for staff, defaults to the caller for customers"
+// @Success 200 {array} models.Address
+// @Failure 400 {object} common.APIError "Missing user_id"
+// @Failure 403 {object} common.APIError "Another client's addresses"
+// @Router /api/v1/addresses [get]
 func (h *AddressHandler) List(c *gin.Context) {
-	// TODO: Implement list addresses
+	requested, err := common.ParseOptionalIDQuery(c, "user_id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	_, userID, err := policies.ScopeClient(c.Request.Context(), requested)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	if userID == 0 {
+		common.RespondError(c, fmt.Errorf("%w: user_id is required", common.ErrInvalidInput))
+		return
+	}
+
+	addresses, err := h.useCase.ListUserAddresses(c.Request.Context(), userID)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    []interface{}{},
-		"message": "list addresses - not implemented",
+		"data": addresses,
 	})
 }
 
 // GetByID returns an address by ID
 func (h *AddressHandler) GetByID(c *gin.Context) {
-	// TODO: Implement get address by ID
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	address, err := h.authorizeAddress(c, id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    nil,
-		"message": "get address - not implemented",
+		"data": address,
 	})
 }
 
-// Create creates a new address
+// Create registers an address for a client
+// @Summary Create address
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Address
+// @Failure 400 {object} common.APIError "Invalid input or unknown client"
+// @Failure 403 {object} common.APIError "Another client's address"
+// @Router /api/v1/addresses [post]
 func (h *AddressHandler) Create(c *gin.Context) {
-	// TODO: Implement create address
+	var req addressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), req.UserID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	address, err := h.useCase.CreateAddress(c.Request.Context(), req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"data":    nil,
-		"message": "create address - not implemented",
+		"data": address,
 	})
 }
 
-// Update updates an existing address
+// Update replaces an address's editable fields
 func (h *AddressHandler) Update(c *gin.Context) {
-	// TODO: Implement update address
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req addressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := h.authorizeAddress(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	if _, err := policies.RequireClient(c.Request.Context(), req.UserID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	address, err := h.useCase.UpdateAddress(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    nil,
-		"message": "update address - not implemented",
+		"data": address,
 	})
 }
 
-// Delete deletes an address
+// Delete removes an address
 func (h *AddressHandler) Delete(c *gin.Context) {
-	// TODO: Implement delete address
-	c.JSON(http.StatusNoContent, nil)
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := h.authorizeAddress(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.useCase.DeleteAddress(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
+// authorizeAddress loads an address the caller owns, or any address for staff.
+// Other clients' addresses are reported as not found.
+func (h *AddressHandler) authorizeAddress(c *gin.Context, id uint) (*models.Address, error) {
+	address, err := h.useCase.GetAddress(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := policies.RequireOwner(c.Request.Context(), address.UserID, fmt.Errorf("%w: address %d", common.ErrNotFound, id)); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
 )
 
 // Address represents a user's service address
@@ -24,6 +26,8 @@ type Address struct {
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	// Client declares the foreign key from UserID; it is never loaded
+	Client *clientModels.Client `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
 }
 
 // TableName specifies the table name for Address
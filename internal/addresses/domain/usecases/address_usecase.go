@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// AddressRepository defines the interface for address data access
+type AddressRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.Address, error)
+	FindByUserID(ctx context.Context, userID uint) ([]models.Address, error)
+	// Create and Update return common.ErrInvalidInput if the client does not exist
+	Create(ctx context.Context, address *models.Address) error
+	Update(ctx context.Context, address *models.Address) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// ClientDirectory looks up the clients addresses belong to
+type ClientDirectory interface {
+	// GetClient returns common.ErrNotFound for unknown and deleted clients
+	GetClient(ctx context.Context, id uint) (*clientModels.Client, error)
+}
+
+// AddressInput holds the editable fields of an address
+type AddressInput struct {
+	UserID       uint
+	Street       string
+	Number       string
+	Apartment    string
+	City         string
+	State        string
+	ZipCode      string
+	Country      string
+	Latitude     float64
+	Longitude    float64
+	Instructions string
+	IsDefault    bool
+}
+
+// AddressUseCase handles address business logic
+type AddressUseCase struct {
+	repo    AddressRepository
+	clients ClientDirectory
+}
+
+// NewAddressUseCase creates a new address use case
+func NewAddressUseCase(repo AddressRepository, clients ClientDirectory) *AddressUseCase {
+	return &AddressUseCase{
+		repo:    repo,
+		clients: clients,
+	}
+}
+
+// ListUserAddresses returns the addresses of a client
+func (uc *AddressUseCase) ListUserAddresses(ctx context.Context, userID uint) ([]models.Address, error) {
+	addresses, err := uc.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return addresses, nil
+}
+
+// GetAddress returns an address by ID
+func (uc *AddressUseCase) GetAddress(ctx context.Context, id uint) (*models.Address, error) {
+	address, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: address %d", common.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return address, nil
+}
+
+// GetBookableAddress returns an address of clientID, or ErrInvalidInput if a wash cannot be booked there
+// for that client, including when the client was deleted
+func (uc *AddressUseCase) GetBookableAddress(ctx context.Context, clientID, id uint) (*models.Address, error) {
+	address, err := uc.GetAddress(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: address %d does not exist", common.ErrInvalidInput, id)
+		}
+		return nil, err
+	}
+
+	if address.UserID != clientID {
+		return nil, fmt.Errorf("%w: address %d does not belong to client %d", common.ErrInvalidInput, id, clientID)
+	}
+	if err := uc.ensureClient(ctx, clientID); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// CreateAddress registers an address for a client
+func (uc *AddressUseCase) CreateAddress(ctx context.Context, input AddressInput) (*models.Address, error) {
+	address := &models.Address{}
+	if err := applyAddressInput(address, input); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureClient(ctx, address.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, address); err != nil {
+		return nil, mapAddressSaveError(err)
+	}
+	return address, nil
+}
+
+// UpdateAddress replaces the editable fields of an address
+func (uc *AddressUseCase) UpdateAddress(ctx context.Context, id uint, input AddressInput) (*models.Address, error) {
+	address, err := uc.GetAddress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyAddressInput(address, input); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureClient(ctx, address.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, address); err != nil {
+		return nil, mapAddressSaveError(err)
+	}
+	return address, nil
+}
+
+// DeleteAddress removes an address
+func (uc *AddressUseCase) DeleteAddress(ctx context.Context, id uint) error {
+	if _, err := uc.GetAddress(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// ensureClient returns ErrInvalidInput unless clientID is a client that has not been deleted.
+// Deleted clients are kept for their history, so the foreign key alone does not reject them.
+func (uc *AddressUseCase) ensureClient(ctx context.Context, clientID uint) error {
+	if _, err := uc.clients.GetClient(ctx, clientID); err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("%w: client %d does not exist", common.ErrInvalidInput, clientID)
+		}
+		return err
+	}
+	return nil
+}
+
+// applyAddressInput validates and normalizes input into address
+func applyAddressInput(address *models.Address, input AddressInput) error {
+	if input.UserID == 0 {
+		return fmt.Errorf("%w: user_id is required", common.ErrInvalidInput)
+	}
+
+	street := strings.TrimSpace(input.Street)
+	if street == "" {
+		return fmt.Errorf("%w: street is required", common.ErrInvalidInput)
+	}
+	city := strings.TrimSpace(input.City)
+	if city == "" {
+		return fmt.Errorf("%w: city is required", common.ErrInvalidInput)
+	}
+	if input.Latitude < -90 || input.Latitude > 90 || input.Longitude < -180 || input.Longitude > 180 {
+		return fmt.Errorf("%w: coordinates out of range", common.ErrInvalidInput)
+	}
+
+	country := strings.TrimSpace(input.Country)
+	if country == "" {
+		country = "Argentina"
+	}
+
+	address.UserID = input.UserID
+	address.Street = street
+	address.Number = strings.TrimSpace(input.Number)
+	address.Apartment = strings.TrimSpace(input.Apartment)
+	address.City = city
+	address.State = strings.TrimSpace(input.State)
+	address.ZipCode = strings.TrimSpace(input.ZipCode)
+	address.Country = country
+	address.Latitude = input.Latitude
+	address.Longitude = input.Longitude
+	address.Instructions = strings.TrimSpace(input.Instructions)
+	address.IsDefault = input.IsDefault
+	return nil
+}
+
+// mapAddressSaveError keeps input errors and wraps anything else as internal
+func mapAddressSaveError(err error) error {
+	if errors.Is(err, common.ErrInvalidInput) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+}
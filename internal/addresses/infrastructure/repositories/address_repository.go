@@ -2,8 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	"gorm.io/gorm"
 )
 
@@ -32,6 +35,9 @@ func (r *AddressRepository) FindAll(ctx context.Context) ([]models.Address, erro
 func (r *AddressRepository) FindByID(ctx context.Context, id uint) (*models.Address, error) {
 	var address models.Address
 	if err := r.db.WithContext(ctx).First(&address, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
 		return nil, err
 	}
 	return &address, nil
@@ -46,14 +52,16 @@ func (r *AddressRepository) FindByUserID(ctx context.Context, userID uint) ([]mo
 	return addresses, nil
 }
 
-// Create creates a new address
+// Create creates a new address.
+// Returns common.ErrInvalidInput if the client does not exist.
 func (r *AddressRepository) Create(ctx context.Context, address *models.Address) error {
-	return r.db.WithContext(ctx).Create(address).Error
+	return mapClientViolation(r.db.WithContext(ctx).Create(address).Error, address.UserID)
 }
 
-// Update updates an existing address
+// Update updates an existing address.
+// Returns common.ErrInvalidInput if the client does not exist.
 func (r *AddressRepository) Update(ctx context.Context, address *models.Address) error {
-	return r.db.WithContext(ctx).Save(address).Error
+	return mapClientViolation(r.db.WithContext(ctx).Save(address).Error, address.UserID)
 }
 
 // Delete soft deletes an address
@@ -61,3 +69,11 @@ func (r *AddressRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.Address{}, id).Error
 }
 
+// mapClientViolation maps a foreign key violation on user_id to common.ErrInvalidInput
+func mapClientViolation(err error, userID uint) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return fmt.Errorf("%w: client %d does not exist", common.ErrInvalidInput, userID)
+	}
+	return err
+}
+
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// APIKeyHandler handles HTTP requests for administering partner API keys
+type APIKeyHandler struct {
+	useCase *usecases.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(useCase *usecases.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		useCase: useCase,
+	}
+}
+
+// issueAPIKeyRequest is the request body for POST /api-keys
+type issueAPIKeyRequest struct {
+	ClientID           uint           `json:"client_id" binding:"required"`
+	Name               string         `json:"name" binding:"required"`
+	Scopes             []models.Scope `json:"scopes" binding:"required"`
+	RateLimitPerMinute int            `json:"rate_limit_per_minute"`
+}
+
+// RegisterRoutes registers the API key routes; they require an admin access token
+func (h *APIKeyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	keys := rg.Group("/api-keys")
+	{
+		keys.GET("", h.List)
+		keys.POST("", h.Issue)
+		keys.DELETE("/:id", h.Revoke)
+	}
+}
+
+// List returns API keys without their values
+// @Summary List API keys
+// @Tags api-keys
+// @Produce json
+// @Param client_id query int false "Owner; all keys if omitted"
+// @Success 200 {array} models.APIKey
+// @Failure 403 {object} common.APIError "Not an admin"
+// @Router /api/v1/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	clientID, err := common.ParseOptionalIDQuery(c, "client_id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	keys, err := h.useCase.ListAPIKeys(c.Request.Context(), clientID)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": keys,
+	})
+}
+
+// Issue creates an API key for a partner client
+// @Summary Issue API key
+// @Description The key acts as client_id with customer permissions, limited to its scopes:
+// @Description availability:read, services:read, reservations:read, reservations:write, vehicles:read, vehicles:write.
+// @Description The key value is in the response only; send it in the X-API-Key header.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.APIKey
+// @Failure 400 {object} common.APIError "Invalid input, unknown scope or unknown client"
+// @Failure 403 {object} common.APIError "Not an admin"
+// @Router /api/v1/api-keys [post]
+func (h *APIKeyHandler) Issue(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req issueAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	key, value, err := h.useCase.IssueAPIKey(c.Request.Context(), usecases.IssueAPIKeyInput{
+		ClientID:           req.ClientID,
+		Name:               req.Name,
+		Scopes:             req.Scopes,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": gin.H{
+			"api_key": key,
+			"key":     value,
+		},
+	})
+}
+
+// Revoke stops an API key from authenticating; the key stays listed as revoked
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := h.useCase.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// AuthHandler handles HTTP requests for authentication
+type AuthHandler struct {
+	useCase *usecases.AuthUseCase
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(useCase *usecases.AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		useCase: useCase,
+	}
+}
+
+// signupRequest is the request body for POST /auth/signup
+type signupRequest struct {
+	Name           string `json:"name" binding:"required"`
+	Email          string `json:"email" binding:"required"`
+	Phone          string `json:"phone"`
+	DocumentNumber string `json:"document_number"`
+	Password       string `json:"password" binding:"required"`
+}
+
+// loginRequest is the request body for POST /auth/login
+type loginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// refreshRequest is the request body for POST /auth/refresh and /auth/logout
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RegisterRoutes registers the public auth routes
+func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	auth := rg.Group("/auth")
+	{
+		auth.POST("/signup", h.Signup)
+		auth.POST("/login", h.Login)
+		auth.POST("/refresh", h.Refresh)
+		auth.POST("/logout", h.Logout)
+	}
+}
+
+// RegisterProtectedRoutes registers the auth routes that require an access token
+func (h *AuthHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/auth/me", h.Me)
+}
+
+// Signup registers a client with a password
+// @Summary Sign up
+// @Description Creates a client with a bcrypt-hashed password (8 to 72 characters) and returns a token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.TokenPair
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 409 {object} common.APIError "Email or document number already registered"
+// @Router /api/v1/auth/signup [post]
+func (h *AuthHandler) Signup(c *gin.Context) {
+	var req signupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	client, tokens, err := h.useCase.Signup(c.Request.Context(), usecases.SignupInput{
+		Client: clientUsecases.ClientInput{
+			Name:           req.Name,
+			Email:          req.Email,
+			Phone:          req.Phone,
+			DocumentNumber: req.DocumentNumber,
+		},
+		Password: req.Password,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": gin.H{
+			"client": client,
+			"tokens": tokens,
+		},
+	})
+}
+
+// Login exchanges credentials for a token pair
+// @Summary Log in
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TokenPair
+// @Failure 401 {object} common.APIError "Invalid email or password"
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	tokens, err := h.useCase.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tokens,
+	})
+}
+
+// Refresh rotates a refresh token
+// @Summary Refresh tokens
+// @Description Returns a new token pair; the refresh token sent is revoked and cannot be used again
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TokenPair
+// @Failure 401 {object} common.APIError "Unknown, expired or reused refresh token"
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	tokens, err := h.useCase.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tokens,
+	})
+}
+
+// Logout revokes a refresh token
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if err := h.useCase.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Me returns the authenticated client
+func (h *AuthHandler) Me(c *gin.Context) {
+	client, err := h.useCase.CurrentClient(c.Request.Context())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": client,
+	})
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// APIKeyHeader carries a partner API key in place of a bearer access token
+const APIKeyHeader = "X-API-Key"
+
+// errNoCredentials means the request carried neither a bearer token nor an API key
+var errNoCredentials = fmt.Errorf("%w: missing bearer token or API key", common.ErrUnauthorized)
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer" access token or
+// X-API-Key API key, and stores the authenticated Principal in the request context.
+// API keys may only call the routes their scopes grant.
+func RequireAuth(tokens *usecases.AuthUseCase, keys *usecases.APIKeyUseCase) gin.HandlerFunc {
+	return authenticate(tokens, keys, true)
+}
+
+// OptionalAuth lets anonymous requests through to public routes but authenticates any
+// credentials sent, so API keys still need the route's scope and count against their rate limit
+func OptionalAuth(tokens *usecases.AuthUseCase, keys *usecases.APIKeyUseCase) gin.HandlerFunc {
+	return authenticate(tokens, keys, false)
+}
+
+// authenticate builds the auth middleware; required rejects anonymous requests
+func authenticate(tokens *usecases.AuthUseCase, keys *usecases.APIKeyUseCase, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := resolvePrincipal(c, tokens, keys)
+		if errors.Is(err, errNoCredentials) && !required {
+			c.Next()
+			return
+		}
+		if err != nil {
+			reject(c, err)
+			return
+		}
+
+		if principal.APIKeyID != 0 {
+			if err := authorizeScope(c, principal); err != nil {
+				reject(c, err)
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(models.ContextWithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// resolvePrincipal authenticates the API key or bearer token of a request
+func resolvePrincipal(c *gin.Context, tokens *usecases.AuthUseCase, keys *usecases.APIKeyUseCase) (models.Principal, error) {
+	apiKey := strings.TrimSpace(c.GetHeader(APIKeyHeader))
+	authorization := c.GetHeader("Authorization")
+
+	switch {
+	case apiKey != "" && authorization != "":
+		return models.Principal{}, fmt.Errorf("%w: send either a bearer token or an API key, not both", common.ErrInvalidInput)
+	case apiKey != "":
+		return keys.AuthenticateAPIKey(c.Request.Context(), apiKey)
+	case authorization != "":
+		token, ok := bearerToken(authorization)
+		if !ok {
+			return models.Principal{}, fmt.Errorf("%w: missing bearer token", common.ErrUnauthorized)
+		}
+		return tokens.Authenticate(c.Request.Context(), token)
+	default:
+		return models.Principal{}, errNoCredentials
+	}
+}
+
+// authorizeScope checks an API key may call the matched route
+func authorizeScope(c *gin.Context, principal models.Principal) error {
+	scope, ok := routeScope(c.Request.Method, c.FullPath())
+	if !ok {
+		return fmt.Errorf("%w: route is not available to API keys", common.ErrForbidden)
+	}
+	if !principal.HasScope(scope) {
+		return fmt.Errorf("%w: API key lacks scope %s", common.ErrForbidden, scope)
+	}
+	return nil
+}
+
+// bearerToken extracts the token from an Authorization header value
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// reject aborts the request, adding a bearer challenge to 401s and Retry-After to rate-limited requests
+func reject(c *gin.Context, err error) {
+	if errors.Is(err, common.ErrUnauthorized) {
+		c.Header("WWW-Authenticate", `Bearer realm="lavalo-api"`)
+	}
+
+	var limited *usecases.RateLimitError
+	if errors.As(err, &limited) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+	}
+
+	common.RespondError(c, err)
+	c.Abort()
+}
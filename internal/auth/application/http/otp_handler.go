@@ -0,0 +1,106 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// OTPHandler handles HTTP requests for passwordless login with one-time codes
+type OTPHandler struct {
+	useCase *usecases.OTPUseCase
+}
+
+// NewOTPHandler creates a new one-time code handler
+func NewOTPHandler(useCase *usecases.OTPUseCase) *OTPHandler {
+	return &OTPHandler{
+		useCase: useCase,
+	}
+}
+
+// requestCodeRequest is the request body for POST /auth/otp/request
+type requestCodeRequest struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// verifyCodeRequest is the request body for POST /auth/otp/verify
+type verifyCodeRequest struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// RegisterRoutes registers the public one-time code routes
+func (h *OTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	otp := rg.Group("/auth/otp")
+	{
+		otp.POST("/request", h.Request)
+		otp.POST("/verify", h.Verify)
+	}
+}
+
+// Request sends a login code
+// @Summary Request login code
+// @Description Sends a 6-digit code to the email (or by SMS to the phone) of a registered client.
+// @Description Responds 202 whether or not the destination is registered. No new code is sent within a minute of the last one.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 202
+// @Failure 400 {object} common.APIError "Neither or both of email and phone"
+// @Router /api/v1/auth/otp/request [post]
+func (h *OTPHandler) Request(c *gin.Context) {
+	var req requestCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	err := h.useCase.RequestCode(c.Request.Context(), usecases.CodeDestination{
+		Email: req.Email,
+		Phone: req.Phone,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "if the destination is registered, a code is on its way",
+	})
+}
+
+// Verify exchanges a login code for a token pair
+// @Summary Verify login code
+// @Description Send the same email or phone the code was requested for. Codes work once and lock after too many wrong guesses.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TokenPair
+// @Failure 401 {object} common.APIError "Wrong, expired, used or locked code"
+// @Router /api/v1/auth/otp/verify [post]
+func (h *OTPHandler) Verify(c *gin.Context) {
+	var req verifyCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	tokens, err := h.useCase.VerifyCode(c.Request.Context(), usecases.CodeDestination{
+		Email: req.Email,
+		Phone: req.Phone,
+	}, req.Code)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tokens,
+	})
+}
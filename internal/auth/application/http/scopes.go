@@ -0,0 +1,39 @@
+package http
+
+import "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+
+// routeScopes maps each route API keys may call to the scope it needs.
+// Routes missing here are closed to API keys, so new routes stay staff- and client-only
+// until they are listed.
+var routeScopes = map[string]models.Scope{
+	"GET /api/v1/availability": models.ScopeAvailabilityRead,
+
+	"GET /api/v1/services":     models.ScopeServicesRead,
+	"GET /api/v1/services/:id": models.ScopeServicesRead,
+
+	"GET /api/v1/reservations":                 models.ScopeReservationsRead,
+	"GET /api/v1/reservations/:id":             models.ScopeReservationsRead,
+	"GET /api/v1/reservations/:id/history":     models.ScopeReservationsRead,
+	"GET /api/v1/reservations/:id/reschedules": models.ScopeReservationsRead,
+	"GET /api/v1/reservations/series/:id":      models.ScopeReservationsRead,
+
+	"POST /api/v1/reservations":                   models.ScopeReservationsWrite,
+	"POST /api/v1/reservations/holds":             models.ScopeReservationsWrite,
+	"POST /api/v1/reservations/:id/cancel":        models.ScopeReservationsWrite,
+	"PUT /api/v1/reservations/:id/reschedule":     models.ScopeReservationsWrite,
+	"POST /api/v1/reservations/series":            models.ScopeReservationsWrite,
+	"POST /api/v1/reservations/series/:id/cancel": models.ScopeReservationsWrite,
+
+	"GET /api/v1/vehicles":     models.ScopeVehiclesRead,
+	"GET /api/v1/vehicles/:id": models.ScopeVehiclesRead,
+
+	"POST /api/v1/vehicles":       models.ScopeVehiclesWrite,
+	"PUT /api/v1/vehicles/:id":    models.ScopeVehiclesWrite,
+	"DELETE /api/v1/vehicles/:id": models.ScopeVehiclesWrite,
+}
+
+// routeScope returns the scope an API key needs to call method on the route pattern path
+func routeScope(method, path string) (models.Scope, bool) {
+	scope, ok := routeScopes[method+" "+path]
+	return scope, ok
+}
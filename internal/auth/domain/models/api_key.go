@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+)
+
+// Scope is a group of routes an API key may call
+type Scope string
+
+const (
+	// ScopeAvailabilityRead checks free slots
+	ScopeAvailabilityRead Scope = "availability:read"
+	// ScopeServicesRead reads the service catalog and prices
+	ScopeServicesRead Scope = "services:read"
+	// ScopeReservationsRead reads the key owner's reservations and series
+	ScopeReservationsRead Scope = "reservations:read"
+	// ScopeReservationsWrite books, holds, reschedules and cancels the key owner's reservations
+	ScopeReservationsWrite Scope = "reservations:write"
+	// ScopeVehiclesRead reads the key owner's vehicles
+	ScopeVehiclesRead Scope = "vehicles:read"
+	// ScopeVehiclesWrite registers, edits and removes the key owner's vehicles
+	ScopeVehiclesWrite Scope = "vehicles:write"
+)
+
+// scopes lists every known scope
+var scopes = map[Scope]bool{
+	ScopeAvailabilityRead:  true,
+	ScopeServicesRead:      true,
+	ScopeReservationsRead:  true,
+	ScopeReservationsWrite: true,
+	ScopeVehiclesRead:      true,
+	ScopeVehiclesWrite:     true,
+}
+
+// IsValid returns true for a known scope
+func (s Scope) IsValid() bool {
+	return scopes[s]
+}
+
+// APIKey lets a partner system (a dealership, a fleet manager) call the API on behalf of the
+// client that owns it, limited to its scopes and rate. Only the SHA-256 hash of the key is stored;
+// Prefix is kept in clear so the key can be recognised in listings.
+type APIKey struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	ClientID uint   `gorm:"index;not null" json:"client_id"`
+	Name     string `gorm:"type:varchar(100);not null" json:"name"`
+	// Prefix is the start of the key, e.g. "lvl_AbCd1234"
+	Prefix  string  `gorm:"type:varchar(20);not null" json:"prefix"`
+	KeyHash string  `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Scopes  []Scope `gorm:"serializer:json;type:text;not null" json:"scopes"`
+	// RateLimitPerMinute caps the requests the key may make in each minute
+	RateLimitPerMinute int        `gorm:"not null" json:"rate_limit_per_minute"`
+	LastUsedAt         *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	// Client declares the foreign key from ClientID; it is never loaded
+	Client *clientModels.Client `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (k *APIKey) BeforeSave(tx *gorm.DB) error {
+	if k.LastUsedAt != nil {
+		lastUsedAt := k.LastUsedAt.UTC()
+		k.LastUsedAt = &lastUsedAt
+	}
+	if k.RevokedAt != nil {
+		revokedAt := k.RevokedAt.UTC()
+		k.RevokedAt = &revokedAt
+	}
+	return nil
+}
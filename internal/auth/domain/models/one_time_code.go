@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+)
+
+// CodeChannel is how a one-time code reaches the client
+type CodeChannel string
+
+const (
+	CodeChannelEmail CodeChannel = "email"
+	CodeChannelSMS   CodeChannel = "sms"
+)
+
+// OneTimeCode is a short numeric code sent to a client's email or phone to log in without a password.
+// Only the bcrypt hash of the code is stored. A code is single-use, expires quickly and locks
+// after too many wrong guesses; requesting a new code replaces any the client has pending.
+type OneTimeCode struct {
+	ID       uint        `gorm:"primaryKey" json:"id"`
+	ClientID uint        `gorm:"index;not null" json:"client_id"`
+	Channel  CodeChannel `gorm:"type:varchar(10);not null" json:"channel"`
+	// Destination is the email or phone the code was sent to
+	Destination string     `gorm:"type:varchar(255);not null" json:"destination"`
+	CodeHash    string     `gorm:"type:varchar(100);not null" json:"-"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	ConsumedAt  *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// Client declares the foreign key from ClientID; it is never loaded
+	Client *clientModels.Client `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for OneTimeCode
+func (OneTimeCode) TableName() string {
+	return "one_time_codes"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (c *OneTimeCode) BeforeSave(tx *gorm.DB) error {
+	c.ExpiresAt = c.ExpiresAt.UTC()
+	if c.ConsumedAt != nil {
+		consumedAt := c.ConsumedAt.UTC()
+		c.ConsumedAt = &consumedAt
+	}
+	return nil
+}
+
+// IsUsable returns true if the code is neither consumed, expired nor locked at now
+func (c *OneTimeCode) IsUsable(now time.Time, maxAttempts int) bool {
+	return c.ConsumedAt == nil && now.Before(c.ExpiresAt) && c.Attempts < maxAttempts
+}
+
+// CodeMessage is a one-time code to deliver to a client
+type CodeMessage struct {
+	Channel     CodeChannel
+	Destination string
+	ClientName  string
+	Code        string
+	ExpiresAt   time.Time
+}
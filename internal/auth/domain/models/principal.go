@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+)
+
+// Principal is the authenticated caller of a request: a client with an access token,
+// or a partner system with an API key acting as the client that owns it
+type Principal struct {
+	ClientID uint              `json:"client_id"`
+	Role     clientModels.Role `json:"role"`
+	// APIKeyID is set when the caller authenticated with an API key
+	APIKeyID uint `json:"api_key_id,omitempty"`
+	// Scopes limits the routes an API key may call; access tokens are not limited
+	Scopes []Scope `json:"scopes,omitempty"`
+}
+
+// IsStaff returns true if the caller may act on behalf of any client
+func (p Principal) IsStaff() bool {
+	return p.Role.AtLeast(clientModels.RoleStaff)
+}
+
+// HasScope returns true if the caller may call routes in scope
+func (p Principal) HasScope(scope Scope) bool {
+	if p.APIKeyID == 0 {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// String identifies the caller in audit fields such as a status change's changed_by
+func (p Principal) String() string {
+	if p.APIKeyID != 0 {
+		return fmt.Sprintf("apikey:%d", p.APIKeyID)
+	}
+	return fmt.Sprintf("%s:%d", p.Role, p.ClientID)
+}
+
+// principalKey is the context key for the request Principal
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the authenticated caller
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
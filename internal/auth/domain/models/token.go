@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+)
+
+// AccessClaims are the claims carried by a signed access token
+type AccessClaims struct {
+	// ClientID is the authenticated client (the JWT "sub" claim)
+	ClientID uint
+	// Role is the client's role when the token was issued
+	Role      clientModels.Role
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// RefreshToken is an opaque, single-use token that obtains a new token pair.
+// Only the SHA-256 hash of the token is stored. Using a token rotates it: the token is
+// revoked and points at its replacement, so presenting it again reveals a stolen token.
+type RefreshToken struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	ClientID     uint       `gorm:"index;not null" json:"client_id"`
+	TokenHash    string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Client declares the foreign key from ClientID; it is never loaded
+	Client *clientModels.Client `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for RefreshToken
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (t *RefreshToken) BeforeSave(tx *gorm.DB) error {
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	if t.RevokedAt != nil {
+		revokedAt := t.RevokedAt.UTC()
+		t.RevokedAt = &revokedAt
+	}
+	return nil
+}
+
+// IsUsable returns true if the token is neither revoked nor expired at now
+func (t *RefreshToken) IsUsable(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// TokenPair is returned on signup, login and refresh
+type TokenPair struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresIn is the access token lifetime in seconds
+	ExpiresIn        int       `json:"expires_in"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
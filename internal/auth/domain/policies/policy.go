@@ -0,0 +1,75 @@
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// Caller returns the authenticated caller in ctx, or common.ErrUnauthorized
+func Caller(ctx context.Context) (models.Principal, error) {
+	principal, ok := models.PrincipalFromContext(ctx)
+	if !ok {
+		return models.Principal{}, fmt.Errorf("%w: not authenticated", common.ErrUnauthorized)
+	}
+	return principal, nil
+}
+
+// RequireRole returns the caller if their role includes minimum, or common.ErrForbidden
+func RequireRole(ctx context.Context, minimum clientModels.Role) (models.Principal, error) {
+	principal, err := Caller(ctx)
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	if !principal.Role.AtLeast(minimum) {
+		return models.Principal{}, fmt.Errorf("%w: requires role %s", common.ErrForbidden, minimum)
+	}
+	return principal, nil
+}
+
+// RequireClient returns the caller if they are clientID or staff, or common.ErrForbidden.
+// Use it before reading or changing anything owned by a client.
+func RequireClient(ctx context.Context, clientID uint) (models.Principal, error) {
+	principal, err := Caller(ctx)
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	if principal.ClientID != clientID && !principal.IsStaff() {
+		return models.Principal{}, fmt.Errorf("%w: resource belongs to another client", common.ErrForbidden)
+	}
+	return principal, nil
+}
+
+// RequireOwner is RequireClient for a resource the caller asked for by ID. Customers get notFound,
+// the error an ID that does not exist gets, instead of common.ErrForbidden, so they cannot tell
+// other clients' IDs from unused ones.
+func RequireOwner(ctx context.Context, clientID uint, notFound error) (models.Principal, error) {
+	principal, err := RequireClient(ctx, clientID)
+	if errors.Is(err, common.ErrForbidden) {
+		return models.Principal{}, notFound
+	}
+	return principal, err
+}
+
+// ScopeClient resolves which client a listing is for. Customers get their own,
+// and common.ErrForbidden if requested names someone else; staff get requested, possibly 0.
+func ScopeClient(ctx context.Context, requested uint) (models.Principal, uint, error) {
+	principal, err := Caller(ctx)
+	if err != nil {
+		return models.Principal{}, 0, err
+	}
+
+	if !principal.IsStaff() {
+		if requested != 0 && requested != principal.ClientID {
+			return models.Principal{}, 0, fmt.Errorf("%w: resource belongs to another client", common.ErrForbidden)
+		}
+		return principal, principal.ClientID, nil
+	}
+	return principal, requested, nil
+}
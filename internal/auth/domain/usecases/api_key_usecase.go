@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+const (
+	// apiKeyPrefix starts every API key so leaked keys are easy to recognise
+	apiKeyPrefix = "lvl_"
+	// apiKeyBytes is the entropy of an API key
+	apiKeyBytes = 32
+	// apiKeyVisibleChars is how much of a key after apiKeyPrefix stays visible in listings
+	apiKeyVisibleChars = 8
+	// maxRateLimitPerMinute caps the rate limit an API key can be issued with
+	maxRateLimitPerMinute = 6000
+	// lastUsedResolution is how often a key's last_used_at is written while it is in use
+	lastUsedResolution = time.Minute
+)
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	// FindAll returns the keys of clientID, or every key if clientID is 0
+	FindAll(ctx context.Context, clientID uint) ([]models.APIKey, error)
+	FindByID(ctx context.Context, id uint) (*models.APIKey, error)
+	// FindByHash returns the key with the given hash, or common.ErrNotFound
+	FindByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	Create(ctx context.Context, key *models.APIKey) error
+	Revoke(ctx context.Context, id uint, now time.Time) error
+	// RevokeAllForClient revokes every unrevoked key of a client
+	RevokeAllForClient(ctx context.Context, clientID uint, now time.Time) error
+	TouchLastUsed(ctx context.Context, id uint, now time.Time) error
+}
+
+// RateLimiter counts the requests made with each API key
+type RateLimiter interface {
+	// Allow counts a request by key, returning false and how long to wait if it is over limit
+	Allow(key uint, limit int, now time.Time) (bool, time.Duration)
+}
+
+// ClientLookup finds the client an API key is issued to
+type ClientLookup interface {
+	GetClient(ctx context.Context, id uint) (*clientModels.Client, error)
+}
+
+// RateLimitError is returned when an API key is over its rate limit; it wraps common.ErrTooManyRequests
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v: API key rate limit exceeded, retry in %s", common.ErrTooManyRequests, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap lets errors.Is match common.ErrTooManyRequests
+func (e *RateLimitError) Unwrap() error {
+	return common.ErrTooManyRequests
+}
+
+// IssueAPIKeyInput holds the data required to issue an API key
+type IssueAPIKeyInput struct {
+	ClientID uint
+	Name     string
+	Scopes   []models.Scope
+	// RateLimitPerMinute defaults to the use case's default limit when 0
+	RateLimitPerMinute int
+}
+
+// APIKeyUseCase handles API key issuance and authentication
+type APIKeyUseCase struct {
+	repo             APIKeyRepository
+	clients          ClientLookup
+	limiter          RateLimiter
+	defaultRateLimit int
+	now              common.Clock
+}
+
+// NewAPIKeyUseCase creates a new API key use case.
+// Keys issued without a rate limit may make defaultRateLimit requests per minute.
+func NewAPIKeyUseCase(repo APIKeyRepository, clients ClientLookup, limiter RateLimiter, defaultRateLimit int, now common.Clock) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		repo:             repo,
+		clients:          clients,
+		limiter:          limiter,
+		defaultRateLimit: defaultRateLimit,
+		now:              now,
+	}
+}
+
+// ListAPIKeys returns the API keys of a client, or every key if clientID is 0
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context, clientID uint) ([]models.APIKey, error) {
+	keys, err := uc.repo.FindAll(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return keys, nil
+}
+
+// IssueAPIKey creates an API key for a client. The key value is returned only here;
+// afterwards only its prefix can be seen.
+func (uc *APIKeyUseCase) IssueAPIKey(ctx context.Context, input IssueAPIKeyInput) (*models.APIKey, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", common.ErrInvalidInput)
+	}
+
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rateLimit := input.RateLimitPerMinute
+	if rateLimit == 0 {
+		rateLimit = uc.defaultRateLimit
+	}
+	if rateLimit < 1 || rateLimit > maxRateLimitPerMinute {
+		return nil, "", fmt.Errorf("%w: rate_limit_per_minute must be between 1 and %d", common.ErrInvalidInput, maxRateLimitPerMinute)
+	}
+
+	if _, err := uc.clients.GetClient(ctx, input.ClientID); err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, "", fmt.Errorf("%w: client %d does not exist", common.ErrInvalidInput, input.ClientID)
+		}
+		return nil, "", err
+	}
+
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	value := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	key := &models.APIKey{
+		ClientID:           input.ClientID,
+		Name:               name,
+		Prefix:             value[:len(apiKeyPrefix)+apiKeyVisibleChars],
+		KeyHash:            hashToken(value),
+		Scopes:             scopes,
+		RateLimitPerMinute: rateLimit,
+	}
+	if err := uc.repo.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return key, value, nil
+}
+
+// RevokeAPIKey stops an API key from authenticating. Revoking twice is a no-op.
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id uint) (*models.APIKey, error) {
+	key, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: API key %d", common.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if key.RevokedAt != nil {
+		return key, nil
+	}
+
+	now := uc.now()
+	if err := uc.repo.Revoke(ctx, id, now); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	key.RevokedAt = &now
+	return key, nil
+}
+
+// ClientDeleted revokes the API keys of a deleted client. It implements
+// the clients' ClientDeletionListener, logging failures instead of returning them.
+func (uc *APIKeyUseCase) ClientDeleted(ctx context.Context, clientID uint) {
+	if err := uc.repo.RevokeAllForClient(ctx, clientID, uc.now()); err != nil {
+		common.Logger.Error("Failed to revoke API keys of deleted client", zap.Uint("client_id", clientID), zap.Error(err))
+	}
+}
+
+// AuthenticateAPIKey validates an API key, counts the request against its rate limit and
+// returns the caller it identifies: the owning client as a customer, limited to the key's scopes.
+// Returns a *RateLimitError if the key is over its limit.
+func (uc *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, value string) (models.Principal, error) {
+	if !strings.HasPrefix(value, apiKeyPrefix) {
+		return models.Principal{}, fmt.Errorf("%w: invalid API key", common.ErrUnauthorized)
+	}
+
+	key, err := uc.repo.FindByHash(ctx, hashToken(value))
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return models.Principal{}, fmt.Errorf("%w: invalid API key", common.ErrUnauthorized)
+		}
+		return models.Principal{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	if key.RevokedAt != nil {
+		return models.Principal{}, fmt.Errorf("%w: API key was revoked", common.ErrUnauthorized)
+	}
+
+	// Deleting a client revokes its keys, but a key must not outlive its client if that failed
+	if _, err := uc.clients.GetClient(ctx, key.ClientID); err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return models.Principal{}, fmt.Errorf("%w: API key's client was deleted", common.ErrUnauthorized)
+		}
+		return models.Principal{}, err
+	}
+
+	now := uc.now()
+	if ok, retryAfter := uc.limiter.Allow(key.ID, key.RateLimitPerMinute, now); !ok {
+		return models.Principal{}, &RateLimitError{RetryAfter: retryAfter}
+	}
+
+	// Busy keys would otherwise write on every request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := uc.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return models.Principal{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+	}
+
+	return models.Principal{
+		ClientID: key.ClientID,
+		Role:     clientModels.RoleCustomer,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// normalizeScopes validates scopes and drops duplicates
+func normalizeScopes(scopes []models.Scope) ([]models.Scope, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", common.ErrInvalidInput)
+	}
+
+	seen := make(map[models.Scope]bool, len(scopes))
+	normalized := make([]models.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("%w: unknown scope %q", common.ErrInvalidInput, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
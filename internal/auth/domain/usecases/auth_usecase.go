@@ -0,0 +1,297 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+const (
+	// minPasswordLength is the shortest password accepted on signup
+	minPasswordLength = 8
+	// maxPasswordLength is bcrypt's input limit in bytes
+	maxPasswordLength = 72
+
+	// refreshTokenBytes is the entropy of a refresh token
+	refreshTokenBytes = 32
+
+	tokenTypeBearer = "Bearer"
+)
+
+// ClientAccounts registers and looks up the clients that authenticate
+type ClientAccounts interface {
+	RegisterClient(ctx context.Context, input clientUsecases.ClientInput, passwordHash string) (*clientModels.Client, error)
+	GetClient(ctx context.Context, id uint) (*clientModels.Client, error)
+	GetClientByEmail(ctx context.Context, email string) (*clientModels.Client, error)
+	GetClientByPhone(ctx context.Context, phone string) (*clientModels.Client, error)
+}
+
+// RefreshTokenRepository defines the interface for refresh token data access
+type RefreshTokenRepository interface {
+	// FindByHash returns the token with the given hash, or common.ErrNotFound
+	FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	Create(ctx context.Context, token *models.RefreshToken) error
+	// Rotate revokes oldID in favour of next; returns common.ErrConflict if oldID was already revoked
+	Rotate(ctx context.Context, oldID uint, next *models.RefreshToken, now time.Time) error
+	Revoke(ctx context.Context, id uint, now time.Time) error
+	RevokeAllForClient(ctx context.Context, clientID uint, now time.Time) error
+}
+
+// TokenSigner signs and verifies access tokens
+type TokenSigner interface {
+	Sign(claims models.AccessClaims) (string, error)
+	// Verify checks the token signature; returns common.ErrUnauthorized if invalid
+	Verify(token string) (models.AccessClaims, error)
+}
+
+// SignupInput holds the data required to sign up
+type SignupInput struct {
+	Client   clientUsecases.ClientInput
+	Password string
+}
+
+// AuthUseCase handles signup, login and token business logic
+type AuthUseCase struct {
+	clients    ClientAccounts
+	tokens     RefreshTokenRepository
+	signer     TokenSigner
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        common.Clock
+
+	// dummyHash is compared against on unknown emails so login takes as long as for known ones
+	dummyHash []byte
+}
+
+// NewAuthUseCase creates a new auth use case.
+// Access tokens expire accessTTL after issue and refresh tokens refreshTTL after issue.
+func NewAuthUseCase(clients ClientAccounts, tokens RefreshTokenRepository, signer TokenSigner, accessTTL, refreshTTL time.Duration, now common.Clock) *AuthUseCase {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("lavalo-dummy-password"), bcrypt.DefaultCost)
+	return &AuthUseCase{
+		clients:    clients,
+		tokens:     tokens,
+		signer:     signer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        now,
+		dummyHash:  dummyHash,
+	}
+}
+
+// Signup registers a client with a password and logs them in
+func (uc *AuthUseCase) Signup(ctx context.Context, input SignupInput) (*clientModels.Client, *models.TokenPair, error) {
+	if len(input.Password) < minPasswordLength || len(input.Password) > maxPasswordLength {
+		return nil, nil, fmt.Errorf("%w: password must be between %d and %d characters", common.ErrInvalidInput, minPasswordLength, maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	client, err := uc.clients.RegisterClient(ctx, input.Client, string(hash))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := uc.issue(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, pair, nil
+}
+
+// Login exchanges an email and password for a token pair.
+// Unknown emails and wrong passwords fail alike with common.ErrUnauthorized.
+func (uc *AuthUseCase) Login(ctx context.Context, email, password string) (*models.TokenPair, error) {
+	client, err := uc.clients.GetClientByEmail(ctx, email)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return nil, err
+	}
+
+	hash := uc.dummyHash
+	if client != nil && client.PasswordHash != "" {
+		hash = []byte(client.PasswordHash)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || client == nil || client.PasswordHash == "" {
+		return nil, fmt.Errorf("%w: invalid email or password", common.ErrUnauthorized)
+	}
+
+	return uc.issue(ctx, client)
+}
+
+// Refresh rotates a refresh token, returning a new token pair.
+// Presenting a token that was already rotated revokes every token of its client,
+// since either the client or an attacker holds a stolen copy.
+// The new access token carries the client's current role.
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	current, err := uc.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	if current.RevokedAt != nil {
+		if err := uc.tokens.RevokeAllForClient(ctx, current.ClientID, now); err != nil {
+			return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+		return nil, fmt.Errorf("%w: refresh token was already used", common.ErrUnauthorized)
+	}
+	if !current.IsUsable(now) {
+		return nil, fmt.Errorf("%w: refresh token expired", common.ErrUnauthorized)
+	}
+
+	client, err := uc.clients.GetClient(ctx, current.ClientID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: client no longer exists", common.ErrUnauthorized)
+		}
+		return nil, err
+	}
+
+	value, next, err := uc.newRefreshToken(client.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.tokens.Rotate(ctx, current.ID, next, now); err != nil {
+		// Another request rotated the same token first
+		if errors.Is(err, common.ErrConflict) {
+			return nil, fmt.Errorf("%w: refresh token was already used", common.ErrUnauthorized)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return uc.pair(client, value, next, now)
+}
+
+// Logout revokes a refresh token. Access tokens stay valid until they expire.
+func (uc *AuthUseCase) Logout(ctx context.Context, refreshToken string) error {
+	current, err := uc.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.tokens.Revoke(ctx, current.ID, uc.now()); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// Authenticate validates an access token and returns the caller it identifies
+func (uc *AuthUseCase) Authenticate(ctx context.Context, accessToken string) (models.Principal, error) {
+	claims, err := uc.signer.Verify(accessToken)
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	if !uc.now().Before(claims.ExpiresAt) {
+		return models.Principal{}, fmt.Errorf("%w: access token expired", common.ErrUnauthorized)
+	}
+
+	return models.Principal{ClientID: claims.ClientID, Role: claims.Role}, nil
+}
+
+// CurrentClient returns the client authenticated in ctx
+func (uc *AuthUseCase) CurrentClient(ctx context.Context) (*clientModels.Client, error) {
+	principal, ok := models.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: not authenticated", common.ErrUnauthorized)
+	}
+
+	client, err := uc.clients.GetClient(ctx, principal.ClientID)
+	if err != nil {
+		// The client was deleted after the token was issued
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: client no longer exists", common.ErrUnauthorized)
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// issue creates a new token pair for a client
+func (uc *AuthUseCase) issue(ctx context.Context, client *clientModels.Client) (*models.TokenPair, error) {
+	now := uc.now()
+	value, token, err := uc.newRefreshToken(client.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.tokens.Create(ctx, token); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return uc.pair(client, value, token, now)
+}
+
+// pair signs an access token and bundles it with the refresh token value
+func (uc *AuthUseCase) pair(client *clientModels.Client, refreshValue string, refresh *models.RefreshToken, now time.Time) (*models.TokenPair, error) {
+	access, err := uc.signer.Sign(models.AccessClaims{
+		ClientID:  client.ID,
+		Role:      client.Role,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(uc.accessTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return &models.TokenPair{
+		AccessToken:      access,
+		TokenType:        tokenTypeBearer,
+		ExpiresIn:        int(uc.accessTTL.Seconds()),
+		RefreshToken:     refreshValue,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+// newRefreshToken generates a refresh token value and its unsaved record
+func (uc *AuthUseCase) newRefreshToken(clientID uint, now time.Time) (string, *models.RefreshToken, error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	value := base64.RawURLEncoding.EncodeToString(raw)
+
+	return value, &models.RefreshToken{
+		ClientID:  clientID,
+		TokenHash: hashToken(value),
+		ExpiresAt: now.Add(uc.refreshTTL),
+	}, nil
+}
+
+// findRefreshToken looks up a refresh token by value
+func (uc *AuthUseCase) findRefreshToken(ctx context.Context, value string) (*models.RefreshToken, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w: refresh_token is required", common.ErrInvalidInput)
+	}
+
+	token, err := uc.tokens.FindByHash(ctx, hashToken(value))
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown refresh token", common.ErrUnauthorized)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return token, nil
+}
+
+// hashToken returns the hex SHA-256 of a token value
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,253 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+const (
+	// codeDigits is the length of a one-time code
+	codeDigits = 6
+	// codeSpace is the number of distinct codes, 10^codeDigits
+	codeSpace = 1_000_000
+	// codeResendInterval is how long a client waits before requesting another code on the same channel
+	codeResendInterval = time.Minute
+)
+
+// OneTimeCodeRepository defines the interface for one-time code data access
+type OneTimeCodeRepository interface {
+	// FindLatest returns the newest code of a client on a channel, or common.ErrNotFound
+	FindLatest(ctx context.Context, clientID uint, channel models.CodeChannel) (*models.OneTimeCode, error)
+	// Replace creates code, discarding the client's pending codes on the same channel
+	Replace(ctx context.Context, code *models.OneTimeCode) error
+	// RecordAttempt returns common.ErrConflict if the code is consumed or out of attempts
+	RecordAttempt(ctx context.Context, id uint, maxAttempts int) error
+	// Consume returns common.ErrConflict if the code was already consumed
+	Consume(ctx context.Context, id uint, now time.Time) error
+}
+
+// CodeSender delivers one-time codes to clients
+type CodeSender interface {
+	SendCode(ctx context.Context, message models.CodeMessage) error
+}
+
+// CodeDestination identifies who a code is for: set exactly one of Email or Phone
+type CodeDestination struct {
+	Email string
+	Phone string
+}
+
+// OTPUseCase handles passwordless login with one-time codes
+type OTPUseCase struct {
+	auth        *AuthUseCase
+	codes       OneTimeCodeRepository
+	sender      CodeSender
+	ttl         time.Duration
+	maxAttempts int
+}
+
+// NewOTPUseCase creates a new one-time code use case.
+// Codes expire ttl after they are sent and lock after maxAttempts wrong guesses;
+// successful logins get their tokens from auth.
+func NewOTPUseCase(auth *AuthUseCase, codes OneTimeCodeRepository, sender CodeSender, ttl time.Duration, maxAttempts int) *OTPUseCase {
+	return &OTPUseCase{
+		auth:        auth,
+		codes:       codes,
+		sender:      sender,
+		ttl:         ttl,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// RequestCode sends a new login code to a client's email or phone.
+// Unknown destinations, and clients who were sent a code less than codeResendInterval ago, succeed
+// without sending anything, so the response does not reveal who is registered.
+func (uc *OTPUseCase) RequestCode(ctx context.Context, destination CodeDestination) error {
+	channel, address, err := destination.resolve()
+	if err != nil {
+		return err
+	}
+
+	client, err := uc.findClient(ctx, channel, address)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) || errors.Is(err, common.ErrConflict) {
+			common.Logger.Info("Login code requested for an unknown or ambiguous destination",
+				zap.String("channel", string(channel)), zap.Error(err))
+			return nil
+		}
+		return err
+	}
+
+	// Send to the address as registered, not as typed
+	address = client.Email
+	if channel == models.CodeChannelSMS {
+		address = client.Phone
+	}
+
+	now := uc.auth.now()
+	latest, err := uc.codes.FindLatest(ctx, client.ID, channel)
+	if err != nil && !errors.Is(err, common.ErrNotFound) {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	// Answer throttled requests like unknown destinations, or the throttle would reveal who is registered
+	if latest != nil && now.Sub(latest.CreatedAt) < codeResendInterval {
+		common.Logger.Info("Login code requested again within the resend interval",
+			zap.Uint("client_id", client.ID), zap.String("channel", string(channel)))
+		return nil
+	}
+
+	value, err := generateCode()
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(value), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	code := &models.OneTimeCode{
+		ClientID:    client.ID,
+		Channel:     channel,
+		Destination: address,
+		CodeHash:    string(hash),
+		ExpiresAt:   now.Add(uc.ttl),
+		CreatedAt:   now,
+	}
+	if err := uc.codes.Replace(ctx, code); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if err := uc.sender.SendCode(ctx, models.CodeMessage{
+		Channel:     channel,
+		Destination: address,
+		ClientName:  client.Name,
+		Code:        value,
+		ExpiresAt:   code.ExpiresAt,
+	}); err != nil {
+		return fmt.Errorf("%w: sending code: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// VerifyCode exchanges the latest code sent to a destination for a token pair.
+// Every guess counts against the code's attempts, and a correct code works once.
+func (uc *OTPUseCase) VerifyCode(ctx context.Context, destination CodeDestination, value string) (*models.TokenPair, error) {
+	channel, address, err := destination.resolve()
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if !isCode(value) {
+		return nil, fmt.Errorf("%w: code must have %d digits", common.ErrInvalidInput, codeDigits)
+	}
+
+	client, code, err := uc.findUsableCode(ctx, channel, address)
+	if err != nil {
+		// Compare anyway so unknown destinations take as long as wrong codes
+		_ = bcrypt.CompareHashAndPassword(uc.auth.dummyHash, []byte(value))
+		return nil, err
+	}
+
+	if err := uc.codes.RecordAttempt(ctx, code.ID, uc.maxAttempts); err != nil {
+		if errors.Is(err, common.ErrConflict) {
+			return nil, fmt.Errorf("%w: code is no longer valid, request a new one", common.ErrUnauthorized)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(code.CodeHash), []byte(value)); err != nil {
+		return nil, fmt.Errorf("%w: invalid code, %d attempts left", common.ErrUnauthorized, uc.maxAttempts-code.Attempts-1)
+	}
+
+	if err := uc.codes.Consume(ctx, code.ID, uc.auth.now()); err != nil {
+		if errors.Is(err, common.ErrConflict) {
+			return nil, fmt.Errorf("%w: code was already used", common.ErrUnauthorized)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return uc.auth.issue(ctx, client)
+}
+
+// findUsableCode returns the client at a destination and their latest code, if it can still be guessed
+func (uc *OTPUseCase) findUsableCode(ctx context.Context, channel models.CodeChannel, address string) (*clientModels.Client, *models.OneTimeCode, error) {
+	client, err := uc.findClient(ctx, channel, address)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) || errors.Is(err, common.ErrConflict) {
+			return nil, nil, fmt.Errorf("%w: invalid or expired code", common.ErrUnauthorized)
+		}
+		return nil, nil, err
+	}
+
+	code, err := uc.codes.FindLatest(ctx, client.ID, channel)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, nil, fmt.Errorf("%w: invalid or expired code", common.ErrUnauthorized)
+		}
+		return nil, nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if !code.IsUsable(uc.auth.now(), uc.maxAttempts) {
+		return nil, nil, fmt.Errorf("%w: code is no longer valid, request a new one", common.ErrUnauthorized)
+	}
+	return client, code, nil
+}
+
+// findClient looks up the client registered with an email or phone
+func (uc *OTPUseCase) findClient(ctx context.Context, channel models.CodeChannel, address string) (*clientModels.Client, error) {
+	if channel == models.CodeChannelSMS {
+		return uc.auth.clients.GetClientByPhone(ctx, address)
+	}
+	return uc.auth.clients.GetClientByEmail(ctx, address)
+}
+
+// resolve returns the channel and address of a destination
+func (d CodeDestination) resolve() (models.CodeChannel, string, error) {
+	email := strings.ToLower(strings.TrimSpace(d.Email))
+	phone := strings.TrimSpace(d.Phone)
+
+	switch {
+	case email != "" && phone != "":
+		return "", "", fmt.Errorf("%w: send either email or phone, not both", common.ErrInvalidInput)
+	case email != "":
+		return models.CodeChannelEmail, email, nil
+	case phone != "":
+		return models.CodeChannelSMS, phone, nil
+	default:
+		return "", "", fmt.Errorf("%w: email or phone is required", common.ErrInvalidInput)
+	}
+}
+
+// generateCode returns a uniformly random numeric code of codeDigits digits
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(codeSpace))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
+
+// isCode reports whether s looks like a one-time code
+func isCode(s string) bool {
+	if len(s) != codeDigits {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
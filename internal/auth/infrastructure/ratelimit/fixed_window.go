@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// FixedWindowLimiter counts requests per API key in fixed windows, e.g. per clock minute.
+// Counters live in memory, so each API process enforces the limit on its own and restarts reset it.
+type FixedWindowLimiter struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[uint]counter
+}
+
+// counter is the request count of a key in the window starting at start
+type counter struct {
+	start time.Time
+	count int
+}
+
+// NewFixedWindowLimiter creates a limiter whose limits apply per window
+func NewFixedWindowLimiter(window time.Duration) *FixedWindowLimiter {
+	return &FixedWindowLimiter{
+		window:  window,
+		windows: make(map[uint]counter),
+	}
+}
+
+// Allow counts a request by key at now. If the key already made limit requests in the
+// current window it returns false and how long until the next window starts.
+func (l *FixedWindowLimiter) Allow(key uint, limit int, now time.Time) (bool, time.Duration) {
+	start := now.Truncate(l.window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.windows[key]
+	if !current.start.Equal(start) {
+		current = counter{start: start}
+	}
+
+	if current.count >= limit {
+		return false, start.Add(l.window).Sub(now)
+	}
+
+	current.count++
+	l.windows[key] = current
+	return true, 0
+}
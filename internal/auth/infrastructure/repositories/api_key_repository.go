@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository implements the API key repository interface
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// FindAll retrieves the API keys of a client, or of every client if clientID is 0
+func (r *APIKeyRepository) FindAll(ctx context.Context, clientID uint) ([]models.APIKey, error) {
+	query := r.db.WithContext(ctx).Order("id ASC")
+	if clientID != 0 {
+		query = query.Where("client_id = ?", clientID)
+	}
+
+	var keys []models.APIKey
+	if err := query.Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// FindByID retrieves an API key by ID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByHash retrieves an API key by the hash of its value
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// Revoke revokes an API key if it is not revoked yet
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now.UTC()).Error
+}
+
+// RevokeAllForClient revokes every unrevoked API key of a client
+func (r *APIKeyRepository) RevokeAllForClient(ctx context.Context, clientID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientID).
+		Update("revoked_at", now.UTC()).Error
+}
+
+// TouchLastUsed records that an API key was used at now
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", now.UTC()).Error
+}
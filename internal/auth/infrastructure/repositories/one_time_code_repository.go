@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"gorm.io/gorm"
+)
+
+// OneTimeCodeRepository implements the one-time code repository interface
+type OneTimeCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewOneTimeCodeRepository creates a new one-time code repository
+func NewOneTimeCodeRepository(db *gorm.DB) *OneTimeCodeRepository {
+	return &OneTimeCodeRepository{
+		db: db,
+	}
+}
+
+// FindLatest retrieves the most recent code sent to a client over a channel
+func (r *OneTimeCodeRepository) FindLatest(ctx context.Context, clientID uint, channel models.CodeChannel) (*models.OneTimeCode, error) {
+	var code models.OneTimeCode
+	err := r.db.WithContext(ctx).
+		Where("client_id = ? AND channel = ?", clientID, channel).
+		Order("id DESC").
+		First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+// Replace deletes the client's unconsumed codes on the same channel and creates code in one transaction
+func (r *OneTimeCodeRepository) Replace(ctx context.Context, code *models.OneTimeCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("client_id = ? AND channel = ? AND consumed_at IS NULL", code.ClientID, code.Channel).
+			Delete(&models.OneTimeCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(code).Error
+	})
+}
+
+// RecordAttempt counts a verification attempt against a code.
+// Returns common.ErrConflict if the code was consumed or already had maxAttempts attempts.
+func (r *OneTimeCodeRepository) RecordAttempt(ctx context.Context, id uint, maxAttempts int) error {
+	result := r.db.WithContext(ctx).Model(&models.OneTimeCode{}).
+		Where("id = ? AND consumed_at IS NULL AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: code %d is used up", common.ErrConflict, id)
+	}
+	return nil
+}
+
+// Consume marks a code as used.
+// Returns common.ErrConflict if it was already consumed.
+func (r *OneTimeCodeRepository) Consume(ctx context.Context, id uint, now time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.OneTimeCode{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", now.UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: code %d was already used", common.ErrConflict, id)
+	}
+	return nil
+}
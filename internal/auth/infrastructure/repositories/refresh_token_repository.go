@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository implements the refresh token repository interface
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+// FindByHash retrieves a refresh token by the hash of its value
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Create creates a new refresh token
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// Rotate revokes the token oldID and creates next as its replacement in one transaction.
+// Returns common.ErrConflict if oldID was already revoked.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldID uint, next *models.RefreshToken, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Updates(map[string]interface{}{
+				"revoked_at":     now.UTC(),
+				"replaced_by_id": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: refresh token %d was already used", common.ErrConflict, oldID)
+		}
+		return nil
+	})
+}
+
+// Revoke revokes a refresh token if it is not revoked yet
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now.UTC()).Error
+}
+
+// RevokeAllForClient revokes every unrevoked refresh token of a client
+func (r *RefreshTokenRepository) RevokeAllForClient(ctx context.Context, clientID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientID).
+		Update("revoked_at", now.UTC()).Error
+}
@@ -0,0 +1,63 @@
+package senders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+)
+
+// FileSender appends login codes to a local outbox file, one JSON object per line.
+// It stands in for email/SMS delivery in development and tests, where the outbox
+// can be tailed or parsed to find the code a client would have received.
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+// OutboxMessage is a line of the outbox file
+type OutboxMessage struct {
+	Channel     models.CodeChannel `json:"channel"`
+	Destination string             `json:"destination"`
+	ClientName  string             `json:"client_name"`
+	Code        string             `json:"code"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+}
+
+// NewFileSender creates a sender that appends to the file at path, creating it if needed
+func NewFileSender(path string) *FileSender {
+	return &FileSender{
+		path: path,
+	}
+}
+
+// SendCode appends a login code to the outbox
+func (s *FileSender) SendCode(ctx context.Context, message models.CodeMessage) error {
+	line, err := json.Marshal(OutboxMessage{
+		Channel:     message.Channel,
+		Destination: message.Destination,
+		ClientName:  message.ClientName,
+		Code:        message.Code,
+		ExpiresAt:   message.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening outbox: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("writing outbox: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,31 @@
+package senders
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// LogSender writes login codes to the application log.
+// It stands in for email/SMS delivery during development; never use it in production,
+// since anyone reading the logs can log in as any client.
+type LogSender struct{}
+
+// NewLogSender creates a new log sender
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// SendCode logs a login code
+func (s *LogSender) SendCode(ctx context.Context, message models.CodeMessage) error {
+	common.Logger.Info("Login code issued",
+		zap.String("channel", string(message.Channel)),
+		zap.String("destination", message.Destination),
+		zap.String("code", message.Code),
+		zap.Time("expires_at", message.ExpiresAt),
+	)
+	return nil
+}
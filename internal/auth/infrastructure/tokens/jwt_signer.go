@@ -0,0 +1,115 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// jwtHeader is the only header JWTSigner issues and accepts
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// jwtClaims is the JSON payload of an access token
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Issuer    string `json:"iss"`
+	Role      string `json:"role,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// JWTSigner signs and verifies HS256 JSON Web Tokens
+type JWTSigner struct {
+	secret []byte
+	issuer string
+}
+
+// NewJWTSigner creates a signer using secret as the HMAC key.
+// Tokens carry issuer as "iss" and tokens from other issuers are rejected.
+func NewJWTSigner(secret []byte, issuer string) *JWTSigner {
+	return &JWTSigner{
+		secret: secret,
+		issuer: issuer,
+	}
+}
+
+// Sign returns a signed token for claims
+func (s *JWTSigner) Sign(claims models.AccessClaims) (string, error) {
+	payload, err := json.Marshal(jwtClaims{
+		Subject:   strconv.FormatUint(uint64(claims.ClientID), 10),
+		Issuer:    s.issuer,
+		Role:      string(claims.Role),
+		IssuedAt:  claims.IssuedAt.Unix(),
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + s.signature(unsigned), nil
+}
+
+// Verify checks the signature and issuer of token and returns its claims.
+// Expiry is left to the caller. Returns common.ErrUnauthorized for any invalid token.
+func (s *JWTSigner) Verify(token string) (models.AccessClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return models.AccessClaims{}, fmt.Errorf("%w: malformed access token", common.ErrUnauthorized)
+	}
+
+	expected := s.signature(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return models.AccessClaims{}, fmt.Errorf("%w: invalid access token signature", common.ErrUnauthorized)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return models.AccessClaims{}, fmt.Errorf("%w: malformed access token", common.ErrUnauthorized)
+	}
+
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return models.AccessClaims{}, fmt.Errorf("%w: malformed access token", common.ErrUnauthorized)
+	}
+	if claims.Issuer != s.issuer {
+		return models.AccessClaims{}, fmt.Errorf("%w: access token issued by %q", common.ErrUnauthorized, claims.Issuer)
+	}
+
+	clientID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || clientID == 0 {
+		return models.AccessClaims{}, fmt.Errorf("%w: invalid access token subject", common.ErrUnauthorized)
+	}
+
+	// Tokens issued before roles existed belong to customers
+	role := clientModels.Role(claims.Role)
+	if role == "" {
+		role = clientModels.RoleCustomer
+	}
+	if !role.IsValid() {
+		return models.AccessClaims{}, fmt.Errorf("%w: invalid access token role", common.ErrUnauthorized)
+	}
+
+	return models.AccessClaims{
+		ClientID:  uint(clientID),
+		Role:      role,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// signature returns the base64url HMAC-SHA256 of unsigned
+func (s *JWTSigner) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,235 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// ClientHandler handles HTTP requests for clients
+type ClientHandler struct {
+	useCase *usecases.ClientUseCase
+}
+
+// NewClientHandler creates a new client handler
+func NewClientHandler(useCase *usecases.ClientUseCase) *ClientHandler {
+	return &ClientHandler{
+		useCase: useCase,
+	}
+}
+
+// clientRequest is the request body for creating and updating clients
+type clientRequest struct {
+	Name           string `json:"name" binding:"required"`
+	Email          string `json:"email" binding:"required"`
+	Phone          string `json:"phone"`
+	DocumentNumber string `json:"document_number"`
+}
+
+// toInput converts the request body to a use case input
+func (r clientRequest) toInput() usecases.ClientInput {
+	return usecases.ClientInput{
+		Name:           r.Name,
+		Email:          r.Email,
+		Phone:          r.Phone,
+		DocumentNumber: r.DocumentNumber,
+	}
+}
+
+// roleRequest is the request body for PUT /clients/:id/role
+type roleRequest struct {
+	Role models.Role `json:"role" binding:"required"`
+}
+
+// RegisterRoutes registers all client routes
+func (h *ClientHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	clients := rg.Group("/clients")
+	{
+		clients.GET("", h.List)
+		clients.GET("/:id", h.GetByID)
+		clients.POST("", h.Create)
+		clients.PUT("/:id", h.Update)
+		clients.DELETE("/:id", h.Delete)
+		clients.PUT("/:id/role", h.SetRole)
+	}
+}
+
+// List returns all clients
+// @Summary List clients
+// @Description Staff only
+// @Tags clients
+// @Produce json
+// @Success 200 {array} models.Client
+// @Failure 403 {object} common.APIError "Not staff"
+// @Router /api/v1/clients [get]
+func (h *ClientHandler) List(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), models.RoleStaff); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	clients, err := h.useCase.ListClients(c.Request.Context())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": clients,
+	})
+}
+
+// GetByID returns a client by ID
+func (h *ClientHandler) GetByID(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	client, err := h.useCase.GetClient(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": client,
+	})
+}
+
+// Create registers a client
+// @Summary Create client
+// @Description Staff register walk-in customers, who can later sign up with the same email to log in.
+// @Description Email and document number (DNI) must be unique.
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Client
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 403 {object} common.APIError "Not staff"
+// @Failure 409 {object} common.APIError "Email or document number already registered"
+// @Router /api/v1/clients [post]
+func (h *ClientHandler) Create(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), models.RoleStaff); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req clientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	client, err := h.useCase.CreateClient(c.Request.Context(), req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": client,
+	})
+}
+
+// Update replaces a client's editable fields
+func (h *ClientHandler) Update(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req clientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	client, err := h.useCase.UpdateClient(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": client,
+	})
+}
+
+// Delete removes a client
+func (h *ClientHandler) Delete(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := policies.RequireRole(c.Request.Context(), models.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.useCase.DeleteClient(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetRole changes a client's role
+// @Summary Set client role
+// @Description Admin only. The new role applies to access tokens issued from the client's next login or refresh.
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Client
+// @Failure 400 {object} common.APIError "Invalid role"
+// @Failure 403 {object} common.APIError "Not an admin"
+// @Router /api/v1/clients/{id}/role [put]
+func (h *ClientHandler) SetRole(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req roleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireRole(c.Request.Context(), models.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	client, err := h.useCase.SetRole(c.Request.Context(), id, req.Role)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": client,
+	})
+}
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// VehicleHandler handles HTTP requests for client vehicles
+type VehicleHandler struct {
+	useCase *usecases.VehicleUseCase
+}
+
+// NewVehicleHandler creates a new vehicle handler
+func NewVehicleHandler(useCase *usecases.VehicleUseCase) *VehicleHandler {
+	return &VehicleHandler{
+		useCase: useCase,
+	}
+}
+
+// vehicleRequest is the request body for creating and updating vehicles
+type vehicleRequest struct {
+	ClientID uint               `json:"client_id" binding:"required"`
+	Plate    string             `json:"plate" binding:"required"`
+	Make     string             `json:"make"`
+	Model    string             `json:"model"`
+	Color    string             `json:"color"`
+	Size     models.VehicleSize `json:"size"`
+}
+
+// toInput converts the request body to a use case input
+func (r vehicleRequest) toInput() usecases.VehicleInput {
+	return usecases.VehicleInput{
+		ClientID: r.ClientID,
+		Plate:    r.Plate,
+		Make:     r.Make,
+		Model:    r.Model,
+		Color:    r.Color,
+		Size:     r.Size,
+	}
+}
+
+// RegisterRoutes registers all vehicle routes
+func (h *VehicleHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	vehicles := rg.Group("/vehicles")
+	{
+		vehicles.GET("", h.List)
+		vehicles.GET("/:id", h.GetByID)
+		vehicles.POST("", h.Create)
+		vehicles.PUT("/:id", h.Update)
+		vehicles.DELETE("/:id", h.Delete)
+	}
+}
+
+// List returns a client's vehicles
+// @Summary List vehicles
+// @Tags vehicles
+// @Produce json
+// @Param client_id query int false "Owner; required for staff, defaults to the caller for customers"
+// @Success 200 {array} models.Vehicle
+// @Failure 400 {object} common.APIError "Missing client_id"
+// @Failure 403 {object} common.APIError "Another client's vehicles"
+// @Router /api/v1/vehicles [get]
+func (h *VehicleHandler) List(c *gin.Context) {
+	requested, err := common.ParseOptionalIDQuery(c, "client_id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	_, clientID, err := policies.ScopeClient(c.Request.Context(), requested)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	if clientID == 0 {
+		common.RespondError(c, fmt.Errorf("%w: client_id is required", common.ErrInvalidInput))
+		return
+	}
+
+	vehicles, err := h.useCase.ListClientVehicles(c.Request.Context(), clientID)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": vehicles,
+	})
+}
+
+// GetByID returns a vehicle by ID
+func (h *VehicleHandler) GetByID(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	vehicle, err := h.authorizeVehicle(c, id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": vehicle,
+	})
+}
+
+// Create registers a vehicle for a client
+// @Summary Create vehicle
+// @Description Plates are unique; size (car, suv or van, default car) sets the price of its reservations
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Vehicle
+// @Failure 400 {object} common.APIError "Invalid input or unknown client"
+// @Failure 409 {object} common.APIError "Plate already registered"
+// @Router /api/v1/vehicles [post]
+func (h *VehicleHandler) Create(c *gin.Context) {
+	var req vehicleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), req.ClientID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	vehicle, err := h.useCase.CreateVehicle(c.Request.Context(), req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": vehicle,
+	})
+}
+
+// Update replaces a vehicle's editable fields
+func (h *VehicleHandler) Update(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req vehicleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	// Customers may neither edit another client's vehicle nor hand theirs over to someone else
+	if _, err := h.authorizeVehicle(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	if _, err := policies.RequireClient(c.Request.Context(), req.ClientID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	vehicle, err := h.useCase.UpdateVehicle(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": vehicle,
+	})
+}
+
+// Delete removes a vehicle
+func (h *VehicleHandler) Delete(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := h.authorizeVehicle(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.useCase.DeleteVehicle(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// authorizeVehicle loads a vehicle the caller owns, or any vehicle for staff.
+// Other clients' vehicles are reported as not found.
+func (h *VehicleHandler) authorizeVehicle(c *gin.Context, id uint) (*models.Vehicle, error) {
+	vehicle, err := h.useCase.GetVehicle(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := policies.RequireOwner(c.Request.Context(), vehicle.ClientID, fmt.Errorf("%w: vehicle %d", common.ErrNotFound, id)); err != nil {
+		return nil, err
+	}
+	return vehicle, nil
+}
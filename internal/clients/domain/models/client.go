@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Role is what a client may do in the API. Each role includes the permissions of the ones before it.
+type Role string
+
+const (
+	// RoleCustomer books and manages their own reservations, vehicles and addresses
+	RoleCustomer Role = "customer"
+	// RoleStaff runs the day's schedule and manages every customer's bookings
+	RoleStaff Role = "staff"
+	// RoleAdmin also manages slots, business hours, prices and roles
+	RoleAdmin Role = "admin"
+)
+
+// roleRanks orders roles from least to most privileged
+var roleRanks = map[Role]int{
+	RoleCustomer: 1,
+	RoleStaff:    2,
+	RoleAdmin:    3,
+}
+
+// IsValid returns true for a known role
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// AtLeast returns true if r has every permission of minimum
+func (r Role) AtLeast(minimum Role) bool {
+	return r.IsValid() && roleRanks[r] >= roleRanks[minimum]
+}
+
+// Client represents a customer of the car wash.
+// Reservations and addresses reference it through their UserID. Deleting a client keeps the row
+// for that history; deleted clients cannot book or add addresses, and their email and DNI can be reused.
+type Client struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Name  string `gorm:"type:varchar(255);not null" json:"name"`
+	Email string `gorm:"type:varchar(255);uniqueIndex:idx_clients_active_email,where:deleted_at IS NULL;not null" json:"email"`
+	Phone string `gorm:"type:varchar(30);index" json:"phone,omitempty"`
+	// DocumentNumber is the national identity document (DNI), digits only
+	DocumentNumber *string `gorm:"type:varchar(20);uniqueIndex:idx_clients_active_document_number,where:deleted_at IS NULL" json:"document_number,omitempty"`
+	// PasswordHash is the bcrypt hash of the client's password; empty for clients who never signed up
+	PasswordHash string `gorm:"type:varchar(100)" json:"-"`
+	// Role defaults to RoleCustomer; only admins change it
+	Role      Role           `gorm:"type:varchar(20);not null;default:'customer'" json:"role"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Client
+func (Client) TableName() string {
+	return "clients"
+}
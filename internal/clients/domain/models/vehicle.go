@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VehicleSize is the size category of a vehicle, used for pricing
+type VehicleSize string
+
+const (
+	VehicleSizeCar VehicleSize = "car"
+	VehicleSizeSUV VehicleSize = "suv"
+	VehicleSizeVan VehicleSize = "van"
+)
+
+// IsValid returns true for a known size category
+func (s VehicleSize) IsValid() bool {
+	switch s {
+	case VehicleSizeCar, VehicleSizeSUV, VehicleSizeVan:
+		return true
+	}
+	return false
+}
+
+// Vehicle is a client's vehicle, identified by its license plate (patente)
+type Vehicle struct {
+	ID       uint `gorm:"primaryKey" json:"id"`
+	ClientID uint `gorm:"index;not null" json:"client_id"`
+	// Plate is stored uppercase without spaces or dashes (e.g., "AB123CD")
+	Plate     string         `gorm:"type:varchar(10);uniqueIndex;not null" json:"plate"`
+	Make      string         `gorm:"type:varchar(50)" json:"make,omitempty"`
+	Model     string         `gorm:"type:varchar(50)" json:"model,omitempty"`
+	Color     string         `gorm:"type:varchar(30)" json:"color,omitempty"`
+	Size      VehicleSize    `gorm:"type:varchar(20);not null;default:'car'" json:"size"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	// Client declares the foreign key from ClientID; it is never loaded
+	Client *Client `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+}
+
+// TableName specifies the table name for Vehicle
+func (Vehicle) TableName() string {
+	return "vehicles"
+}
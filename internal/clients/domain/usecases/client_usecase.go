@@ -0,0 +1,237 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// ClientRepository defines the interface for client data access
+type ClientRepository interface {
+	FindAll(ctx context.Context) ([]models.Client, error)
+	FindByID(ctx context.Context, id uint) (*models.Client, error)
+	FindByEmail(ctx context.Context, email string) (*models.Client, error)
+	FindByPhone(ctx context.Context, phone string) ([]models.Client, error)
+	// Create and Update return common.ErrConflict if the email or document number is taken
+	Create(ctx context.Context, client *models.Client) error
+	Update(ctx context.Context, client *models.Client) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// ClientDeletionListener is notified after a client is deleted (e.g., to revoke their API keys)
+type ClientDeletionListener interface {
+	ClientDeleted(ctx context.Context, clientID uint)
+}
+
+// ClientInput holds the editable fields of a client
+type ClientInput struct {
+	Name  string
+	Email string
+	// Phone is stored without spaces, dashes, dots or parentheses
+	Phone string
+	// DocumentNumber is the DNI; dots and spaces are ignored
+	DocumentNumber string
+}
+
+// ClientUseCase handles client business logic
+type ClientUseCase struct {
+	repo ClientRepository
+
+	deletionListeners []ClientDeletionListener
+}
+
+// NewClientUseCase creates a new client use case
+func NewClientUseCase(repo ClientRepository) *ClientUseCase {
+	return &ClientUseCase{
+		repo: repo,
+	}
+}
+
+// AddClientDeletionListener registers a listener for deleted clients
+func (uc *ClientUseCase) AddClientDeletionListener(listener ClientDeletionListener) {
+	uc.deletionListeners = append(uc.deletionListeners, listener)
+}
+
+// ListClients returns all clients
+func (uc *ClientUseCase) ListClients(ctx context.Context) ([]models.Client, error) {
+	clients, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return clients, nil
+}
+
+// GetClient returns a client by ID
+func (uc *ClientUseCase) GetClient(ctx context.Context, id uint) (*models.Client, error) {
+	client, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: client %d", common.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return client, nil
+}
+
+// GetClientByEmail returns a client by email, compared case-insensitively
+func (uc *ClientUseCase) GetClientByEmail(ctx context.Context, email string) (*models.Client, error) {
+	client, err := uc.repo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: client with email %q", common.ErrNotFound, email)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return client, nil
+}
+
+// GetClientByPhone returns the client with a phone number, ignoring formatting.
+// Phone numbers are not unique; if several clients share one, it returns common.ErrConflict.
+func (uc *ClientUseCase) GetClientByPhone(ctx context.Context, phone string) (*models.Client, error) {
+	normalized := normalizePhone(phone)
+	if normalized == "" {
+		return nil, fmt.Errorf("%w: client with phone %q", common.ErrNotFound, phone)
+	}
+
+	clients, err := uc.repo.FindByPhone(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	switch len(clients) {
+	case 0:
+		return nil, fmt.Errorf("%w: client with phone %q", common.ErrNotFound, phone)
+	case 1:
+		return &clients[0], nil
+	default:
+		return nil, fmt.Errorf("%w: phone %q belongs to %d clients", common.ErrConflict, phone, len(clients))
+	}
+}
+
+// CreateClient registers a client
+func (uc *ClientUseCase) CreateClient(ctx context.Context, input ClientInput) (*models.Client, error) {
+	return uc.RegisterClient(ctx, input, "")
+}
+
+// RegisterClient registers a client who can log in with the password hashed as passwordHash
+func (uc *ClientUseCase) RegisterClient(ctx context.Context, input ClientInput, passwordHash string) (*models.Client, error) {
+	client := &models.Client{PasswordHash: passwordHash, Role: models.RoleCustomer}
+	if err := applyClientInput(client, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, client); err != nil {
+		return nil, mapSaveError(err)
+	}
+	return client, nil
+}
+
+// UpdateClient replaces the editable fields of a client
+func (uc *ClientUseCase) UpdateClient(ctx context.Context, id uint, input ClientInput) (*models.Client, error) {
+	client, err := uc.GetClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyClientInput(client, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, client); err != nil {
+		return nil, mapSaveError(err)
+	}
+	return client, nil
+}
+
+// SetRole changes the role of a client
+func (uc *ClientUseCase) SetRole(ctx context.Context, id uint, role models.Role) (*models.Client, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("%w: invalid role %q", common.ErrInvalidInput, role)
+	}
+
+	client, err := uc.GetClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	client.Role = role
+	if err := uc.repo.Update(ctx, client); err != nil {
+		return nil, mapSaveError(err)
+	}
+	return client, nil
+}
+
+// DeleteClient removes a client and notifies the deletion listeners
+func (uc *ClientUseCase) DeleteClient(ctx context.Context, id uint) error {
+	if _, err := uc.GetClient(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	for _, listener := range uc.deletionListeners {
+		listener.ClientDeleted(ctx, id)
+	}
+	return nil
+}
+
+// applyClientInput validates and normalizes input into client
+func applyClientInput(client *models.Client, input ClientInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", common.ErrInvalidInput)
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return fmt.Errorf("%w: invalid email %q", common.ErrInvalidInput, input.Email)
+	}
+
+	var document *string
+	if raw := input.DocumentNumber; strings.TrimSpace(raw) != "" {
+		digits := strings.NewReplacer(".", "", " ", "").Replace(strings.TrimSpace(raw))
+		if !isDocumentNumber(digits) {
+			return fmt.Errorf("%w: document_number must have 7 or 8 digits", common.ErrInvalidInput)
+		}
+		document = &digits
+	}
+
+	client.Name = name
+	client.Email = email
+	client.Phone = normalizePhone(input.Phone)
+	client.DocumentNumber = document
+	return nil
+}
+
+// normalizePhone strips the separators people type in phone numbers, e.g. "+54 11 5555-0000" to "+541155550000"
+func normalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+}
+
+// isDocumentNumber reports whether s is a 7 or 8 digit DNI
+func isDocumentNumber(s string) bool {
+	if len(s) < 7 || len(s) > 8 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// mapSaveError keeps uniqueness conflicts and wraps anything else as internal
+func mapSaveError(err error) error {
+	if errors.Is(err, common.ErrConflict) {
+		return fmt.Errorf("%w: email or document_number already registered", common.ErrConflict)
+	}
+	return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+}
@@ -0,0 +1,184 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// VehicleRepository defines the interface for vehicle data access
+type VehicleRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.Vehicle, error)
+	FindByClientID(ctx context.Context, clientID uint) ([]models.Vehicle, error)
+	// Create and Update return common.ErrConflict if the plate is taken
+	// and common.ErrInvalidInput if the client does not exist
+	Create(ctx context.Context, vehicle *models.Vehicle) error
+	Update(ctx context.Context, vehicle *models.Vehicle) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// VehicleInput holds the editable fields of a vehicle
+type VehicleInput struct {
+	ClientID uint
+	// Plate is normalized to uppercase without spaces or dashes
+	Plate string
+	Make  string
+	Model string
+	Color string
+	// Size defaults to models.VehicleSizeCar
+	Size models.VehicleSize
+}
+
+// VehicleUseCase handles vehicle business logic
+type VehicleUseCase struct {
+	repo VehicleRepository
+}
+
+// NewVehicleUseCase creates a new vehicle use case
+func NewVehicleUseCase(repo VehicleRepository) *VehicleUseCase {
+	return &VehicleUseCase{
+		repo: repo,
+	}
+}
+
+// ListClientVehicles returns the vehicles of a client
+func (uc *VehicleUseCase) ListClientVehicles(ctx context.Context, clientID uint) ([]models.Vehicle, error) {
+	vehicles, err := uc.repo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return vehicles, nil
+}
+
+// GetVehicle returns a vehicle by ID
+func (uc *VehicleUseCase) GetVehicle(ctx context.Context, id uint) (*models.Vehicle, error) {
+	vehicle, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: vehicle %d", common.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return vehicle, nil
+}
+
+// GetBookableVehicle returns a vehicle of clientID, or ErrInvalidInput if it cannot be booked for that client
+func (uc *VehicleUseCase) GetBookableVehicle(ctx context.Context, clientID, id uint) (*models.Vehicle, error) {
+	vehicle, err := uc.GetVehicle(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: vehicle %d does not exist", common.ErrInvalidInput, id)
+		}
+		return nil, err
+	}
+
+	if vehicle.ClientID != clientID {
+		return nil, fmt.Errorf("%w: vehicle %d does not belong to client %d", common.ErrInvalidInput, id, clientID)
+	}
+	return vehicle, nil
+}
+
+// CreateVehicle registers a vehicle for a client
+func (uc *VehicleUseCase) CreateVehicle(ctx context.Context, input VehicleInput) (*models.Vehicle, error) {
+	vehicle := &models.Vehicle{}
+	if err := applyVehicleInput(vehicle, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, vehicle); err != nil {
+		return nil, mapVehicleSaveError(err)
+	}
+	return vehicle, nil
+}
+
+// UpdateVehicle replaces the editable fields of a vehicle
+func (uc *VehicleUseCase) UpdateVehicle(ctx context.Context, id uint, input VehicleInput) (*models.Vehicle, error) {
+	vehicle, err := uc.GetVehicle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyVehicleInput(vehicle, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, vehicle); err != nil {
+		return nil, mapVehicleSaveError(err)
+	}
+	return vehicle, nil
+}
+
+// DeleteVehicle removes a vehicle
+func (uc *VehicleUseCase) DeleteVehicle(ctx context.Context, id uint) error {
+	if _, err := uc.GetVehicle(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// applyVehicleInput validates and normalizes input into vehicle
+func applyVehicleInput(vehicle *models.Vehicle, input VehicleInput) error {
+	if input.ClientID == 0 {
+		return fmt.Errorf("%w: client_id is required", common.ErrInvalidInput)
+	}
+
+	plate := normalizePlate(input.Plate)
+	if !isPlate(plate) {
+		return fmt.Errorf("%w: invalid plate %q", common.ErrInvalidInput, input.Plate)
+	}
+
+	size := input.Size
+	if size == "" {
+		size = models.VehicleSizeCar
+	}
+	if !size.IsValid() {
+		return fmt.Errorf("%w: size must be car, suv or van", common.ErrInvalidInput)
+	}
+
+	vehicle.ClientID = input.ClientID
+	vehicle.Plate = plate
+	vehicle.Make = strings.TrimSpace(input.Make)
+	vehicle.Model = strings.TrimSpace(input.Model)
+	vehicle.Color = strings.TrimSpace(input.Color)
+	vehicle.Size = size
+	return nil
+}
+
+// normalizePlate uppercases a plate and drops spaces and dashes
+func normalizePlate(plate string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(plate))
+}
+
+// isPlate reports whether plate is 6 or 7 letters and digits, covering the
+// "ABC123" and Mercosur "AB123CD" formats
+func isPlate(plate string) bool {
+	if len(plate) < 6 || len(plate) > 7 {
+		return false
+	}
+	for _, r := range plate {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// mapVehicleSaveError keeps input and uniqueness errors and wraps anything else as internal
+func mapVehicleSaveError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrInvalidInput):
+		return err
+	case errors.Is(err, common.ErrConflict):
+		return fmt.Errorf("%w: plate already registered", common.ErrConflict)
+	default:
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"gorm.io/gorm"
+)
+
+// ClientRepository implements the client repository interface
+type ClientRepository struct {
+	db *gorm.DB
+}
+
+// NewClientRepository creates a new client repository
+func NewClientRepository(db *gorm.DB) *ClientRepository {
+	return &ClientRepository{
+		db: db,
+	}
+}
+
+// FindAll retrieves all clients
+func (r *ClientRepository) FindAll(ctx context.Context) ([]models.Client, error) {
+	var clients []models.Client
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// FindByID retrieves a client by ID
+func (r *ClientRepository) FindByID(ctx context.Context, id uint) (*models.Client, error) {
+	var client models.Client
+	if err := r.db.WithContext(ctx).First(&client, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// FindByEmail retrieves a client by email
+func (r *ClientRepository) FindByEmail(ctx context.Context, email string) (*models.Client, error) {
+	var client models.Client
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// FindByPhone retrieves the clients with a phone number
+func (r *ClientRepository) FindByPhone(ctx context.Context, phone string) ([]models.Client, error) {
+	var clients []models.Client
+	if err := r.db.WithContext(ctx).Where("phone = ?", phone).Order("id ASC").Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// Create creates a new client.
+// Returns common.ErrConflict if the email or document number is already registered.
+func (r *ClientRepository) Create(ctx context.Context, client *models.Client) error {
+	return mapDuplicate(r.db.WithContext(ctx).Create(client).Error)
+}
+
+// Update updates an existing client.
+// Returns common.ErrConflict if the email or document number is already registered.
+func (r *ClientRepository) Update(ctx context.Context, client *models.Client) error {
+	return mapDuplicate(r.db.WithContext(ctx).Save(client).Error)
+}
+
+// Delete soft deletes a client; its reservations and addresses keep referencing it.
+// Its email and document number are free to register again.
+func (r *ClientRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Client{}, id).Error
+}
+
+// mapDuplicate maps unique index violations to common.ErrConflict
+func mapDuplicate(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return common.ErrConflict
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"gorm.io/gorm"
+)
+
+// VehicleRepository implements the vehicle repository interface
+type VehicleRepository struct {
+	db *gorm.DB
+}
+
+// NewVehicleRepository creates a new vehicle repository
+func NewVehicleRepository(db *gorm.DB) *VehicleRepository {
+	return &VehicleRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves a vehicle by ID
+func (r *VehicleRepository) FindByID(ctx context.Context, id uint) (*models.Vehicle, error) {
+	var vehicle models.Vehicle
+	if err := r.db.WithContext(ctx).First(&vehicle, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &vehicle, nil
+}
+
+// FindByClientID retrieves all vehicles of a client
+func (r *VehicleRepository) FindByClientID(ctx context.Context, clientID uint) ([]models.Vehicle, error) {
+	var vehicles []models.Vehicle
+	if err := r.db.WithContext(ctx).
+		Where("client_id = ?", clientID).
+		Order("id ASC").
+		Find(&vehicles).Error; err != nil {
+		return nil, err
+	}
+	return vehicles, nil
+}
+
+// Create creates a new vehicle.
+// Returns common.ErrConflict if the plate is registered and common.ErrInvalidInput if the client does not exist.
+func (r *VehicleRepository) Create(ctx context.Context, vehicle *models.Vehicle) error {
+	return mapVehicleError(r.db.WithContext(ctx).Create(vehicle).Error, vehicle.ClientID)
+}
+
+// Update updates an existing vehicle.
+// Returns common.ErrConflict if the plate is registered and common.ErrInvalidInput if the client does not exist.
+func (r *VehicleRepository) Update(ctx context.Context, vehicle *models.Vehicle) error {
+	return mapVehicleError(r.db.WithContext(ctx).Save(vehicle).Error, vehicle.ClientID)
+}
+
+// Delete soft deletes a vehicle; reservations keep referencing it
+func (r *VehicleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Vehicle{}, id).Error
+}
+
+// mapVehicleError maps duplicate plates and unknown clients to domain errors
+func mapVehicleError(err error, clientID uint) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return fmt.Errorf("%w: client %d does not exist", common.ErrInvalidInput, clientID)
+	}
+	return mapDuplicate(err)
+}
@@ -0,0 +1,7 @@
+package common
+
+import "time"
+
+// Clock returns the current time.
+// Use cases take a Clock instead of calling time.Now so tests can fix the current time.
+type Clock func() time.Time
@@ -1,14 +1,21 @@
 package common
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Reservations ReservationsConfig
+	Pricing      PricingConfig
+	Business     BusinessConfig
+	Auth         AuthConfig
+	Payments     PaymentsConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -22,6 +29,83 @@ type DatabaseConfig struct {
 	DSN string
 }
 
+// ReservationsConfig holds reservation business rules
+type ReservationsConfig struct {
+	// FreeCancellationHours is how many hours before the start time a cancellation is fully refunded
+	FreeCancellationHours int
+	// LateCancellationFeePercent is the percentage retained for cancellations after the free window
+	LateCancellationFeePercent int
+	// MinLeadMinutes is how many minutes ahead of now a booking must start
+	MinLeadMinutes int
+	// HoldMinutes is how long a reservation hold keeps its slot before it expires
+	HoldMinutes int
+	// HoldSweepSeconds is how often expired holds are released
+	HoldSweepSeconds int
+	// RefundRetrySeconds is how often refunds of cancellations that failed are retried
+	RefundRetrySeconds int
+}
+
+// PricingConfig holds how prices vary with the vehicle washed
+type PricingConfig struct {
+	// SUVSurchargePercent is added to the service base price for SUVs
+	SUVSurchargePercent int
+	// VanSurchargePercent is added to the service base price for vans
+	VanSurchargePercent int
+}
+
+// BusinessConfig holds settings about the business itself
+type BusinessConfig struct {
+	// TimeZone is the IANA zone used for opening hours, availability dates and booking validation
+	TimeZone string
+}
+
+// AuthConfig holds token signing and lifetime settings
+type AuthConfig struct {
+	// JWTSecret signs access tokens; required in release mode
+	JWTSecret string
+	// AccessTokenMinutes is how long an access token is valid
+	AccessTokenMinutes int
+	// RefreshTokenDays is how long a refresh token is valid if not rotated
+	RefreshTokenDays int
+	// OTPMinutes is how long a one-time login code is valid
+	OTPMinutes int
+	// OTPMaxAttempts is how many guesses a one-time login code allows
+	OTPMaxAttempts int
+	// OTPOutboxFile receives login codes as JSON lines instead of the log until email/SMS delivery exists
+	OTPOutboxFile string
+	// APIKeyRateLimitPerMinute is the rate limit of API keys issued without one
+	APIKeyRateLimitPerMinute int
+}
+
+// PaymentsConfig holds the payment provider settings
+type PaymentsConfig struct {
+	// Provider is "mercadopago", or "fake" to use an in-process fake of Mercado Pago outside release mode
+	Provider string
+	// PublicURL is where clients and providers reach this API
+	PublicURL string
+	// ReturnURL is where clients land after paying
+	ReturnURL string
+	// MercadoPagoAccessToken authenticates with Mercado Pago; required for the mercadopago provider
+	MercadoPagoAccessToken string
+	// MercadoPagoBaseURL overrides the Mercado Pago API URL
+	MercadoPagoBaseURL string
+	// WebhookSecret verifies provider webhook signatures; required in release mode
+	WebhookSecret string
+	// WebhookToleranceSeconds is how old, or how far ahead, a webhook signature may be
+	WebhookToleranceSeconds int
+	// DepositPercent is the share of a reservation's price a deposit checkout charges
+	DepositPercent int
+}
+
+// Location loads the business time zone
+func (c BusinessConfig) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid business time zone %q: %w", c.TimeZone, err)
+	}
+	return loc, nil
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	return &Config{
@@ -32,6 +116,40 @@ func LoadConfig() *Config {
 		Database: DatabaseConfig{
 			DSN: getEnv("DATABASE_DSN", "lavalo.db"),
 		},
+		Reservations: ReservationsConfig{
+			FreeCancellationHours:      getEnvAsInt("CANCELLATION_FREE_HOURS", 24),
+			LateCancellationFeePercent: getEnvAsInt("CANCELLATION_LATE_FEE_PERCENT", 50),
+			MinLeadMinutes:             getEnvAsInt("BOOKING_MIN_LEAD_MINUTES", 60),
+			HoldMinutes:                getEnvAsInt("RESERVATION_HOLD_MINUTES", 10),
+			HoldSweepSeconds:           getEnvAsInt("RESERVATION_HOLD_SWEEP_SECONDS", 30),
+			RefundRetrySeconds:         getEnvAsInt("RESERVATION_REFUND_RETRY_SECONDS", 300),
+		},
+		Pricing: PricingConfig{
+			SUVSurchargePercent: getEnvAsInt("PRICING_SUV_SURCHARGE_PERCENT", 20),
+			VanSurchargePercent: getEnvAsInt("PRICING_VAN_SURCHARGE_PERCENT", 40),
+		},
+		Business: BusinessConfig{
+			TimeZone: getEnv("BUSINESS_TIMEZONE", "America/Argentina/Buenos_Aires"),
+		},
+		Auth: AuthConfig{
+			JWTSecret:                getEnv("AUTH_JWT_SECRET", ""),
+			AccessTokenMinutes:       getEnvAsInt("AUTH_ACCESS_TOKEN_MINUTES", 15),
+			RefreshTokenDays:         getEnvAsInt("AUTH_REFRESH_TOKEN_DAYS", 30),
+			OTPMinutes:               getEnvAsInt("AUTH_OTP_MINUTES", 10),
+			OTPMaxAttempts:           getEnvAsInt("AUTH_OTP_MAX_ATTEMPTS", 5),
+			OTPOutboxFile:            getEnv("AUTH_OTP_OUTBOX_FILE", ""),
+			APIKeyRateLimitPerMinute: getEnvAsInt("AUTH_API_KEY_RATE_LIMIT_PER_MINUTE", 60),
+		},
+		Payments: PaymentsConfig{
+			Provider:                getEnv("PAYMENTS_PROVIDER", "fake"),
+			PublicURL:               getEnv("PUBLIC_BASE_URL", ""),
+			ReturnURL:               getEnv("PAYMENTS_RETURN_URL", ""),
+			MercadoPagoAccessToken:  getEnv("MERCADOPAGO_ACCESS_TOKEN", ""),
+			MercadoPagoBaseURL:      getEnv("MERCADOPAGO_BASE_URL", ""),
+			WebhookSecret:           getEnv("PAYMENTS_WEBHOOK_SECRET", ""),
+			WebhookToleranceSeconds: getEnvAsInt("PAYMENTS_WEBHOOK_TOLERANCE_SECONDS", 300),
+			DepositPercent:          getEnvAsInt("PAYMENTS_DEPOSIT_PERCENT", 30),
+		},
 	}
 }
 
@@ -52,4 +170,3 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
-
@@ -12,7 +12,9 @@ var (
 	ErrUnauthorized      = errors.New("unauthorized")
 	ErrForbidden         = errors.New("forbidden")
 	ErrConflict          = errors.New("resource conflict")
+	ErrTooManyRequests   = errors.New("too many requests")
 	ErrInternalServer    = errors.New("internal server error")
+	ErrUpstream          = errors.New("upstream service failed")
 	ErrSlotNotAvailable  = errors.New("slot not available")
 	ErrReservationFailed = errors.New("reservation failed")
 )
@@ -53,6 +55,10 @@ func MapErrorToHTTPStatus(err error) int {
 		return http.StatusConflict
 	case errors.Is(err, ErrSlotNotAvailable):
 		return http.StatusConflict
+	case errors.Is(err, ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrUpstream):
+		return http.StatusBadGateway
 	default:
 		return http.StatusInternalServerError
 	}
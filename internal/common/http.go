@@ -0,0 +1,62 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RespondError writes a domain error as an APIError with the mapped HTTP status
+func RespondError(c *gin.Context, err error) {
+	statusCode := MapErrorToHTTPStatus(err)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		c.JSON(statusCode, apiErr)
+		return
+	}
+
+	c.JSON(statusCode, NewAPIError(statusCode, err.Error(), ""))
+}
+
+// ParseIDParam parses a positive numeric path parameter such as :id
+func ParseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrInvalidInput, name, c.Param(name))
+	}
+	return uint(id), nil
+}
+
+// ParseOptionalIDQuery parses a positive numeric query parameter, returning 0 if it is absent
+func ParseOptionalIDQuery(c *gin.Context, name string) (uint, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrInvalidInput, name, raw)
+	}
+	return uint(id), nil
+}
+
+// ParseIDQuery parses a list of positive numeric query parameters, accepting both
+// repeated keys and comma-separated values (e.g. ?slot_id=1,2&slot_id=3)
+func ParseIDQuery(c *gin.Context, name string) ([]uint, error) {
+	var ids []uint
+	for _, raw := range c.QueryArray(name) {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+			if err != nil || id == 0 {
+				return nil, fmt.Errorf("%w: invalid %s %q", ErrInvalidInput, name, part)
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
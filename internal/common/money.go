@@ -0,0 +1,144 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// DefaultCurrency is the ISO 4217 currency prices are set and charged in
+const DefaultCurrency = "ARS"
+
+// currencyPattern matches ISO 4217 currency codes
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Money is an amount in minor units (centavos for ARS) of an ISO 4217 currency.
+// Amounts are integers so sums, percentages and refunds never drift.
+//
+// Stored with gorm as embedded columns, e.g. `gorm:"embedded;embeddedPrefix:price_"` gives price_cents and price_currency.
+type Money struct {
+	Cents    int64  `gorm:"not null;default:0" json:"cents"`
+	Currency string `gorm:"type:varchar(3)" json:"currency"`
+}
+
+// NewMoney returns cents of currency
+func NewMoney(cents int64, currency string) Money {
+	return Money{Cents: cents, Currency: currency}
+}
+
+// MoneyFromMajor converts an amount in major units, as used by payment provider APIs, rounding to the nearest cent
+func MoneyFromMajor(amount float64, currency string) Money {
+	return Money{Cents: int64(math.Round(amount * 100)), Currency: currency}
+}
+
+// Major returns the amount in major units, for payment provider APIs
+func (m Money) Major() float64 {
+	return float64(m.Cents) / 100
+}
+
+// Validate checks the amount is not negative and the currency is an ISO 4217 code
+func (m Money) Validate() error {
+	if m.Cents < 0 {
+		return fmt.Errorf("%w: amount cannot be negative", ErrInvalidInput)
+	}
+	if !currencyPattern.MatchString(m.Currency) {
+		return fmt.Errorf("%w: invalid currency %q", ErrInvalidInput, m.Currency)
+	}
+	return nil
+}
+
+// WithDefaultCurrency returns m in DefaultCurrency if it has no currency
+func (m Money) WithDefaultCurrency() Money {
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+	return m
+}
+
+// Add returns m + other, or ErrConflict if they are in different currencies
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.sameCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: m.Cents + other.Cents, Currency: currency}, nil
+}
+
+// Sub returns m - other, or ErrConflict if they are in different currencies
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.sameCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: m.Cents - other.Cents, Currency: currency}, nil
+}
+
+// Percent returns percent of m, rounding half away from zero to the cent
+func (m Money) Percent(percent int) Money {
+	product := m.Cents * int64(percent)
+	cents := (product + 50) / 100
+	if product < 0 {
+		cents = (product - 50) / 100
+	}
+	return Money{Cents: cents, Currency: m.Currency}
+}
+
+// Min returns the smaller of m and other, or ErrConflict if they are in different currencies
+func (m Money) Min(other Money) (Money, error) {
+	if _, err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	if other.Cents < m.Cents {
+		return other, nil
+	}
+	return m, nil
+}
+
+// IsZero returns true if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Cents == 0
+}
+
+// IsPositive returns true if the amount is more than zero
+func (m Money) IsPositive() bool {
+	return m.Cents > 0
+}
+
+// Equal returns true if m and other are the same amount of the same currency
+func (m Money) Equal(other Money) bool {
+	return m.Cents == other.Cents && (m.Currency == other.Currency || m.Cents == 0)
+}
+
+// LessThan returns true if m is less than other, or ErrConflict if they are in different currencies
+func (m Money) LessThan(other Money) (bool, error) {
+	if _, err := m.sameCurrency(other); err != nil {
+		return false, err
+	}
+	return m.Cents < other.Cents, nil
+}
+
+// String formats the amount in major units, e.g. "ARS 1500.50"
+func (m Money) String() string {
+	sign := ""
+	cents := m.Cents
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s %s%d.%02d", m.Currency, sign, cents/100, cents%100)
+}
+
+// sameCurrency returns the currency of an operation on m and other. A zero amount without
+// a currency, such as a sum that has not started, takes the other's currency.
+// Nothing converts between currencies, so mixing them is ErrConflict; amounts reported by
+// payment providers can be in a currency other than the one charged.
+func (m Money) sameCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == other.Currency:
+		return m.Currency, nil
+	case m.Currency == "" && m.Cents == 0:
+		return other.Currency, nil
+	case other.Currency == "" && other.Cents == 0:
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: cannot combine %s with %s", ErrConflict, m.Currency, other.Currency)
+}
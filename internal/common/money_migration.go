@@ -0,0 +1,69 @@
+package common
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DecimalMoneyColumn is a decimal column replaced by Money columns
+type DecimalMoneyColumn struct {
+	Table string
+	// Column is the decimal column holding the amount in major units
+	Column string
+	// Prefix is the embeddedPrefix of the Money field replacing it
+	Prefix string
+	// CurrencyColumn holds each row's currency; rows without one get DefaultCurrency
+	CurrencyColumn string
+}
+
+// MigrateDecimalMoney moves amounts from decimal columns into the Money columns that replace them,
+// then drops the decimal columns. The Money columns must exist already, e.g. through AutoMigrate.
+// Columns that were already migrated are skipped, so it is safe to run on every start.
+func MigrateDecimalMoney(db *gorm.DB, columns ...DecimalMoneyColumn) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		drop := make(map[string][]string)
+
+		for _, column := range columns {
+			if !migrator.HasTable(column.Table) || !migrator.HasColumn(column.Table, column.Column) {
+				continue
+			}
+
+			currency := fmt.Sprintf("'%s'", DefaultCurrency)
+			if column.CurrencyColumn != "" && migrator.HasColumn(column.Table, column.CurrencyColumn) {
+				currency = fmt.Sprintf("COALESCE(NULLIF(%s, ''), '%s')", column.CurrencyColumn, DefaultCurrency)
+				drop[column.Table] = appendOnce(drop[column.Table], column.CurrencyColumn)
+			}
+
+			err := tx.Exec(fmt.Sprintf(
+				"UPDATE %s SET %scents = CAST(ROUND(COALESCE(%s, 0) * 100) AS BIGINT), %scurrency = %s",
+				column.Table, column.Prefix, column.Column, column.Prefix, currency,
+			)).Error
+			if err != nil {
+				return fmt.Errorf("migrating %s.%s: %w", column.Table, column.Column, err)
+			}
+			drop[column.Table] = appendOnce(drop[column.Table], column.Column)
+		}
+
+		for table, names := range drop {
+			for _, name := range names {
+				// Migrator.DropColumn needs a model, which the decimal columns no longer have
+				if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, name)).Error; err != nil {
+					return fmt.Errorf("dropping %s.%s: %w", table, name, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// appendOnce appends value to values unless it is already there
+func appendOnce(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -1,64 +1,388 @@
 package http
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
 )
 
+// ReservationReader looks up the reservation a payment is for, to authorize access to it
+type ReservationReader interface {
+	GetReservation(ctx context.Context, id uint) (*reservationModels.Reservation, error)
+}
+
 // PaymentHandler handles HTTP requests for payments
 type PaymentHandler struct {
-	// TODO: Add use cases dependency injection
+	useCase      *usecases.PaymentUseCase
+	checkout     *usecases.CheckoutUseCase
+	webhooks     *usecases.WebhookUseCase
+	refunds      *usecases.RefundUseCase
+	reservations ReservationReader
 }
 
+// maxWebhookBytes bounds the body of a provider notification
+const maxWebhookBytes = 1 << 20
+
 // NewPaymentHandler creates a new payment handler
-func NewPaymentHandler() *PaymentHandler {
-	return &PaymentHandler{}
+func NewPaymentHandler(useCase *usecases.PaymentUseCase, checkout *usecases.CheckoutUseCase, webhooks *usecases.WebhookUseCase, refunds *usecases.RefundUseCase, reservations ReservationReader) *PaymentHandler {
+	return &PaymentHandler{
+		useCase:      useCase,
+		checkout:     checkout,
+		webhooks:     webhooks,
+		refunds:      refunds,
+		reservations: reservations,
+	}
+}
+
+// createPaymentRequest is the request body for POST /payments
+type createPaymentRequest struct {
+	ReservationID uint `json:"reservation_id" binding:"required"`
+	// Deposit pays only the deposit, leaving the balance to be paid at the bay
+	Deposit bool `json:"deposit"`
+}
+
+// createCashPaymentRequest is the request body for POST /payments/cash
+type createCashPaymentRequest struct {
+	ReservationID uint `json:"reservation_id" binding:"required"`
+	// Amount defaults to the outstanding balance, in the reservation's currency
+	Amount common.Money `json:"amount"`
+}
+
+// createRefundRequest is the request body for POST /payments/:id/refunds
+type createRefundRequest struct {
+	// Amount defaults to everything not refunded yet, in the payment's currency
+	Amount common.Money `json:"amount"`
+	Reason string       `json:"reason" binding:"required"`
 }
 
-// RegisterRoutes registers all payment routes
+// RegisterRoutes registers the payment routes that require an access token
 func (h *PaymentHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	payments := rg.Group("/payments")
 	{
 		payments.GET("", h.List)
 		payments.GET("/:id", h.GetByID)
 		payments.POST("", h.Create)
-		payments.POST("/webhook", h.Webhook)
+		payments.POST("/cash", h.CreateCash)
+		payments.GET("/:id/refunds", h.ListRefunds)
+		payments.POST("/:id/refunds", h.CreateRefund)
 	}
 }
 
-// List returns all payments
+// RegisterWebhookRoutes registers the provider webhook, which is public and authenticated by its signature
+func (h *PaymentHandler) RegisterWebhookRoutes(rg *gin.RouterGroup) {
+	rg.POST("/payments/webhook", h.Webhook)
+}
+
+// List returns the payments of a reservation or of a client's reservations
+// @Summary List payments
+// @Description With reservation_id, the payments of that reservation. Otherwise customers get the
+// @Description payments of all their reservations and staff those of user_id.
+// @Tags payments
+// @Produce json
+// @Param reservation_id query int false "Reservation"
+// @Param user_id query int false "Client; required for staff without reservation_id"
+// @Success 200 {array} models.Payment
+// @Failure 400 {object} common.APIError "Missing user_id or reservation_id"
+// @Failure 403 {object} common.APIError "Another client's payments"
+// @Router /api/v1/payments [get]
 func (h *PaymentHandler) List(c *gin.Context) {
-	// TODO: Implement list payments
+	reservationID, err := common.ParseOptionalIDQuery(c, "reservation_id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var payments []models.Payment
+	if reservationID != 0 {
+		payments, err = h.listReservationPayments(c, reservationID)
+	} else {
+		payments, err = h.listUserPayments(c)
+	}
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    []interface{}{},
-		"message": "list payments - not implemented",
+		"data": payments,
 	})
 }
 
 // GetByID returns a payment by ID
 func (h *PaymentHandler) GetByID(c *gin.Context) {
-	// TODO: Implement get payment by ID
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	payment, err := h.useCase.GetPayment(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.authorizeReservation(c, payment.ReservationID, fmt.Errorf("%w: payment %d", common.ErrNotFound, id)); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    nil,
-		"message": "get payment - not implemented",
+		"data": payment,
 	})
 }
 
-// Create initiates a new payment
+// Create starts paying what is owed on a reservation, or its deposit, through the payment provider
+// @Summary Create payment
+// @Description Returns a pending payment whose checkout_url the client is sent to in order to pay.
+// @Description With deposit, only the deposit less what was paid already is charged and the balance
+// @Description is paid at the bay. If a checkout for the same amount is already pending, that payment is returned.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Payment
+// @Failure 400 {object} common.APIError "Invalid input or reservation without a price"
+// @Failure 404 {object} common.APIError "Reservation not found or another client's"
+// @Failure 409 {object} common.APIError "Reservation or deposit already paid, reservation cancelled or completed"
+// @Failure 502 {object} common.APIError "Payment provider failed"
+// @Router /api/v1/payments [post]
 func (h *PaymentHandler) Create(c *gin.Context) {
-	// TODO: Implement create payment
+	var req createPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if err := h.authorizeReservation(c, req.ReservationID, common.ErrNotFound); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var payment *models.Payment
+	var err error
+	if req.Deposit {
+		payment, err = h.checkout.CreateDepositCheckout(c.Request.Context(), req.ReservationID)
+	} else {
+		payment, err = h.checkout.CreateCheckout(c.Request.Context(), req.ReservationID)
+	}
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"data":    nil,
-		"message": "create payment - not implemented",
+		"data": payment,
 	})
 }
 
-// Webhook handles payment provider webhooks
+// CreateCash records a payment taken in cash at the bay
+// @Summary Record cash payment
+// @Description Staff only. Records amount, or the whole outstanding balance if it is omitted, as a
+// @Description completed cash payment. Completed reservations may still be paid.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Payment
+// @Failure 400 {object} common.APIError "Invalid input or amount over the outstanding balance"
+// @Failure 404 {object} common.APIError "Reservation not found"
+// @Failure 409 {object} common.APIError "Reservation already paid or cancelled"
+// @Router /api/v1/payments/cash [post]
+func (h *PaymentHandler) CreateCash(c *gin.Context) {
+	principal, err := policies.RequireRole(c.Request.Context(), clientModels.RoleStaff)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req createCashPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	payment, err := h.checkout.RecordCashPayment(c.Request.Context(), usecases.CashPaymentInput{
+		ReservationID: req.ReservationID,
+		Amount:        req.Amount,
+		RecordedBy:    principal.String(),
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": payment,
+	})
+}
+
+// ListRefunds returns the refunds of a payment, oldest first
+// @Summary List payment refunds
+// @Tags payments
+// @Produce json
+// @Param id path int true "Payment"
+// @Success 200 {array} models.Refund
+// @Failure 404 {object} common.APIError "Payment not found or another client's"
+// @Router /api/v1/payments/{id}/refunds [get]
+func (h *PaymentHandler) ListRefunds(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	payment, err := h.useCase.GetPayment(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.authorizeReservation(c, payment.ReservationID, fmt.Errorf("%w: payment %d", common.ErrNotFound, id)); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	refunds, err := h.refunds.ListPaymentRefunds(c.Request.Context(), payment.ID)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": refunds,
+	})
+}
+
+// CreateRefund refunds part or all of a payment
+// @Summary Refund payment
+// @Description Staff only. Refunds amount, or everything not refunded yet if it is omitted, through the
+// @Description payment provider for payments taken by checkout. The payment becomes partially_refunded
+// @Description or, once nothing is left to refund, refunded. A refund the provider rejects is kept as failed.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment"
+// @Success 201 {object} models.Refund
+// @Failure 400 {object} common.APIError "Invalid input or amount over the refundable balance"
+// @Failure 404 {object} common.APIError "Payment not found"
+// @Failure 409 {object} common.APIError "Payment not captured or already refunded"
+// @Failure 502 {object} common.APIError "Payment provider failed"
+// @Router /api/v1/payments/{id}/refunds [post]
+func (h *PaymentHandler) CreateRefund(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	principal, err := policies.RequireRole(c.Request.Context(), clientModels.RoleStaff)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req createRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	refund, err := h.refunds.RefundPayment(c.Request.Context(), id, usecases.RefundInput{
+		Amount:      req.Amount,
+		Reason:      req.Reason,
+		RequestedBy: principal.String(),
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": refund,
+	})
+}
+
+// Webhook applies a payment provider notification
+// @Summary Payment provider webhook
+// @Description Called by the payment provider when a payment changes. The notification is verified by its
+// @Description signature, the payment is updated from the provider, and its reservation is confirmed once paid.
+// @Description Repeated notifications are acknowledged without being processed again.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} common.APIError "Malformed notification"
+// @Failure 401 {object} common.APIError "Invalid or expired signature"
+// @Failure 502 {object} common.APIError "Payment provider failed; the provider retries"
+// @Router /api/v1/payments/webhook [post]
 func (h *PaymentHandler) Webhook(c *gin.Context) {
-	// TODO: Implement webhook handling
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	result, err := h.webhooks.HandleWebhook(c.Request.Context(), models.WebhookRequest{
+		Header: c.Request.Header,
+		Query:  c.Request.URL.Query(),
+		Body:   body,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	message := "webhook processed"
+	if result.Duplicate {
+		message = "webhook already processed"
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "webhook received",
+		"message": message,
 	})
 }
 
+// listReservationPayments returns the payments of a reservation the caller may see
+func (h *PaymentHandler) listReservationPayments(c *gin.Context, reservationID uint) ([]models.Payment, error) {
+	if err := h.authorizeReservation(c, reservationID, common.ErrNotFound); err != nil {
+		return nil, err
+	}
+	return h.useCase.ListReservationPayments(c.Request.Context(), reservationID)
+}
+
+// listUserPayments returns the payments of the caller, or for staff of the user_id query parameter
+func (h *PaymentHandler) listUserPayments(c *gin.Context) ([]models.Payment, error) {
+	requested, err := common.ParseOptionalIDQuery(c, "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	_, userID, err := policies.ScopeClient(c.Request.Context(), requested)
+	if err != nil {
+		return nil, err
+	}
+	if userID == 0 {
+		return nil, fmt.Errorf("%w: user_id or reservation_id is required", common.ErrInvalidInput)
+	}
+	return h.useCase.ListUserPayments(c.Request.Context(), userID)
+}
+
+// authorizeReservation checks the caller booked the reservation, or is staff. Other clients get
+// notFound, the error the resource they asked for would give if it did not exist.
+func (h *PaymentHandler) authorizeReservation(c *gin.Context, reservationID uint, notFound error) error {
+	reservation, err := h.reservations.GetReservation(c.Request.Context(), reservationID)
+	if err != nil {
+		return err
+	}
+
+	_, err = policies.RequireOwner(c.Request.Context(), reservation.UserID, notFound)
+	return err
+}
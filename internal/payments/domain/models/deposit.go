@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// DepositPolicy defines how much of a reservation's price is paid up front when booking,
+// leaving the balance to be paid at the bay
+type DepositPolicy struct {
+	// Percent is the share of the price a deposit charges; zero disables deposits
+	Percent int
+}
+
+// Enabled returns true if reservations may be booked with a deposit
+func (p DepositPolicy) Enabled() bool {
+	return p.Percent > 0
+}
+
+// Amount returns the deposit for a reservation priced at price, rounded to cents and never more than price
+func (p DepositPolicy) Amount(price common.Money) common.Money {
+	return price.Percent(min(p.Percent, 100))
+}
@@ -4,30 +4,76 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 )
 
 // PaymentStatus represents the status of a payment
 type PaymentStatus string
 
 const (
-	PaymentStatusPending   PaymentStatus = "pending"
-	PaymentStatusCompleted PaymentStatus = "completed"
-	PaymentStatusFailed    PaymentStatus = "failed"
-	PaymentStatusRefunded  PaymentStatus = "refunded"
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusCompleted         PaymentStatus = "completed"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+)
+
+// paymentTransitions lists the statuses each status may move to as the provider reports progress.
+// A failed payment may still complete when the client retries the same checkout.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending:           {PaymentStatusCompleted, PaymentStatusFailed, PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+	PaymentStatusFailed:            {PaymentStatusCompleted},
+	PaymentStatusCompleted:         {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+	PaymentStatusPartiallyRefunded: {PaymentStatusRefunded},
+}
+
+// CanTransitionTo returns true if a payment in status s may move to next
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderName identifies the payment provider that processes a payment
+type ProviderName string
+
+const (
+	ProviderMercadoPago ProviderName = "mercadopago"
+)
+
+// PaymentMethod is how a payment was taken
+type PaymentMethod string
+
+const (
+	// PaymentMethodCheckout payments are charged through the provider's hosted checkout
+	PaymentMethodCheckout PaymentMethod = "checkout"
+	// PaymentMethodCash payments are taken by staff at the bay
+	PaymentMethodCash PaymentMethod = "cash"
 )
 
 // Payment represents a payment transaction
 type Payment struct {
-	ID            uint           `gorm:"primaryKey" json:"id"`
-	ReservationID uint           `gorm:"index;not null" json:"reservation_id"`
-	Amount        float64        `gorm:"type:decimal(10,2);not null" json:"amount"`
-	Currency      string         `gorm:"type:varchar(3);default:'ARS'" json:"currency"`
-	Status        PaymentStatus  `gorm:"type:varchar(20);default:'pending'" json:"status"`
-	Provider      string         `gorm:"type:varchar(50)" json:"provider"`
-	ExternalID    string         `gorm:"type:varchar(255)" json:"external_id,omitempty"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	ReservationID  uint          `gorm:"index;not null" json:"reservation_id"`
+	Amount         common.Money  `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	RefundedAmount common.Money  `gorm:"embedded;embeddedPrefix:refunded_" json:"refunded_amount"`
+	Status         PaymentStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	Method         PaymentMethod `gorm:"type:varchar(20);default:'checkout'" json:"method"`
+	// Provider processes checkout payments; empty for payments taken in person
+	Provider ProviderName `gorm:"type:varchar(50)" json:"provider"`
+	// ExternalID is the provider's ID for the checkout, e.g. a Mercado Pago preference
+	ExternalID string `gorm:"type:varchar(255);index" json:"external_id,omitempty"`
+	// CheckoutURL is where the client pays a pending payment
+	CheckoutURL string `gorm:"type:varchar(500)" json:"checkout_url,omitempty"`
+	// RecordedBy is who took a payment in person, e.g. "staff:3"
+	RecordedBy string         `gorm:"type:varchar(100)" json:"recorded_by,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName specifies the table name for Payment
@@ -35,3 +81,11 @@ func (Payment) TableName() string {
 	return "payments"
 }
 
+// RefundableAmount returns the captured amount that has not been refunded yet.
+// Returns common.ErrConflict if the refunded amount is in another currency.
+func (p *Payment) RefundableAmount() (common.Money, error) {
+	if p.Status != PaymentStatusCompleted && p.Status != PaymentStatusPartiallyRefunded {
+		return common.Money{Currency: p.Amount.Currency}, nil
+	}
+	return p.Amount.Sub(p.RefundedAmount)
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// CheckoutRequest asks a payment provider for a hosted checkout
+type CheckoutRequest struct {
+	// Reference is our ID for the payment; the provider echoes it back in notifications
+	Reference string
+	Title     string
+	Amount    common.Money
+	// ExpiresAt closes the checkout, e.g. when the reservation hold it pays for ends; zero for no limit
+	ExpiresAt time.Time
+}
+
+// Checkout is a hosted checkout created by a payment provider
+type Checkout struct {
+	// ExternalID is the provider's ID for the checkout
+	ExternalID string
+	// URL is where the client is sent to pay
+	URL string
+}
+
+// ProviderPayment is the state of a checkout as reported by its provider
+type ProviderPayment struct {
+	ExternalID     string
+	Status         PaymentStatus
+	PaidAmount     common.Money
+	RefundedAmount common.Money
+}
+
+// ProviderRefund is a refund processed by a payment provider
+type ProviderRefund struct {
+	ID     string
+	Amount common.Money
+}
+
+// WebhookRequest is a notification as received from a payment provider
+type WebhookRequest struct {
+	Header http.Header
+	Query  url.Values
+	Body   []byte
+}
+
+// WebhookNotification is a verified notification that something changed at the provider
+type WebhookNotification struct {
+	// EventID identifies the notification; the provider repeats it when it retries
+	EventID string
+	// Type is the kind of resource that changed, e.g. "payment"
+	Type string
+	// ResourceID is the provider's ID of the resource that changed
+	ResourceID string
+}
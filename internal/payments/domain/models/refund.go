@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// RefundStatus represents the status of a refund
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund returns part or all of a payment to the client
+type Refund struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	PaymentID uint         `gorm:"index;not null" json:"payment_id"`
+	Amount    common.Money `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	Reason    string       `gorm:"type:text" json:"reason,omitempty"`
+	Status    RefundStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	// ProviderRefundID is the provider's ID for the refund; empty for payments taken without a checkout
+	ProviderRefundID string `gorm:"type:varchar(255)" json:"provider_refund_id,omitempty"`
+	// Failure is why the provider did not refund, for failed refunds
+	Failure string `gorm:"type:text" json:"failure,omitempty"`
+	// RequestedBy is who asked for the refund, e.g. "staff:3" or "reservation:12" for cancellations
+	RequestedBy string    `gorm:"type:varchar(100);index" json:"requested_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Payment declares the foreign key from PaymentID; it is never loaded
+	Payment *Payment `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+}
+
+// TableName specifies the table name for Refund
+func (Refund) TableName() string {
+	return "payment_refunds"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// WebhookEvent records a provider notification that was processed, so repeats are ignored
+type WebhookEvent struct {
+	ID       uint         `gorm:"primaryKey" json:"id"`
+	Provider ProviderName `gorm:"type:varchar(50);not null;uniqueIndex:idx_webhook_events_provider_event" json:"provider"`
+	EventID  string       `gorm:"type:varchar(255);not null;uniqueIndex:idx_webhook_events_provider_event" json:"event_id"`
+	Type     string       `gorm:"type:varchar(50)" json:"type"`
+	// PaymentID is the payment the notification was about, if any
+	PaymentID *uint     `gorm:"index" json:"payment_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for WebhookEvent
+func (WebhookEvent) TableName() string {
+	return "payment_webhook_events"
+}
+
+// WebhookResult is the outcome of processing a provider notification
+type WebhookResult struct {
+	// Duplicate is true if the notification had already been processed
+	Duplicate bool
+	// Payment is the payment the notification was about, nil if it was about none of ours
+	Payment *Payment
+}
@@ -0,0 +1,256 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+)
+
+// PaymentProvider is a payment gateway that hosts checkouts, such as Mercado Pago.
+// Errors returned by a provider are treated as upstream failures.
+type PaymentProvider interface {
+	Name() models.ProviderName
+	// CreateCheckout creates a hosted checkout the client is sent to
+	CreateCheckout(ctx context.Context, request models.CheckoutRequest) (*models.Checkout, error)
+	// GetPayment fetches the current state of a checkout by its ExternalID
+	GetPayment(ctx context.Context, externalID string) (*models.ProviderPayment, error)
+	// Refund returns amount of what was paid through a checkout to the client
+	Refund(ctx context.Context, externalID string, amount common.Money) (*models.ProviderRefund, error)
+	// VerifyWebhook checks a notification's signature and parses it, without calling the provider.
+	// It returns common.ErrUnauthorized for bad or stale signatures and common.ErrInvalidInput for malformed notifications.
+	VerifyWebhook(request models.WebhookRequest) (*models.WebhookNotification, error)
+	// WebhookCheckout returns the ExternalID of the checkout a notification is about, or "" if it is about none
+	WebhookCheckout(ctx context.Context, notification models.WebhookNotification) (string, error)
+}
+
+// ReservationReader looks up the reservation a payment is for
+type ReservationReader interface {
+	GetReservation(ctx context.Context, id uint) (*reservationModels.Reservation, error)
+}
+
+// CashPaymentInput holds the data needed to record a payment taken in cash
+type CashPaymentInput struct {
+	ReservationID uint
+	// Amount is how much was taken; zero takes everything outstanding.
+	// Without a currency it is in the reservation's currency.
+	Amount     common.Money
+	RecordedBy string
+}
+
+// CheckoutUseCase handles charging reservations, through a payment provider or in person
+type CheckoutUseCase struct {
+	repo         PaymentRepository
+	provider     PaymentProvider
+	reservations ReservationReader
+	deposit      models.DepositPolicy
+}
+
+// NewCheckoutUseCase creates a new checkout use case
+func NewCheckoutUseCase(repo PaymentRepository, provider PaymentProvider, reservations ReservationReader, deposit models.DepositPolicy) *CheckoutUseCase {
+	return &CheckoutUseCase{
+		repo:         repo,
+		provider:     provider,
+		reservations: reservations,
+		deposit:      deposit,
+	}
+}
+
+// CreateCheckout starts a payment of what is owed on a reservation and returns it with the
+// provider's checkout URL. If a checkout for the same amount is already pending it is returned instead.
+func (uc *CheckoutUseCase) CreateCheckout(ctx context.Context, reservationID uint) (*models.Payment, error) {
+	reservation, payments, err := uc.payable(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	owed, err := unpaidAmount(reservation.Price, payments)
+	if err != nil {
+		return nil, err
+	}
+	if !owed.IsPositive() {
+		return nil, fmt.Errorf("%w: reservation %d is already paid", common.ErrConflict, reservationID)
+	}
+	return uc.checkout(ctx, reservation, payments, owed, fmt.Sprintf("Lavado - reserva #%d", reservationID))
+}
+
+// CreateDepositCheckout starts a payment of the deposit on a reservation, less what has been paid
+// already, leaving the balance to be paid at the bay. Like CreateCheckout it returns a pending
+// checkout for the same amount instead of starting another.
+func (uc *CheckoutUseCase) CreateDepositCheckout(ctx context.Context, reservationID uint) (*models.Payment, error) {
+	if !uc.deposit.Enabled() {
+		return nil, fmt.Errorf("%w: deposits are not enabled", common.ErrInvalidInput)
+	}
+
+	reservation, payments, err := uc.payable(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := unpaidAmount(uc.deposit.Amount(reservation.Price), payments)
+	if err != nil {
+		return nil, err
+	}
+	if !due.IsPositive() {
+		return nil, fmt.Errorf("%w: the deposit for reservation %d is already paid", common.ErrConflict, reservationID)
+	}
+	return uc.checkout(ctx, reservation, payments, due, fmt.Sprintf("Seña - reserva #%d", reservationID))
+}
+
+// RecordCashPayment records a payment taken in cash at the bay. Completed reservations may still
+// be paid, as staff can complete a reservation before its balance is settled.
+func (uc *CheckoutUseCase) RecordCashPayment(ctx context.Context, input CashPaymentInput) (*models.Payment, error) {
+	// The reservation and its balance are checked and the payment recorded without other payments or a
+	// cancellation in between, so concurrent payments cannot together pay more than is owed and a
+	// reservation cancelled meanwhile is not paid
+	var payment *models.Payment
+	var recordErr error
+	err := uc.repo.WithPayments(ctx, input.ReservationID, func(payments []models.Payment) error {
+		payment, recordErr = uc.recordCash(ctx, payments, input)
+		return recordErr
+	})
+	if recordErr != nil {
+		return nil, recordErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return payment, nil
+}
+
+// recordCash records a cash payment of up to what is left to pay of the reservation, given its payments
+func (uc *CheckoutUseCase) recordCash(ctx context.Context, payments []models.Payment, input CashPaymentInput) (*models.Payment, error) {
+	reservation, err := uc.reservations.GetReservation(ctx, input.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case reservationModels.ReservationStatusPending, reservationModels.ReservationStatusConfirmed, reservationModels.ReservationStatusCompleted:
+	default:
+		return nil, fmt.Errorf("%w: reservation %d is %s", common.ErrConflict, input.ReservationID, reservation.Status)
+	}
+
+	outstanding, err := unpaidAmount(reservation.Price, payments)
+	if err != nil {
+		return nil, err
+	}
+	if !outstanding.IsPositive() {
+		return nil, fmt.Errorf("%w: reservation %d is already paid", common.ErrConflict, reservation.ID)
+	}
+
+	amount, err := cashAmount(outstanding, input.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &models.Payment{
+		ReservationID:  reservation.ID,
+		Amount:         amount,
+		RefundedAmount: common.Money{Currency: amount.Currency},
+		Status:         models.PaymentStatusCompleted,
+		Method:         models.PaymentMethodCash,
+		RecordedBy:     input.RecordedBy,
+	}
+	if err := uc.repo.Create(ctx, payment); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return payment, nil
+}
+
+// payable loads a reservation that can be paid by checkout together with its payments
+func (uc *CheckoutUseCase) payable(ctx context.Context, reservationID uint) (*reservationModels.Reservation, []models.Payment, error) {
+	reservation, err := uc.reservations.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if reservation.Status != reservationModels.ReservationStatusPending && reservation.Status != reservationModels.ReservationStatusConfirmed {
+		return nil, nil, fmt.Errorf("%w: reservation %d is %s", common.ErrConflict, reservationID, reservation.Status)
+	}
+	if !reservation.Price.IsPositive() {
+		return nil, nil, fmt.Errorf("%w: reservation %d has no price to pay", common.ErrInvalidInput, reservationID)
+	}
+
+	payments, err := uc.repo.FindByReservationID(ctx, reservationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return reservation, payments, nil
+}
+
+// checkout creates a provider checkout for amount, unless one for the same amount is already pending
+func (uc *CheckoutUseCase) checkout(ctx context.Context, reservation *reservationModels.Reservation, payments []models.Payment, amount common.Money, title string) (*models.Payment, error) {
+	for i := range payments {
+		if payments[i].Status == models.PaymentStatusPending && payments[i].CheckoutURL != "" && payments[i].Amount.Equal(amount) {
+			return &payments[i], nil
+		}
+	}
+
+	payment := &models.Payment{
+		ReservationID:  reservation.ID,
+		Amount:         amount,
+		RefundedAmount: common.Money{Currency: amount.Currency},
+		Status:         models.PaymentStatusPending,
+		Method:         models.PaymentMethodCheckout,
+		Provider:       uc.provider.Name(),
+	}
+	if err := uc.repo.Create(ctx, payment); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	request := models.CheckoutRequest{
+		Reference: strconv.FormatUint(uint64(payment.ID), 10),
+		Title:     title,
+		Amount:    amount,
+	}
+	if reservation.ExpiresAt != nil {
+		request.ExpiresAt = *reservation.ExpiresAt
+	}
+
+	checkout, err := uc.provider.CreateCheckout(ctx, request)
+	if err != nil {
+		payment.Status = models.PaymentStatusFailed
+		if updateErr := uc.repo.Update(ctx, payment); updateErr != nil {
+			common.Logger.Error("Failed to mark payment failed", zap.Uint("payment_id", payment.ID), zap.Error(updateErr))
+		}
+		return nil, fmt.Errorf("%w: creating %s checkout: %v", common.ErrUpstream, uc.provider.Name(), err)
+	}
+
+	payment.ExternalID = checkout.ExternalID
+	payment.CheckoutURL = checkout.URL
+	if err := uc.repo.Update(ctx, payment); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return payment, nil
+}
+
+// cashAmount resolves and checks how much of the outstanding balance a cash payment takes
+func cashAmount(outstanding, requested common.Money) (common.Money, error) {
+	if requested.IsZero() {
+		return outstanding, nil
+	}
+
+	if requested.Currency == "" {
+		requested.Currency = outstanding.Currency
+	}
+	if err := requested.Validate(); err != nil {
+		return common.Money{}, err
+	}
+	if requested.Currency != outstanding.Currency {
+		return common.Money{}, fmt.Errorf("%w: reservation is priced in %s", common.ErrInvalidInput, outstanding.Currency)
+	}
+	exceeds, err := outstanding.LessThan(requested)
+	if err != nil {
+		return common.Money{}, err
+	}
+	if exceeds {
+		return common.Money{}, fmt.Errorf("%w: payment of %s exceeds outstanding balance of %s", common.ErrInvalidInput, requested, outstanding)
+	}
+	return requested, nil
+}
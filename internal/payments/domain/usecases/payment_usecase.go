@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+)
+
+// PaymentRepository defines the interface for payment data access
+type PaymentRepository interface {
+	FindAll(ctx context.Context) ([]models.Payment, error)
+	FindByID(ctx context.Context, id uint) (*models.Payment, error)
+	// FindByExternalID returns the payment with the given provider checkout ID, or common.ErrNotFound
+	FindByExternalID(ctx context.Context, provider models.ProviderName, externalID string) (*models.Payment, error)
+	FindByReservationID(ctx context.Context, reservationID uint) ([]models.Payment, error)
+	// FindByUserID returns the payments of the reservations booked by userID
+	FindByUserID(ctx context.Context, userID uint) ([]models.Payment, error)
+	Create(ctx context.Context, payment *models.Payment) error
+	Update(ctx context.Context, payment *models.Payment) error
+	// UpdateState sets the status and refunded amount of payment if neither changed since it was loaded,
+	// or returns common.ErrConflict
+	UpdateState(ctx context.Context, payment *models.Payment, status models.PaymentStatus, refunded common.Money) error
+	// WithPayments calls fn with the payments of a reservation; what has been paid does not change until fn returns
+	WithPayments(ctx context.Context, reservationID uint, fn func(payments []models.Payment) error) error
+}
+
+// PaymentUseCase handles payment business logic
+type PaymentUseCase struct {
+	repo PaymentRepository
+}
+
+// NewPaymentUseCase creates a new payment use case
+func NewPaymentUseCase(repo PaymentRepository) *PaymentUseCase {
+	return &PaymentUseCase{
+		repo: repo,
+	}
+}
+
+// GetPayment returns a payment by ID
+func (uc *PaymentUseCase) GetPayment(ctx context.Context, id uint) (*models.Payment, error) {
+	payment, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: payment %d", common.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return payment, nil
+}
+
+// ListReservationPayments returns the payments of a reservation, oldest first
+func (uc *PaymentUseCase) ListReservationPayments(ctx context.Context, reservationID uint) ([]models.Payment, error) {
+	payments, err := uc.repo.FindByReservationID(ctx, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return payments, nil
+}
+
+// ListUserPayments returns the payments of a client's reservations, newest first
+func (uc *PaymentUseCase) ListUserPayments(ctx context.Context, userID uint) ([]models.Payment, error) {
+	payments, err := uc.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return payments, nil
+}
+
+// paidAmount returns the captured, not yet refunded amount of payments,
+// or common.ErrConflict if they are in different currencies
+func paidAmount(payments []models.Payment) (common.Money, error) {
+	var total common.Money
+	for i := range payments {
+		refundable, err := payments[i].RefundableAmount()
+		if err != nil {
+			return common.Money{}, err
+		}
+		if total, err = total.Add(refundable); err != nil {
+			return common.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// unpaidAmount returns what is left of total once payments are taken off,
+// or common.ErrConflict if they are in different currencies
+func unpaidAmount(total common.Money, payments []models.Payment) (common.Money, error) {
+	paid, err := paidAmount(payments)
+	if err != nil {
+		return common.Money{}, err
+	}
+	return total.Sub(paid)
+}
@@ -0,0 +1,439 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+)
+
+// RefundRepository defines the interface for refund data access
+type RefundRepository interface {
+	// FindByPaymentID returns the refunds of a payment, oldest first
+	FindByPaymentID(ctx context.Context, paymentID uint) ([]models.Refund, error)
+	// FindByRequestedBy returns the refunds asked for by requestedBy, oldest first
+	FindByRequestedBy(ctx context.Context, requestedBy string) ([]models.Refund, error)
+	Create(ctx context.Context, refund *models.Refund) error
+	Update(ctx context.Context, refund *models.Refund) error
+	// Complete saves a processed refund and moves the payment it refunds to status and refunded, in a
+	// single transaction. Returns common.ErrConflict if the payment changed since it was loaded.
+	Complete(ctx context.Context, refund *models.Refund, payment *models.Payment, status models.PaymentStatus, refunded common.Money) error
+}
+
+// RefundInput holds the data needed to refund a payment
+type RefundInput struct {
+	// Amount is how much to refund; zero refunds everything not refunded yet.
+	// Without a currency it is in the payment's currency.
+	Amount      common.Money
+	Reason      string
+	RequestedBy string
+}
+
+// RefundUseCase handles refunding payments, through the payment provider for payments taken by checkout
+type RefundUseCase struct {
+	repo     PaymentRepository
+	refunds  RefundRepository
+	provider PaymentProvider
+}
+
+// NewRefundUseCase creates a new refund use case
+func NewRefundUseCase(repo PaymentRepository, refunds RefundRepository, provider PaymentProvider) *RefundUseCase {
+	return &RefundUseCase{
+		repo:     repo,
+		refunds:  refunds,
+		provider: provider,
+	}
+}
+
+// ListPaymentRefunds returns the refunds of a payment, oldest first
+func (uc *RefundUseCase) ListPaymentRefunds(ctx context.Context, paymentID uint) ([]models.Refund, error) {
+	refunds, err := uc.refunds.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return refunds, nil
+}
+
+// RefundPayment refunds part or all of a captured payment. The payment moves to refunded once
+// nothing is left to refund, and to partially_refunded otherwise.
+func (uc *RefundUseCase) RefundPayment(ctx context.Context, paymentID uint, input RefundInput) (*models.Refund, error) {
+	payment, err := uc.repo.FindByID(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: payment %d", common.ErrNotFound, paymentID)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return uc.refund(ctx, payment, input.Amount, input.Reason, input.RequestedBy)
+}
+
+// PaidAmount returns the captured, not yet refunded amount for a reservation
+func (uc *RefundUseCase) PaidAmount(ctx context.Context, reservationID uint) (common.Money, error) {
+	payments, err := uc.repo.FindByReservationID(ctx, reservationID)
+	if err != nil {
+		return common.Money{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return paidAmount(payments)
+}
+
+// WithPaidAmount calls fn with the captured, not yet refunded amount for a reservation.
+// No payment or refund changes it until fn returns, so fn can act on the reservation's balance.
+func (uc *RefundUseCase) WithPaidAmount(ctx context.Context, reservationID uint, fn func(paid common.Money) error) error {
+	var fnErr error
+	err := uc.repo.WithPayments(ctx, reservationID, func(payments []models.Payment) error {
+		paid, err := paidAmount(payments)
+		if err != nil {
+			fnErr = err
+		} else {
+			fnErr = fn(paid)
+		}
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// RefundReservation makes sure amount has been refunded across the reservation's captured payments,
+// oldest first, recording a refund for each payment it takes from. Refunds already made for the
+// reservation count toward amount, so a call that failed partway can be retried.
+func (uc *RefundUseCase) RefundReservation(ctx context.Context, reservationID uint, amount common.Money) error {
+	requestedBy := reservationRefundRequester(reservationID)
+
+	earlier, err := uc.refundedFor(ctx, requestedBy)
+	if err != nil {
+		return err
+	}
+	if amount, err = amount.Sub(earlier); err != nil {
+		return err
+	}
+
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	payments, err := uc.repo.FindByReservationID(ctx, reservationID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	// Check the whole amount can be refunded before refunding any payment
+	paid, err := paidAmount(payments)
+	if err != nil {
+		return err
+	}
+	short, err := paid.LessThan(amount)
+	if err != nil {
+		return err
+	}
+	if short {
+		return fmt.Errorf("%w: refund of %s exceeds refundable balance", common.ErrInvalidInput, amount)
+	}
+
+	remaining := amount
+	for i := range payments {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		refundable, err := payments[i].RefundableAmount()
+		if err != nil {
+			return err
+		}
+		if !refundable.IsPositive() {
+			continue
+		}
+
+		share, err := refundable.Min(remaining)
+		if err != nil {
+			return err
+		}
+		if _, err := uc.refund(ctx, &payments[i], share, "reservation cancelled", requestedBy); err != nil {
+			return err
+		}
+		if remaining, err = remaining.Sub(share); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefundUnaccounted refunds from a completed payment whatever its reservation's payments hold beyond
+// accounted, the paid amount a cancellation of the reservation was worked out from. Money that arrives
+// after a reservation was cancelled is returned in full, while the cancellation fee is kept. Refunds made
+// for the cancellation since count as held, as they came out of accounted. Returns nil if nothing is owed.
+func (uc *RefundUseCase) RefundUnaccounted(ctx context.Context, payment *models.Payment, accounted common.Money, input RefundInput) (*models.Refund, error) {
+	var amount common.Money
+	var fnErr error
+	err := uc.repo.WithPayments(ctx, payment.ReservationID, func(payments []models.Payment) error {
+		amount, fnErr = uc.unaccountedAmount(ctx, payment.ID, payments, accounted)
+		return fnErr
+	})
+	if fnErr != nil {
+		return nil, fnErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+	input.Amount = amount
+	return uc.RefundPayment(ctx, payment.ID, input)
+}
+
+// unaccountedAmount returns how much of payment paymentID to refund for RefundUnaccounted
+func (uc *RefundUseCase) unaccountedAmount(ctx context.Context, paymentID uint, payments []models.Payment, accounted common.Money) (common.Money, error) {
+	payment := findPayment(payments, paymentID)
+	if payment == nil {
+		return common.Money{}, fmt.Errorf("%w: payment %d", common.ErrNotFound, paymentID)
+	}
+	refundable, err := payment.RefundableAmount()
+	if err != nil {
+		return common.Money{}, err
+	}
+
+	held, err := paidAmount(payments)
+	if err != nil {
+		return common.Money{}, err
+	}
+	refunded, err := uc.refundedFor(ctx, reservationRefundRequester(payments[0].ReservationID))
+	if err != nil {
+		return common.Money{}, err
+	}
+	if held, err = held.Add(refunded); err != nil {
+		return common.Money{}, err
+	}
+	owed, err := held.Sub(accounted)
+	if err != nil {
+		return common.Money{}, err
+	}
+	return owed.Min(refundable)
+}
+
+// refundedFor returns the total of the succeeded refunds asked for by requestedBy
+func (uc *RefundUseCase) refundedFor(ctx context.Context, requestedBy string) (common.Money, error) {
+	refunds, err := uc.refunds.FindByRequestedBy(ctx, requestedBy)
+	if err != nil {
+		return common.Money{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return sumRefunds(refunds, models.RefundStatusSucceeded)
+}
+
+// paymentRefunds returns the total of a payment's refunds in status
+func (uc *RefundUseCase) paymentRefunds(ctx context.Context, paymentID uint, status models.RefundStatus) (common.Money, error) {
+	refunds, err := uc.refunds.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		return common.Money{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return sumRefunds(refunds, status)
+}
+
+// sumRefunds returns the total of the refunds in status
+func sumRefunds(refunds []models.Refund, status models.RefundStatus) (common.Money, error) {
+	var total common.Money
+	for _, refund := range refunds {
+		if refund.Status != status {
+			continue
+		}
+		var err error
+		if total, err = total.Add(refund.Amount); err != nil {
+			return common.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// findPayment returns the payment with id among payments, or nil
+func findPayment(payments []models.Payment, id uint) *models.Payment {
+	for i := range payments {
+		if payments[i].ID == id {
+			return &payments[i]
+		}
+	}
+	return nil
+}
+
+// reservationRefundRequester is who RefundReservation records its refunds as asked for by
+func reservationRefundRequester(reservationID uint) string {
+	return fmt.Sprintf("reservation:%d", reservationID)
+}
+
+// refund records a refund of requested (everything refundable if zero), has the provider return it if
+// the payment was taken by checkout, and updates the payment. A refund the provider rejects is kept as failed.
+func (uc *RefundUseCase) refund(ctx context.Context, payment *models.Payment, requested common.Money, reason, requestedBy string) (*models.Refund, error) {
+	refund := &models.Refund{
+		PaymentID:   payment.ID,
+		Reason:      reason,
+		Status:      models.RefundStatusPending,
+		RequestedBy: requestedBy,
+	}
+
+	// Check and claim the amount under the ledger lock, so concurrent refunds of the payment cannot
+	// both pass the check. The provider is called after the lock is released, as it notifies the
+	// webhook, which updates the payment, while processing the refund.
+	var claimErr error
+	err := uc.repo.WithPayments(ctx, payment.ReservationID, func(payments []models.Payment) error {
+		claimErr = uc.claim(ctx, payment, payments, requested, refund)
+		return claimErr
+	})
+	if claimErr != nil {
+		return nil, claimErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if payment.ExternalID != "" {
+		processed, err := uc.provider.Refund(ctx, payment.ExternalID, refund.Amount)
+		if err != nil {
+			refund.Status = models.RefundStatusFailed
+			refund.Failure = err.Error()
+			if updateErr := uc.refunds.Update(ctx, refund); updateErr != nil {
+				common.Logger.Error("Failed to record failed refund", zap.Uint("refund_id", refund.ID), zap.Error(updateErr))
+			}
+			return nil, fmt.Errorf("%w: refunding %s payment: %v", common.ErrUpstream, uc.provider.Name(), err)
+		}
+		refund.ProviderRefundID = processed.ID
+	}
+
+	refund.Status = models.RefundStatusSucceeded
+	if err := uc.complete(ctx, refund, payment); err != nil {
+		common.Logger.Error("Failed to record processed refund",
+			zap.Uint("refund_id", refund.ID),
+			zap.Uint("payment_id", payment.ID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return refund, nil
+}
+
+// claim reloads payment from payments, the reservation's payments read under the ledger lock, checks
+// requested can still be refunded from it and records refund as pending for the amount. Pending
+// refunds count as refunded, as the provider may be returning them.
+func (uc *RefundUseCase) claim(ctx context.Context, payment *models.Payment, payments []models.Payment, requested common.Money, refund *models.Refund) error {
+	current := findPayment(payments, payment.ID)
+	if current == nil {
+		return fmt.Errorf("%w: payment %d", common.ErrNotFound, payment.ID)
+	}
+	*payment = *current
+
+	if payment.ExternalID != "" && payment.Provider != uc.provider.Name() {
+		return fmt.Errorf("%w: payment %d was taken through %s", common.ErrConflict, payment.ID, payment.Provider)
+	}
+
+	inFlight, err := uc.paymentRefunds(ctx, payment.ID, models.RefundStatusPending)
+	if err != nil {
+		return err
+	}
+	amount, err := refundAmount(payment, inFlight, requested)
+	if err != nil {
+		return err
+	}
+
+	refund.Amount = amount
+	if err := uc.refunds.Create(ctx, refund); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// complete saves a processed refund and adds it to what has been refunded of payment, reloading the
+// payment and trying again if it changed meanwhile. The refunded amount is the total of the payment's
+// succeeded refunds; the provider's notification for the refund may already have counted it, along with
+// refunds made outside the API, in which case the larger stored amount is kept.
+func (uc *RefundUseCase) complete(ctx context.Context, refund *models.Refund, payment *models.Payment) error {
+	for attempt := 1; ; attempt++ {
+		settled, err := uc.paymentRefunds(ctx, payment.ID, models.RefundStatusSucceeded)
+		if err != nil {
+			return err
+		}
+		refunded, err := settled.Add(refund.Amount)
+		if err != nil {
+			return err
+		}
+		behind, err := refunded.LessThan(payment.RefundedAmount)
+		if err != nil {
+			return err
+		}
+		if behind {
+			refunded = payment.RefundedAmount
+		}
+		partial, err := refunded.LessThan(payment.Amount)
+		if err != nil {
+			return err
+		}
+
+		status := models.PaymentStatusRefunded
+		if partial {
+			status = models.PaymentStatusPartiallyRefunded
+		}
+
+		err = uc.refunds.Complete(ctx, refund, payment, status, refunded)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, common.ErrConflict) {
+			return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+		if attempt == stateUpdateAttempts {
+			return err
+		}
+
+		current, err := uc.repo.FindByID(ctx, payment.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+		*payment = *current
+	}
+}
+
+// refundAmount resolves and checks how much to refund of a payment, given the requested amount
+// and the refunds of it still in flight
+func refundAmount(payment *models.Payment, inFlight, requested common.Money) (common.Money, error) {
+	refundable, err := payment.RefundableAmount()
+	if err != nil {
+		return common.Money{}, err
+	}
+	if refundable, err = refundable.Sub(inFlight); err != nil {
+		return common.Money{}, err
+	}
+	if !refundable.IsPositive() {
+		if inFlight.IsPositive() {
+			return common.Money{}, fmt.Errorf("%w: payment %d has nothing to refund besides refunds in progress", common.ErrConflict, payment.ID)
+		}
+		return common.Money{}, fmt.Errorf("%w: payment %d is %s and has nothing to refund", common.ErrConflict, payment.ID, payment.Status)
+	}
+	if requested.IsZero() {
+		return refundable, nil
+	}
+
+	if requested.Currency == "" {
+		requested.Currency = refundable.Currency
+	}
+	if err := requested.Validate(); err != nil {
+		return common.Money{}, err
+	}
+	if requested.Currency != refundable.Currency {
+		return common.Money{}, fmt.Errorf("%w: payment %d is in %s", common.ErrInvalidInput, payment.ID, refundable.Currency)
+	}
+	exceeds, err := refundable.LessThan(requested)
+	if err != nil {
+		return common.Money{}, err
+	}
+	if exceeds {
+		return common.Money{}, fmt.Errorf("%w: refund of %s exceeds refundable balance of %s", common.ErrInvalidInput, requested, refundable)
+	}
+	return requested, nil
+}
@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// stateUpdateAttempts is how many times a provider state is applied to a payment that keeps
+// changing under it, e.g. because refunds are being recorded
+const stateUpdateAttempts = 3
+
+// WebhookEventRepository records processed provider notifications
+type WebhookEventRepository interface {
+	// Create records an event, returning common.ErrConflict if its provider event ID is already recorded
+	Create(ctx context.Context, event *models.WebhookEvent) error
+	Update(ctx context.Context, event *models.WebhookEvent) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// ReservationConfirmer confirms the reservation a completed payment is for
+type ReservationConfirmer interface {
+	ReservationReader
+	ConfirmReservation(ctx context.Context, id uint, input reservationUsecases.StatusChangeInput) (*reservationModels.Reservation, error)
+	GetCancellation(ctx context.Context, reservationID uint) (*reservationModels.ReservationCancellation, error)
+}
+
+// WebhookUseCase applies payment provider notifications to payments
+type WebhookUseCase struct {
+	repo         PaymentRepository
+	events       WebhookEventRepository
+	provider     PaymentProvider
+	reservations ReservationConfirmer
+	refunds      *RefundUseCase
+}
+
+// NewWebhookUseCase creates a new webhook use case.
+// Payments completed for holds that were released meanwhile are refunded through refunds.
+func NewWebhookUseCase(repo PaymentRepository, events WebhookEventRepository, provider PaymentProvider, reservations ReservationConfirmer, refunds *RefundUseCase) *WebhookUseCase {
+	return &WebhookUseCase{
+		repo:         repo,
+		events:       events,
+		provider:     provider,
+		reservations: reservations,
+		refunds:      refunds,
+	}
+}
+
+// HandleWebhook verifies a provider notification and brings the payment it is about up to date
+// with the provider, confirming the reservation once the payment completes.
+// Each notification is processed once; if processing fails it is forgotten, so the provider's retry is processed.
+func (uc *WebhookUseCase) HandleWebhook(ctx context.Context, request models.WebhookRequest) (*models.WebhookResult, error) {
+	notification, err := uc.provider.VerifyWebhook(request)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.WebhookEvent{
+		Provider: uc.provider.Name(),
+		EventID:  notification.EventID,
+		Type:     notification.Type,
+	}
+	if err := uc.events.Create(ctx, event); err != nil {
+		if errors.Is(err, common.ErrConflict) {
+			return &models.WebhookResult{Duplicate: true}, nil
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	payment, err := uc.process(ctx, *notification)
+	if err != nil {
+		if deleteErr := uc.events.Delete(ctx, event.ID); deleteErr != nil {
+			common.Logger.Error("Failed to forget webhook event", zap.String("event_id", event.EventID), zap.Error(deleteErr))
+		}
+		return nil, err
+	}
+
+	if payment != nil {
+		event.PaymentID = &payment.ID
+		if err := uc.events.Update(ctx, event); err != nil {
+			common.Logger.Error("Failed to link webhook event to payment", zap.String("event_id", event.EventID), zap.Error(err))
+		}
+	}
+	return &models.WebhookResult{Payment: payment}, nil
+}
+
+// process syncs the payment a notification is about, returning nil if it is about none of ours
+func (uc *WebhookUseCase) process(ctx context.Context, notification models.WebhookNotification) (*models.Payment, error) {
+	externalID, err := uc.provider.WebhookCheckout(ctx, notification)
+	if err != nil {
+		return nil, fmt.Errorf("%w: resolving %s notification: %v", common.ErrUpstream, uc.provider.Name(), err)
+	}
+	if externalID == "" {
+		return nil, nil
+	}
+
+	payment, err := uc.repo.FindByExternalID(ctx, uc.provider.Name(), externalID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			common.Logger.Warn("Webhook for an unknown checkout", zap.String("external_id", externalID))
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	state, err := uc.provider.GetPayment(ctx, externalID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fetching %s payment: %v", common.ErrUpstream, uc.provider.Name(), err)
+	}
+
+	if err := uc.applyState(ctx, payment, state); err != nil {
+		return nil, err
+	}
+
+	if payment.Status == models.PaymentStatusCompleted {
+		if err := uc.confirmReservation(ctx, payment); err != nil {
+			return nil, err
+		}
+	}
+	return payment, nil
+}
+
+// applyState moves a payment to the status reported by the provider. Notifications can arrive
+// out of order, so transitions the state machine does not allow are ignored and the refunded amount
+// never goes down. Only the status and refunded amount are written, and only if a refund has not
+// changed them meanwhile; if one has, the payment is reloaded and the state applied to it again.
+func (uc *WebhookUseCase) applyState(ctx context.Context, payment *models.Payment, state *models.ProviderPayment) error {
+	for attempt := 1; ; attempt++ {
+		refunded := state.RefundedAmount
+		behind, err := refunded.LessThan(payment.RefundedAmount)
+		if err != nil {
+			common.Logger.Error("Ignoring payment state in another currency reported by provider",
+				zap.Uint("payment_id", payment.ID),
+				zap.String("refunded", state.RefundedAmount.String()),
+				zap.Error(err),
+			)
+			return nil
+		}
+		if behind {
+			refunded = payment.RefundedAmount
+		}
+
+		if state.Status == payment.Status && refunded.Equal(payment.RefundedAmount) {
+			return nil
+		}
+		if state.Status != payment.Status && !payment.Status.CanTransitionTo(state.Status) {
+			common.Logger.Warn("Ignoring payment status reported by provider",
+				zap.Uint("payment_id", payment.ID),
+				zap.String("from", string(payment.Status)),
+				zap.String("to", string(state.Status)),
+			)
+			return nil
+		}
+
+		err = uc.repo.UpdateState(ctx, payment, state.Status, refunded)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, common.ErrConflict) {
+			return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+		if attempt == stateUpdateAttempts {
+			return err
+		}
+
+		current, err := uc.repo.FindByID(ctx, payment.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+		*payment = *current
+	}
+}
+
+// confirmReservation confirms the pending reservation a completed payment is for.
+// A reservation cancelled before the payment completed did not count it toward its cancellation
+// refund, and a hold that expired no longer keeps its slot, so the payment is refunded.
+// Other reservations that can no longer be confirmed are left for staff.
+func (uc *WebhookUseCase) confirmReservation(ctx context.Context, payment *models.Payment) error {
+	reservation, err := uc.reservations.GetReservation(ctx, payment.ReservationID)
+	if err != nil {
+		return err
+	}
+
+	if reservation.Status == reservationModels.ReservationStatusPending {
+		_, err = uc.reservations.ConfirmReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{
+			ChangedBy: fmt.Sprintf("payment:%d", payment.ID),
+			Reason:    "payment completed",
+		})
+		if !errors.Is(err, common.ErrConflict) {
+			return err
+		}
+
+		// The hold expired, or the reservation changed since it was loaded
+		conflict := err
+		if reservation, err = uc.reservations.GetReservation(ctx, payment.ReservationID); err != nil {
+			return err
+		}
+		if !reservation.IsHold() && reservation.Status == reservationModels.ReservationStatusPending {
+			common.Logger.Warn("Paid reservation could not be confirmed",
+				zap.Uint("reservation_id", reservation.ID),
+				zap.Uint("payment_id", payment.ID),
+				zap.Error(conflict),
+			)
+			return nil
+		}
+	}
+
+	switch {
+	case reservation.Status == reservationModels.ReservationStatusCancelled:
+		// Holds released when they expired have no cancellation and counted nothing
+		var accounted common.Money
+		cancellation, err := uc.reservations.GetCancellation(ctx, reservation.ID)
+		if err == nil {
+			accounted = cancellation.PaidAmount
+		} else if !errors.Is(err, common.ErrNotFound) {
+			return err
+		}
+		return uc.refundUnaccounted(ctx, payment, accounted)
+	case reservation.IsHold():
+		return uc.refundUnaccounted(ctx, payment, common.Money{})
+	}
+	return nil
+}
+
+// refundUnaccounted refunds what a payment adds to a cancelled or released reservation beyond
+// accounted, the paid amount its cancellation counted, and reloads the payment. If the refund fails
+// the error is returned, so the notification is processed again when the provider retries it.
+func (uc *WebhookUseCase) refundUnaccounted(ctx context.Context, payment *models.Payment, accounted common.Money) error {
+	refund, err := uc.refunds.RefundUnaccounted(ctx, payment, accounted, RefundInput{
+		Reason:      "reservation cancelled or released before the payment completed",
+		RequestedBy: fmt.Sprintf("payment:%d", payment.ID),
+	})
+	if err != nil {
+		common.Logger.Error("Failed to refund payment for a cancelled reservation", zap.Uint("payment_id", payment.ID), zap.Error(err))
+		return err
+	}
+	if refund == nil {
+		return nil
+	}
+
+	common.Logger.Warn("Refunded payment for a cancelled reservation",
+		zap.Uint("reservation_id", payment.ReservationID),
+		zap.Uint("payment_id", payment.ID),
+		zap.String("amount", refund.Amount.String()),
+	)
+
+	refunded, err := uc.repo.FindByID(ctx, payment.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	*payment = *refunded
+	return nil
+}
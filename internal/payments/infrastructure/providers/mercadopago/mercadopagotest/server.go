@@ -0,0 +1,584 @@
+// Package mercadopagotest provides an in-process fake of the Mercado Pago API for tests and local development.
+//
+// It implements the subset used by the mercadopago provider: preferences, merchant orders, payments
+// and refunds, plus a checkout page where a payment can be approved or rejected by hand.
+// With webhooks enabled, payments and refunds are notified to the preference's notification_url
+// with a signature like Mercado Pago's. Preference expiration is recorded but not enforced.
+package mercadopagotest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Payment statuses as reported by Mercado Pago
+const (
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusPending   = "in_process"
+	StatusRefunded  = "refunded"
+	StatusCancelled = "cancelled"
+)
+
+// Preference is a checkout created through the fake
+type Preference struct {
+	ID                string
+	ExternalReference string
+	Title             string
+	Amount            float64
+	Currency          string
+	NotificationURL   string
+	SuccessURL        string
+	FailureURL        string
+	ExpirationDateTo  string
+}
+
+// Payment is a payment attempt made on a preference
+type Payment struct {
+	ID                int64
+	PreferenceID      string
+	Status            string
+	TransactionAmount float64
+	AmountRefunded    float64
+}
+
+// Notification is a webhook the fake sent
+type Notification struct {
+	URL    string
+	Header http.Header
+	Body   []byte
+	// StatusCode is the receiver's response, or 0 if it could not be reached
+	StatusCode int
+}
+
+// Server is a fake Mercado Pago API. Mount it with httptest.NewServer, or under a path prefix
+// with http.StripPrefix; checkout URLs it hands out include that prefix.
+type Server struct {
+	accessToken string
+	mux         *http.ServeMux
+
+	mu          sync.Mutex
+	nextID      int64
+	preferences map[string]*Preference
+	payments    map[int64]*Payment
+	// attempts lists payment IDs by preference, in the order they were made
+	attempts map[string][]int64
+
+	webhookSecret string
+	notifications []Notification
+}
+
+// NewServer creates a fake that accepts API calls authenticated with accessToken
+func NewServer(accessToken string) *Server {
+	s := &Server{
+		accessToken: accessToken,
+		mux:         http.NewServeMux(),
+		preferences: make(map[string]*Preference),
+		payments:    make(map[int64]*Payment),
+		attempts:    make(map[string][]int64),
+	}
+
+	s.mux.HandleFunc("POST /checkout/preferences", s.authenticated(s.createPreference))
+	s.mux.HandleFunc("GET /merchant_orders/search", s.authenticated(s.searchMerchantOrders))
+	s.mux.HandleFunc("GET /merchant_orders/{id}", s.authenticated(s.getMerchantOrder))
+	s.mux.HandleFunc("GET /v1/payments/{id}", s.authenticated(s.getPayment))
+	s.mux.HandleFunc("POST /v1/payments/{id}/refunds", s.authenticated(s.refundPayment))
+	s.mux.HandleFunc("GET /checkout/v1/redirect", s.checkoutPage)
+	s.mux.HandleFunc("POST /checkout/v1/pay", s.pay)
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// EnableWebhooks makes the fake notify payments and refunds, signing webhooks with secret
+func (s *Server) EnableWebhooks(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookSecret = secret
+}
+
+// Notifications returns the webhooks sent so far, oldest first
+func (s *Server) Notifications() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Notification(nil), s.notifications...)
+}
+
+// Preference returns a copy of the preference with the given ID
+func (s *Server) Preference(id string) (Preference, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preference, ok := s.preferences[id]
+	if !ok {
+		return Preference{}, false
+	}
+	return *preference, true
+}
+
+// Payments returns copies of the payment attempts made on a preference
+func (s *Server) Payments(preferenceID string) []Payment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payments := make([]Payment, 0, len(s.attempts[preferenceID]))
+	for _, id := range s.attempts[preferenceID] {
+		payments = append(payments, *s.payments[id])
+	}
+	return payments
+}
+
+// Pay records a payment attempt for the full amount of a preference, as if the client
+// completed the checkout, and returns its ID
+func (s *Server) Pay(preferenceID, status string) (int64, error) {
+	s.mu.Lock()
+	preference, ok := s.preferences[preferenceID]
+	if !ok {
+		s.mu.Unlock()
+		return 0, fmt.Errorf("mercadopagotest: preference %s not found", preferenceID)
+	}
+
+	s.nextID++
+	payment := &Payment{
+		ID:                s.nextID,
+		PreferenceID:      preference.ID,
+		Status:            status,
+		TransactionAmount: preference.Amount,
+	}
+	s.payments[payment.ID] = payment
+	s.attempts[preference.ID] = append(s.attempts[preference.ID], payment.ID)
+	s.mu.Unlock()
+
+	s.notify(payment.ID, "payment.created")
+	return payment.ID, nil
+}
+
+// Notify sends a webhook about a payment again, as Mercado Pago does when the receiver does not
+// acknowledge one, reusing the event ID of the payment's last notification
+func (s *Server) Notify(paymentID int64) error {
+	s.mu.Lock()
+	var last *Notification
+	for i := range s.notifications {
+		if strings.Contains(s.notifications[i].URL, "data.id="+strconv.FormatInt(paymentID, 10)+"&") {
+			last = &s.notifications[i]
+		}
+	}
+	s.mu.Unlock()
+
+	if last == nil {
+		return fmt.Errorf("mercadopagotest: payment %d was never notified", paymentID)
+	}
+	var body struct {
+		ID     int64  `json:"id"`
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(last.Body, &body); err != nil {
+		return err
+	}
+	s.send(paymentID, body.ID, body.Action)
+	return nil
+}
+
+// notify sends a new webhook about a payment, if webhooks are enabled and its preference has a notification_url
+func (s *Server) notify(paymentID int64, action string) {
+	s.mu.Lock()
+	s.nextID++
+	eventID := s.nextID
+	s.mu.Unlock()
+
+	s.send(paymentID, eventID, action)
+}
+
+// send posts a signed webhook about a payment; s.mu must not be held, as the receiver may call back
+func (s *Server) send(paymentID, eventID int64, action string) {
+	s.mu.Lock()
+	secret := s.webhookSecret
+	preference := s.preferences[s.payments[paymentID].PreferenceID]
+	s.mu.Unlock()
+
+	if secret == "" || preference.NotificationURL == "" {
+		return
+	}
+
+	dataID := strconv.FormatInt(paymentID, 10)
+	separator := "?"
+	if strings.Contains(preference.NotificationURL, "?") {
+		separator = "&"
+	}
+	target := preference.NotificationURL + separator + "data.id=" + dataID + "&type=payment"
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":           eventID,
+		"live_mode":    false,
+		"type":         "payment",
+		"action":       action,
+		"api_version":  "v1",
+		"date_created": time.Now().UTC().Format(time.RFC3339),
+		"data":         map[string]string{"id": dataID},
+	})
+
+	requestID := fmt.Sprintf("fake-request-%d-%d", eventID, time.Now().UnixNano())
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("x-request-id", requestID)
+	header.Set("x-signature", "ts="+ts+",v1="+Sign(secret, dataID, requestID, ts))
+
+	notification := Notification{URL: target, Header: header, Body: body}
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err == nil {
+		req.Header = header.Clone()
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+			notification.StatusCode = resp.StatusCode
+		}
+	}
+
+	s.mu.Lock()
+	s.notifications = append(s.notifications, notification)
+	s.mu.Unlock()
+}
+
+// Sign returns the v1 signature Mercado Pago puts in the x-signature header of a webhook
+func Sign(secret, dataID, requestID, ts string) string {
+	manifest := "id:" + strings.ToLower(dataID) + ";request-id:" + requestID + ";ts:" + ts + ";"
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// authenticated rejects API calls without the fake's access token
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.accessToken {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "invalid access token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// createPreference handles POST /checkout/preferences
+func (s *Server) createPreference(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Items []struct {
+			Title      string  `json:"title"`
+			Quantity   int     `json:"quantity"`
+			UnitPrice  float64 `json:"unit_price"`
+			CurrencyID string  `json:"currency_id"`
+		} `json:"items"`
+		ExternalReference string `json:"external_reference"`
+		NotificationURL   string `json:"notification_url"`
+		BackURLs          struct {
+			Success string `json:"success"`
+			Failure string `json:"failure"`
+		} `json:"back_urls"`
+		ExpirationDateTo string `json:"expiration_date_to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	if len(body.Items) == 0 {
+		writeError(w, http.StatusBadRequest, "bad_request", "items needed")
+		return
+	}
+
+	preference := &Preference{
+		ExternalReference: body.ExternalReference,
+		Title:             body.Items[0].Title,
+		Currency:          body.Items[0].CurrencyID,
+		NotificationURL:   body.NotificationURL,
+		SuccessURL:        body.BackURLs.Success,
+		FailureURL:        body.BackURLs.Failure,
+		ExpirationDateTo:  body.ExpirationDateTo,
+	}
+	for _, item := range body.Items {
+		if item.Quantity < 1 || item.UnitPrice <= 0 {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid item quantity or unit_price")
+			return
+		}
+		preference.Amount += float64(item.Quantity) * item.UnitPrice
+	}
+	preference.Amount = math.Round(preference.Amount*100) / 100
+
+	s.mu.Lock()
+	s.nextID++
+	preference.ID = fmt.Sprintf("fake-pref-%d", s.nextID)
+	s.preferences[preference.ID] = preference
+	s.mu.Unlock()
+
+	initPoint := baseURL(r) + "/checkout/v1/redirect?pref_id=" + url.QueryEscape(preference.ID)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":                 preference.ID,
+		"external_reference": preference.ExternalReference,
+		"init_point":         initPoint,
+		"sandbox_init_point": initPoint,
+	})
+}
+
+// searchMerchantOrders handles GET /merchant_orders/search?preference_id=
+func (s *Server) searchMerchantOrders(w http.ResponseWriter, r *http.Request) {
+	preferenceID := r.URL.Query().Get("preference_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elements := []interface{}{}
+	if order, ok := s.merchantOrderJSON(preferenceID); ok {
+		elements = append(elements, order)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"elements": elements,
+		"total":    len(elements),
+	})
+}
+
+// getMerchantOrder handles GET /merchant_orders/{id}
+func (s *Server) getMerchantOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for preferenceID, preference := range s.preferences {
+		if strconv.FormatInt(preference.orderID(), 10) != r.PathValue("id") {
+			continue
+		}
+		if order, ok := s.merchantOrderJSON(preferenceID); ok {
+			writeJSON(w, http.StatusOK, order)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "merchant order not found")
+}
+
+// getPayment handles GET /v1/payments/{id}
+func (s *Server) getPayment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.findPayment(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "payment not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.paymentJSON(payment))
+}
+
+// refundPayment handles POST /v1/payments/{id}/refunds; without an amount the rest is refunded
+func (s *Server) refundPayment(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Amount *float64 `json:"amount"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	payment, ok := s.findPayment(r)
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "not_found", "payment not found")
+		return
+	}
+	if payment.Status != StatusApproved {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "bad_request", "payment is "+payment.Status)
+		return
+	}
+
+	refundable := math.Round((payment.TransactionAmount-payment.AmountRefunded)*100) / 100
+	amount := refundable
+	if body.Amount != nil {
+		amount = *body.Amount
+	}
+	if amount <= 0 || amount > refundable {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid refund amount")
+		return
+	}
+
+	payment.AmountRefunded = math.Round((payment.AmountRefunded+amount)*100) / 100
+	if payment.AmountRefunded >= payment.TransactionAmount {
+		payment.Status = StatusRefunded
+	}
+
+	s.nextID++
+	refundID := s.nextID
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":         refundID,
+		"payment_id": payment.ID,
+		"amount":     amount,
+		"status":     StatusApproved,
+	})
+	s.notify(payment.ID, "payment.updated")
+}
+
+// checkoutPageTemplate is the page a client is sent to in order to pay
+var checkoutPageTemplate = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Mercado Pago (fake)</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Currency}} {{printf "%.2f" .Amount}}</p>
+<form method="post" action="pay?pref_id={{.ID}}&amp;status=approved"><button>Pagar</button></form>
+<form method="post" action="pay?pref_id={{.ID}}&amp;status=rejected"><button>Rechazar</button></form>
+</body>
+</html>
+`))
+
+// checkoutPage handles GET /checkout/v1/redirect?pref_id=
+func (s *Server) checkoutPage(w http.ResponseWriter, r *http.Request) {
+	preference, ok := s.Preference(r.URL.Query().Get("pref_id"))
+	if !ok {
+		http.Error(w, "preference not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = checkoutPageTemplate.Execute(w, preference)
+}
+
+// pay handles the checkout page's buttons and returns the client to the back URL
+func (s *Server) pay(w http.ResponseWriter, r *http.Request) {
+	preferenceID := r.URL.Query().Get("pref_id")
+	status := r.URL.Query().Get("status")
+	if status != StatusApproved && status != StatusRejected {
+		http.Error(w, "status must be approved or rejected", http.StatusBadRequest)
+		return
+	}
+
+	paymentID, err := s.Pay(preferenceID, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	preference, _ := s.Preference(preferenceID)
+	backURL := preference.FailureURL
+	if status == StatusApproved {
+		backURL = preference.SuccessURL
+	}
+	if backURL == "" {
+		fmt.Fprintf(w, "payment %d %s\n", paymentID, status)
+		return
+	}
+
+	query := url.Values{}
+	query.Set("collection_status", status)
+	query.Set("payment_id", strconv.FormatInt(paymentID, 10))
+	query.Set("preference_id", preferenceID)
+	query.Set("external_reference", preference.ExternalReference)
+	separator := "?"
+	if strings.Contains(backURL, "?") {
+		separator = "&"
+	}
+	http.Redirect(w, r, backURL+separator+query.Encode(), http.StatusSeeOther)
+}
+
+// findPayment returns the payment named by the {id} path value; s.mu must be held
+func (s *Server) findPayment(r *http.Request) (*Payment, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	payment, ok := s.payments[id]
+	return payment, ok
+}
+
+// orderID derives a stable merchant order ID from the preference ID
+func (p *Preference) orderID() int64 {
+	id, _ := strconv.ParseInt(strings.TrimPrefix(p.ID, "fake-pref-"), 10, 64)
+	return id
+}
+
+// merchantOrderJSON renders the merchant order of a preference, which exists once someone tried to pay;
+// s.mu must be held
+func (s *Server) merchantOrderJSON(preferenceID string) (map[string]interface{}, bool) {
+	preference, ok := s.preferences[preferenceID]
+	if !ok || len(s.attempts[preferenceID]) == 0 {
+		return nil, false
+	}
+
+	payments := make([]interface{}, 0, len(s.attempts[preferenceID]))
+	orderStatus := "payment_required"
+	for _, id := range s.attempts[preferenceID] {
+		payment := s.payments[id]
+		payments = append(payments, s.paymentJSON(payment))
+		if payment.Status == StatusApproved {
+			orderStatus = "paid"
+		}
+	}
+
+	return map[string]interface{}{
+		"id":                 preference.orderID(),
+		"preference_id":      preference.ID,
+		"external_reference": preference.ExternalReference,
+		"order_status":       orderStatus,
+		"total_amount":       preference.Amount,
+		"payments":           payments,
+	}, true
+}
+
+// paymentJSON renders a payment as the API does; s.mu must be held
+func (s *Server) paymentJSON(payment *Payment) map[string]interface{} {
+	preference := s.preferences[payment.PreferenceID]
+	return map[string]interface{}{
+		"id":                 payment.ID,
+		"status":             payment.Status,
+		"transaction_amount": payment.TransactionAmount,
+		"amount_refunded":    payment.AmountRefunded,
+		"external_reference": preference.ExternalReference,
+		"order":              map[string]interface{}{"id": preference.orderID(), "type": "mercadopago"},
+	}
+}
+
+// baseURL is the URL the fake is served at, including any prefix stripped before it was called
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	prefix := ""
+	if original, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		prefix = strings.TrimSuffix(original.Path, r.URL.Path)
+	}
+	return scheme + "://" + r.Host + prefix
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in Mercado Pago's format
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"message": message,
+		"error":   code,
+		"status":  status,
+	})
+}
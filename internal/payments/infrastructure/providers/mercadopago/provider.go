@@ -0,0 +1,529 @@
+package mercadopago
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+)
+
+// DefaultBaseURL is the Mercado Pago API
+const DefaultBaseURL = "https://api.mercadopago.com"
+
+// Config holds the settings of the Mercado Pago adapter
+type Config struct {
+	// BaseURL is the API root; DefaultBaseURL unless testing against a fake
+	BaseURL     string
+	AccessToken string
+	// NotificationURL receives Mercado Pago webhooks; empty to rely on polling
+	NotificationURL string
+	// ReturnURL is where the client lands after paying, whatever the outcome
+	ReturnURL string
+	// Sandbox sends clients to the sandbox checkout, for test credentials
+	Sandbox bool
+	// Timeout bounds each API call
+	Timeout time.Duration
+	// WebhookSecret signs webhooks; notifications are rejected without one
+	WebhookSecret string
+	// WebhookTolerance is how far a webhook's signature timestamp may be from now
+	WebhookTolerance time.Duration
+	// Now is the clock webhook timestamps are checked against; time.Now if nil
+	Now common.Clock
+}
+
+// Provider charges clients through Mercado Pago Checkout Pro.
+// A checkout is a preference; its ExternalID is the preference ID, and its state is read from
+// the merchant order Mercado Pago opens once the client attempts to pay.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewProvider creates a Mercado Pago provider
+func NewProvider(cfg Config) *Provider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.WebhookTolerance == 0 {
+		cfg.WebhookTolerance = 5 * time.Minute
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Name identifies the provider on payments
+func (p *Provider) Name() models.ProviderName {
+	return models.ProviderMercadoPago
+}
+
+// preferenceItem is a line of a preference
+type preferenceItem struct {
+	Title      string  `json:"title"`
+	Quantity   int     `json:"quantity"`
+	UnitPrice  float64 `json:"unit_price"`
+	CurrencyID string  `json:"currency_id"`
+}
+
+// backURLs are where the client returns after the checkout
+type backURLs struct {
+	Success string `json:"success"`
+	Failure string `json:"failure"`
+	Pending string `json:"pending"`
+}
+
+// preferenceRequest is the body of POST /checkout/preferences
+type preferenceRequest struct {
+	Items             []preferenceItem `json:"items"`
+	ExternalReference string           `json:"external_reference"`
+	NotificationURL   string           `json:"notification_url,omitempty"`
+	BackURLs          *backURLs        `json:"back_urls,omitempty"`
+	AutoReturn        string           `json:"auto_return,omitempty"`
+	Expires           bool             `json:"expires,omitempty"`
+	ExpirationDateTo  string           `json:"expiration_date_to,omitempty"`
+}
+
+// preference is a created checkout preference
+type preference struct {
+	ID               string `json:"id"`
+	InitPoint        string `json:"init_point"`
+	SandboxInitPoint string `json:"sandbox_init_point"`
+}
+
+// orderPayment is a payment attempt within a merchant order
+type orderPayment struct {
+	ID                int64   `json:"id"`
+	Status            string  `json:"status"`
+	TransactionAmount float64 `json:"transaction_amount"`
+	AmountRefunded    float64 `json:"amount_refunded"`
+	CurrencyID        string  `json:"currency_id"`
+}
+
+// paid returns the captured amount of the payment
+func (o orderPayment) paid() common.Money {
+	return common.MoneyFromMajor(o.TransactionAmount, o.currency())
+}
+
+// refunded returns the refunded amount of the payment
+func (o orderPayment) refunded() common.Money {
+	return common.MoneyFromMajor(o.AmountRefunded, o.currency())
+}
+
+// refundable returns what is left to refund of the payment
+func (o orderPayment) refundable() (common.Money, error) {
+	if o.Status != "approved" {
+		return common.Money{Currency: o.currency()}, nil
+	}
+	return o.paid().Sub(o.refunded())
+}
+
+// currency returns the payment's currency; it is omitted by older API versions
+func (o orderPayment) currency() string {
+	if o.CurrencyID == "" {
+		return common.DefaultCurrency
+	}
+	return o.CurrencyID
+}
+
+// merchantOrder groups the payment attempts made on a preference
+type merchantOrder struct {
+	ID                int64          `json:"id"`
+	PreferenceID      string         `json:"preference_id"`
+	ExternalReference string         `json:"external_reference"`
+	Payments          []orderPayment `json:"payments"`
+}
+
+// payment is a payment as returned by GET /v1/payments/{id}
+type payment struct {
+	ID                int64  `json:"id"`
+	Status            string `json:"status"`
+	ExternalReference string `json:"external_reference"`
+	Order             struct {
+		ID flexibleID `json:"id"`
+	} `json:"order"`
+}
+
+// notification is the body of a webhook
+type notification struct {
+	ID     flexibleID `json:"id"`
+	Type   string     `json:"type"`
+	Action string     `json:"action"`
+	Data   struct {
+		ID flexibleID `json:"id"`
+	} `json:"data"`
+}
+
+// flexibleID is an ID Mercado Pago sends either as a number or as a string
+type flexibleID string
+
+// UnmarshalJSON accepts JSON numbers and strings
+func (id *flexibleID) UnmarshalJSON(data []byte) error {
+	var value json.Number
+	if err := json.Unmarshal(data, &value); err == nil {
+		*id = flexibleID(value)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*id = flexibleID(text)
+	return nil
+}
+
+// merchantOrderSearch is the response of GET /merchant_orders/search
+type merchantOrderSearch struct {
+	Elements []merchantOrder `json:"elements"`
+}
+
+// refund is a refund of a payment
+type refund struct {
+	ID     int64   `json:"id"`
+	Amount float64 `json:"amount"`
+}
+
+// apiError is the error body returned by Mercado Pago
+type apiError struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// CreateCheckout creates a preference for a single item
+func (p *Provider) CreateCheckout(ctx context.Context, request models.CheckoutRequest) (*models.Checkout, error) {
+	body := preferenceRequest{
+		Items: []preferenceItem{{
+			Title:      request.Title,
+			Quantity:   1,
+			UnitPrice:  request.Amount.Major(),
+			CurrencyID: request.Amount.Currency,
+		}},
+		ExternalReference: request.Reference,
+		NotificationURL:   p.cfg.NotificationURL,
+	}
+	if p.cfg.ReturnURL != "" {
+		body.BackURLs = &backURLs{Success: p.cfg.ReturnURL, Failure: p.cfg.ReturnURL, Pending: p.cfg.ReturnURL}
+		body.AutoReturn = "approved"
+	}
+	if !request.ExpiresAt.IsZero() {
+		body.Expires = true
+		body.ExpirationDateTo = request.ExpiresAt.Format("2006-01-02T15:04:05.000-07:00")
+	}
+
+	var created preference
+	if err := p.do(ctx, http.MethodPost, "/checkout/preferences", request.Reference, body, &created); err != nil {
+		return nil, err
+	}
+
+	checkoutURL := created.InitPoint
+	if p.cfg.Sandbox && created.SandboxInitPoint != "" {
+		checkoutURL = created.SandboxInitPoint
+	}
+	return &models.Checkout{ExternalID: created.ID, URL: checkoutURL}, nil
+}
+
+// GetPayment reads the state of a preference from its merchant order
+func (p *Provider) GetPayment(ctx context.Context, externalID string) (*models.ProviderPayment, error) {
+	order, err := p.findOrder(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ProviderPayment{ExternalID: externalID, Status: models.PaymentStatusPending}
+	if order == nil {
+		return result, nil
+	}
+
+	failed := len(order.Payments) > 0
+	for _, payment := range order.Payments {
+		switch payment.Status {
+		case "approved", "refunded":
+			if result.PaidAmount, err = result.PaidAmount.Add(payment.paid()); err != nil {
+				return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+			}
+			if result.RefundedAmount, err = result.RefundedAmount.Add(payment.refunded()); err != nil {
+				return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+			}
+			failed = false
+		case "rejected", "cancelled":
+		default:
+			failed = false
+		}
+	}
+
+	partial, err := result.RefundedAmount.LessThan(result.PaidAmount)
+	if err != nil {
+		return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+	}
+
+	switch {
+	case result.PaidAmount.IsPositive() && !partial:
+		result.Status = models.PaymentStatusRefunded
+	case result.RefundedAmount.IsPositive():
+		result.Status = models.PaymentStatusPartiallyRefunded
+	case result.PaidAmount.IsPositive():
+		result.Status = models.PaymentStatusCompleted
+	case failed:
+		result.Status = models.PaymentStatusFailed
+	}
+	return result, nil
+}
+
+// Refund refunds amount across the approved payments of a preference
+func (p *Provider) Refund(ctx context.Context, externalID string, amount common.Money) (*models.ProviderRefund, error) {
+	order, err := p.findOrder(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("mercadopago: preference %s has no payments to refund", externalID)
+	}
+
+	// Check the whole amount can be refunded before refunding any payment
+	total := common.Money{Currency: amount.Currency}
+	for _, payment := range order.Payments {
+		refundable, err := payment.refundable()
+		if err != nil {
+			return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+		}
+		if total, err = total.Add(refundable); err != nil {
+			return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+		}
+	}
+	short, err := total.LessThan(amount)
+	if err != nil {
+		return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+	}
+	if short {
+		return nil, fmt.Errorf("mercadopago: preference %s has %s to refund, %s requested", externalID, total, amount)
+	}
+
+	remaining := amount
+	var ids []string
+	for _, payment := range order.Payments {
+		if !remaining.IsPositive() {
+			break
+		}
+		refundable, err := payment.refundable()
+		if err != nil {
+			return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+		}
+		if !refundable.IsPositive() {
+			continue
+		}
+
+		var created refund
+		share, err := refundable.Min(remaining)
+		if err != nil {
+			return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+		}
+		body := map[string]float64{"amount": share.Major()}
+		key := fmt.Sprintf("refund-%d-%d-%d", payment.ID, payment.refunded().Cents, share.Cents)
+		if err := p.do(ctx, http.MethodPost, fmt.Sprintf("/v1/payments/%d/refunds", payment.ID), key, body, &created); err != nil {
+			return nil, err
+		}
+		ids = append(ids, strconv.FormatInt(created.ID, 10))
+		if remaining, err = remaining.Sub(common.MoneyFromMajor(created.Amount, amount.Currency)); err != nil {
+			return nil, fmt.Errorf("mercadopago: preference %s: %w", externalID, err)
+		}
+	}
+
+	return &models.ProviderRefund{ID: strings.Join(ids, ","), Amount: amount}, nil
+}
+
+// Notification types handled by WebhookCheckout
+const (
+	notificationPayment       = "payment"
+	notificationMerchantOrder = "merchant_order"
+	// notificationMerchantOrderWebhook is how webhooks (as opposed to IPN) name merchant orders
+	notificationMerchantOrderWebhook = "topic_merchant_order_wh"
+)
+
+// VerifyWebhook checks the x-signature header of a webhook: an HMAC-SHA256 with the webhook secret over
+// "id:<data.id>;request-id:<x-request-id>;ts:<ts>;", where ts must be within the tolerance of now
+func (p *Provider) VerifyWebhook(request models.WebhookRequest) (*models.WebhookNotification, error) {
+	if p.cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("%w: webhook secret is not configured", common.ErrUnauthorized)
+	}
+
+	var ts, signature string
+	for _, part := range strings.Split(request.Header.Get("x-signature"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "ts":
+			ts = value
+		case "v1":
+			signature = value
+		}
+	}
+	if ts == "" || signature == "" {
+		return nil, fmt.Errorf("%w: missing webhook signature", common.ErrUnauthorized)
+	}
+
+	dataID := request.Query.Get("data.id")
+	requestID := request.Header.Get("x-request-id")
+
+	var manifest strings.Builder
+	if dataID != "" {
+		manifest.WriteString("id:" + strings.ToLower(dataID) + ";")
+	}
+	if requestID != "" {
+		manifest.WriteString("request-id:" + requestID + ";")
+	}
+	manifest.WriteString("ts:" + ts + ";")
+
+	mac := hmac.New(sha256.New, []byte(p.cfg.WebhookSecret))
+	mac.Write([]byte(manifest.String()))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return nil, fmt.Errorf("%w: invalid webhook signature", common.ErrUnauthorized)
+	}
+
+	signedAt, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid webhook timestamp", common.ErrUnauthorized)
+	}
+	// Timestamps are in seconds, but accept milliseconds too
+	at := time.Unix(signedAt, 0)
+	if signedAt > 1e12 {
+		at = time.UnixMilli(signedAt)
+	}
+	if skew := p.cfg.Now().Sub(at); skew > p.cfg.WebhookTolerance || skew < -p.cfg.WebhookTolerance {
+		return nil, fmt.Errorf("%w: webhook signature expired", common.ErrUnauthorized)
+	}
+
+	var body notification
+	if err := json.Unmarshal(request.Body, &body); err != nil {
+		return nil, fmt.Errorf("%w: invalid webhook body: %v", common.ErrInvalidInput, err)
+	}
+	// Only the query's data.id is signed, so the body must agree with it
+	if dataID == "" || (body.Data.ID != "" && !strings.EqualFold(string(body.Data.ID), dataID)) {
+		return nil, fmt.Errorf("%w: webhook data.id is missing or does not match the signed one", common.ErrInvalidInput)
+	}
+
+	eventID := string(body.ID)
+	if eventID == "" {
+		eventID = requestID
+	}
+	if eventID == "" {
+		return nil, fmt.Errorf("%w: webhook has no id", common.ErrInvalidInput)
+	}
+
+	notificationType := request.Query.Get("type")
+	if notificationType == "" {
+		notificationType = body.Type
+	}
+
+	return &models.WebhookNotification{
+		EventID:    eventID,
+		Type:       notificationType,
+		ResourceID: dataID,
+	}, nil
+}
+
+// WebhookCheckout finds the preference a payment or merchant order notification is about
+func (p *Provider) WebhookCheckout(ctx context.Context, notification models.WebhookNotification) (string, error) {
+	orderID := ""
+	switch notification.Type {
+	case notificationPayment:
+		var found payment
+		if err := p.do(ctx, http.MethodGet, "/v1/payments/"+url.PathEscape(notification.ResourceID), "", nil, &found); err != nil {
+			return "", err
+		}
+		orderID = string(found.Order.ID)
+	case notificationMerchantOrder, notificationMerchantOrderWebhook:
+		orderID = notification.ResourceID
+	}
+
+	// Payments made outside a checkout have no merchant order
+	if orderID == "" {
+		return "", nil
+	}
+
+	var order merchantOrder
+	if err := p.do(ctx, http.MethodGet, "/merchant_orders/"+url.PathEscape(orderID), "", nil, &order); err != nil {
+		return "", err
+	}
+	return order.PreferenceID, nil
+}
+
+// findOrder returns the merchant order of a preference, or nil if nobody tried to pay yet
+func (p *Provider) findOrder(ctx context.Context, preferenceID string) (*merchantOrder, error) {
+	var search merchantOrderSearch
+	path := "/merchant_orders/search?preference_id=" + url.QueryEscape(preferenceID)
+	if err := p.do(ctx, http.MethodGet, path, "", nil, &search); err != nil {
+		return nil, err
+	}
+
+	if len(search.Elements) == 0 {
+		return nil, nil
+	}
+	return &search.Elements[0], nil
+}
+
+// do calls the API and decodes a JSON response into out.
+// idempotencyKey lets Mercado Pago drop retries of the same POST.
+func (p *Provider) do(ctx context.Context, method, path, idempotencyKey string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.cfg.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.AccessToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("X-Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mercadopago: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var failure apiError
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+		if failure.Message == "" {
+			failure.Message = failure.Error
+		}
+		return fmt.Errorf("mercadopago: %s %s: status %d: %s", method, path, resp.StatusCode, failure.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("mercadopago: %s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
@@ -2,11 +2,22 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
 	"gorm.io/gorm"
 )
 
+// ledgerMu serializes the reads of a reservation's payments that writes depend on, such as
+// checking the outstanding balance before recording a cash payment, with the writes that change
+// what has been paid, across repository instances.
+// Being in memory, it only covers a single API process: running several against one database
+// needs those reads done in transactions that lock the payments (e.g. SELECT ... FOR UPDATE) instead.
+var ledgerMu sync.Mutex
+
 // PaymentRepository implements the payment repository interface
 type PaymentRepository struct {
 	db *gorm.DB
@@ -32,6 +43,21 @@ func (r *PaymentRepository) FindAll(ctx context.Context) ([]models.Payment, erro
 func (r *PaymentRepository) FindByID(ctx context.Context, id uint) (*models.Payment, error) {
 	var payment models.Payment
 	if err := r.db.WithContext(ctx).First(&payment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// FindByExternalID retrieves a payment by the provider's ID for its checkout
+func (r *PaymentRepository) FindByExternalID(ctx context.Context, provider models.ProviderName, externalID string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.WithContext(ctx).Where("provider = ? AND external_id = ?", provider, externalID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
 		return nil, err
 	}
 	return &payment, nil
@@ -40,7 +66,20 @@ func (r *PaymentRepository) FindByID(ctx context.Context, id uint) (*models.Paym
 // FindByReservationID retrieves payments for a reservation
 func (r *PaymentRepository) FindByReservationID(ctx context.Context, reservationID uint) ([]models.Payment, error) {
 	var payments []models.Payment
-	if err := r.db.WithContext(ctx).Where("reservation_id = ?", reservationID).Find(&payments).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("reservation_id = ?", reservationID).Order("id ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// FindByUserID retrieves the payments of a user's reservations, newest first
+func (r *PaymentRepository) FindByUserID(ctx context.Context, userID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN reservations ON reservations.id = payments.reservation_id").
+		Where("reservations.user_id = ?", userID).
+		Order("payments.id DESC").
+		Find(&payments).Error; err != nil {
 		return nil, err
 	}
 	return payments, nil
@@ -56,3 +95,50 @@ func (r *PaymentRepository) Update(ctx context.Context, payment *models.Payment)
 	return r.db.WithContext(ctx).Save(payment).Error
 }
 
+// WithPayments calls fn with the payments of a reservation. What has been paid on the reservation
+// does not change until fn returns, so fn may act on its balance; fn must not call WithPayments,
+// UpdateState or complete a refund itself.
+func (r *PaymentRepository) WithPayments(ctx context.Context, reservationID uint, fn func(payments []models.Payment) error) error {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+
+	payments, err := r.FindByReservationID(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	return fn(payments)
+}
+
+// UpdateState sets the status and refunded amount of payment, only if the stored ones are still those
+// loaded into payment, so a refund recorded meanwhile is not overwritten
+func (r *PaymentRepository) UpdateState(ctx context.Context, payment *models.Payment, status models.PaymentStatus, refunded common.Money) error {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+
+	if err := updateState(r.db.WithContext(ctx), payment, status, refunded); err != nil {
+		return err
+	}
+
+	payment.Status = status
+	payment.RefundedAmount = refunded
+	return nil
+}
+
+// updateState applies UpdateState within tx, without setting the fields of payment
+func updateState(tx *gorm.DB, payment *models.Payment, status models.PaymentStatus, refunded common.Money) error {
+	result := tx.Model(&models.Payment{}).
+		Where("id = ? AND status = ? AND refunded_cents = ?", payment.ID, payment.Status, payment.RefundedAmount.Cents).
+		Updates(map[string]interface{}{
+			"status":            status,
+			"refunded_cents":    refunded.Cents,
+			"refunded_currency": refunded.Currency,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: payment %d changed since it was loaded", common.ErrConflict, payment.ID)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	"gorm.io/gorm"
+)
+
+// RefundRepository implements the refund repository interface
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository creates a new refund repository
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{
+		db: db,
+	}
+}
+
+// FindByPaymentID retrieves the refunds of a payment, oldest first
+func (r *RefundRepository) FindByPaymentID(ctx context.Context, paymentID uint) ([]models.Refund, error) {
+	var refunds []models.Refund
+	if err := r.db.WithContext(ctx).Where("payment_id = ?", paymentID).Order("id ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// FindByRequestedBy retrieves the refunds asked for by requestedBy, oldest first
+func (r *RefundRepository) FindByRequestedBy(ctx context.Context, requestedBy string) ([]models.Refund, error) {
+	var refunds []models.Refund
+	if err := r.db.WithContext(ctx).Where("requested_by = ?", requestedBy).Order("id ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// Create creates a new refund
+func (r *RefundRepository) Create(ctx context.Context, refund *models.Refund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+// Update updates an existing refund
+func (r *RefundRepository) Update(ctx context.Context, refund *models.Refund) error {
+	return r.db.WithContext(ctx).Save(refund).Error
+}
+
+// Complete saves a processed refund and moves the payment it refunds to status and refunded in a
+// single transaction. Like UpdateState, the payment is only updated if it has not changed since it was loaded.
+func (r *RefundRepository) Complete(ctx context.Context, refund *models.Refund, payment *models.Payment, status models.PaymentStatus, refunded common.Money) error {
+	ledgerMu.Lock()
+	defer ledgerMu.Unlock()
+
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateState(tx, payment, status, refunded); err != nil {
+			return err
+		}
+		return tx.Save(refund).Error
+	}); err != nil {
+		return err
+	}
+
+	payment.Status = status
+	payment.RefundedAmount = refunded
+	return nil
+}
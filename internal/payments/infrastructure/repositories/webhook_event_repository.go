@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	"gorm.io/gorm"
+)
+
+// WebhookEventRepository implements the webhook event repository interface
+type WebhookEventRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookEventRepository creates a new webhook event repository
+func NewWebhookEventRepository(db *gorm.DB) *WebhookEventRepository {
+	return &WebhookEventRepository{
+		db: db,
+	}
+}
+
+// Create records a webhook event, returning common.ErrConflict if the provider's event ID is already recorded
+func (r *WebhookEventRepository) Create(ctx context.Context, event *models.WebhookEvent) error {
+	err := r.db.WithContext(ctx).Create(event).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return common.ErrConflict
+	}
+	return err
+}
+
+// Update updates a recorded webhook event
+func (r *WebhookEventRepository) Update(ctx context.Context, event *models.WebhookEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
+
+// Delete forgets a webhook event so the provider's retry is processed
+func (r *WebhookEventRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.WebhookEvent{}, id).Error
+}
@@ -1,19 +1,55 @@
 package http
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	authModels "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
 )
 
 // ReservationHandler handles HTTP requests for reservations
 type ReservationHandler struct {
-	// TODO: Add use cases dependency injection
+	useCase *usecases.ReservationUseCase
 }
 
 // NewReservationHandler creates a new reservation handler
-func NewReservationHandler() *ReservationHandler {
-	return &ReservationHandler{}
+func NewReservationHandler(useCase *usecases.ReservationUseCase) *ReservationHandler {
+	return &ReservationHandler{
+		useCase: useCase,
+	}
+}
+
+// createReservationRequest is the request body for POST /reservations
+type createReservationRequest struct {
+	UserID    uint      `json:"user_id" binding:"required"`
+	SlotID    uint      `json:"slot_id" binding:"required"`
+	AddressID uint      `json:"address_id" binding:"required"`
+	ServiceID uint      `json:"service_id"`
+	VehicleID uint      `json:"vehicle_id"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+	Notes     string    `json:"notes"`
+}
+
+// toInput converts the request body into use case input
+func (r createReservationRequest) toInput() usecases.CreateReservationInput {
+	return usecases.CreateReservationInput{
+		UserID:    r.UserID,
+		SlotID:    r.SlotID,
+		AddressID: r.AddressID,
+		ServiceID: r.ServiceID,
+		VehicleID: r.VehicleID,
+		StartTime: r.StartTime,
+		Notes:     r.Notes,
+	}
 }
 
 // RegisterRoutes registers all reservation routes
@@ -23,40 +59,156 @@ func (h *ReservationHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		reservations.GET("", h.List)
 		reservations.GET("/:id", h.GetByID)
 		reservations.POST("", h.Create)
+		reservations.POST("/holds", h.Hold)
+		reservations.POST("/series", h.CreateSeries)
+		reservations.GET("/series/:id", h.GetSeries)
+		reservations.POST("/series/:id/cancel", h.CancelSeries)
 		reservations.PUT("/:id", h.Update)
 		reservations.DELETE("/:id", h.Delete)
+		reservations.POST("/:id/confirm", h.Confirm)
+		reservations.POST("/:id/cancel", h.Cancel)
+		reservations.POST("/:id/complete", h.Complete)
+		reservations.GET("/:id/history", h.History)
+		reservations.GET("/:id/balance", h.Balance)
+		reservations.PUT("/:id/reschedule", h.Reschedule)
+		reservations.GET("/:id/reschedules", h.Reschedules)
 	}
 }
 
-// List returns all reservations
+// List returns the caller's reservations, or for staff a client's reservations or the day's schedule
+// @Summary List reservations
+// @Description Customers get their own reservations. Staff get the reservations of user_id if given,
+// @Description otherwise every reservation starting on date, ordered by start time and slot.
+// @Tags reservations
+// @Produce json
+// @Param user_id query int false "Client (staff only; customers may only pass their own)"
+// @Param date query string false "Business date of the schedule (YYYY-MM-DD), defaults to today"
+// @Success 200 {array} models.Reservation
+// @Failure 403 {object} common.APIError "Another client's reservations"
+// @Router /api/v1/reservations [get]
 func (h *ReservationHandler) List(c *gin.Context) {
-	// TODO: Implement list reservations
+	requested, err := common.ParseOptionalIDQuery(c, "user_id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	_, userID, err := policies.ScopeClient(c.Request.Context(), requested)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var reservations []models.Reservation
+	if userID != 0 {
+		reservations, err = h.useCase.ListUserReservations(c.Request.Context(), userID)
+	} else {
+		reservations, err = h.useCase.ListSchedule(c.Request.Context(), c.Query("date"))
+	}
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    []interface{}{},
-		"message": "list reservations - not implemented",
+		"data": reservations,
 	})
 }
 
 // GetByID returns a reservation by ID
 func (h *ReservationHandler) GetByID(c *gin.Context) {
-	// TODO: Implement get reservation by ID
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	reservation, _, err := h.authorizeReservation(c, id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    nil,
-		"message": "get reservation - not implemented",
+		"data": reservation,
 	})
 }
 
 // Create creates a new reservation
+// @Summary Create reservation
+// @Description Books a slot at a start time on the 30-minute grid; the service duration must fit before closing.
+// @Description The price is the service base price plus the surcharge for the size of vehicle_id, if given.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Reservation
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 409 {object} common.APIError "Slot not available"
+// @Router /api/v1/reservations [post]
 func (h *ReservationHandler) Create(c *gin.Context) {
-	// TODO: Implement create reservation
+	var req createReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), req.UserID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	reservation, err := h.useCase.CreateReservation(c.Request.Context(), req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"data":    nil,
-		"message": "create reservation - not implemented",
+		"data": reservation,
+	})
+}
+
+// Hold temporarily books a slot while the customer pays
+// @Summary Hold reservation
+// @Description Books a slot like POST /reservations, as a pending reservation with an expires_at.
+// @Description Confirming it before expires_at keeps the booking; otherwise it is released automatically.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Reservation
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 409 {object} common.APIError "Slot not available"
+// @Router /api/v1/reservations/holds [post]
+func (h *ReservationHandler) Hold(c *gin.Context) {
+	var req createReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), req.UserID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	reservation, err := h.useCase.HoldReservation(c.Request.Context(), req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": reservation,
 	})
 }
 
 // Update updates an existing reservation
 func (h *ReservationHandler) Update(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleStaff); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	// TODO: Implement update reservation
 	c.JSON(http.StatusOK, gin.H{
 		"data":    nil,
@@ -66,7 +218,279 @@ func (h *ReservationHandler) Update(c *gin.Context) {
 
 // Delete deletes a reservation
 func (h *ReservationHandler) Delete(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	// TODO: Implement delete reservation
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// rescheduleReservationRequest is the request body for PUT /reservations/:id/reschedule
+type rescheduleReservationRequest struct {
+	SlotID    uint      `json:"slot_id" binding:"required"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+	Reason    string    `json:"reason"`
+}
+
+// statusChangeRequest is the optional request body for status transition endpoints.
+// The change is recorded as made by the authenticated caller.
+type statusChangeRequest struct {
+	Reason string `json:"reason"`
+	// OverrideBalance completes a reservation that is not fully paid; only used by complete
+	OverrideBalance bool `json:"override_balance"`
+}
+
+// statusTransition is a use case method that moves a reservation to a new status
+type statusTransition func(ctx context.Context, id uint, input usecases.StatusChangeInput) (*models.Reservation, error)
+
+// Confirm moves a pending reservation to confirmed
+// @Summary Confirm reservation
+// @Description Staff only
+// @Tags reservations
+// @Produce json
+// @Success 200 {object} models.Reservation
+// @Failure 404 {object} common.APIError "Reservation not found"
+// @Failure 409 {object} common.APIError "Illegal status transition"
+// @Router /api/v1/reservations/{id}/confirm [post]
+func (h *ReservationHandler) Confirm(c *gin.Context) {
+	h.transition(c, h.useCase.ConfirmReservation)
+}
+
+// Cancel cancels a pending or confirmed reservation
+// @Summary Cancel reservation
+// @Tags reservations
+// @Produce json
+// @Description Applies the cancellation policy and refunds the reservation's payments. A refund the provider fails is reported as pending and retried.
+// @Success 200 {object} models.CancellationResult
+// @Failure 404 {object} common.APIError "Reservation not found or another client's"
+// @Failure 409 {object} common.APIError "Illegal status transition"
+// @Router /api/v1/reservations/{id}/cancel [post]
+func (h *ReservationHandler) Cancel(c *gin.Context) {
+	id, input, err := parseStatusChange(c)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	_, principal, err := h.authorizeReservation(c, id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	input.ChangedBy = principal.String()
+
+	result, err := h.useCase.CancelReservation(c.Request.Context(), id, input)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// Complete marks a confirmed reservation as completed
+// @Summary Complete reservation
+// @Description Staff only. A reservation with an outstanding balance is only completed with
+// @Description override_balance and a reason.
+// @Tags reservations
+// @Produce json
+// @Success 200 {object} models.Reservation
+// @Failure 400 {object} common.APIError "Balance overridden without a reason"
+// @Failure 404 {object} common.APIError "Reservation not found"
+// @Failure 409 {object} common.APIError "Illegal status transition or outstanding balance"
+// @Router /api/v1/reservations/{id}/complete [post]
+func (h *ReservationHandler) Complete(c *gin.Context) {
+	h.transition(c, h.useCase.CompleteReservation)
+}
+
+// Balance returns what has been paid on a reservation and what is left to pay
+// @Summary Reservation balance
+// @Description The reservation's price, what has been paid by checkout or in cash less refunds,
+// @Description and what is outstanding
+// @Tags reservations
+// @Produce json
+// @Param id path int true "Reservation"
+// @Success 200 {object} models.Balance
+// @Failure 404 {object} common.APIError "Reservation not found or another client's"
+// @Router /api/v1/reservations/{id}/balance [get]
+func (h *ReservationHandler) Balance(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, _, err := h.authorizeReservation(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	balance, err := h.useCase.GetBalance(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": balance,
+	})
+}
+
+// History returns the status changes of a reservation
+func (h *ReservationHandler) History(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, _, err := h.authorizeReservation(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	changes, err := h.useCase.GetStatusHistory(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": changes,
+	})
+}
+
+// Reschedule moves a reservation to a new slot and start time
+// @Summary Reschedule reservation
+// @Description Atomically moves an active reservation to a free slot/start time, keeping its payments
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Reservation
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 404 {object} common.APIError "Reservation not found"
+// @Failure 409 {object} common.APIError "Slot not available or reservation not active"
+// @Router /api/v1/reservations/{id}/reschedule [put]
+func (h *ReservationHandler) Reschedule(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req rescheduleReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	_, principal, err := h.authorizeReservation(c, id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	reservation, err := h.useCase.RescheduleReservation(c.Request.Context(), id, usecases.RescheduleReservationInput{
+		SlotID:    req.SlotID,
+		StartTime: req.StartTime,
+		ChangedBy: principal.String(),
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reservation,
+	})
+}
+
+// Reschedules returns the previous slots and times of a reservation
+func (h *ReservationHandler) Reschedules(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, _, err := h.authorizeReservation(c, id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	reschedules, err := h.useCase.GetRescheduleHistory(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reschedules,
+	})
+}
+
+// transition runs a staff-only status transition for the reservation in the :id path parameter
+func (h *ReservationHandler) transition(c *gin.Context, apply statusTransition) {
+	id, input, err := parseStatusChange(c)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	principal, err := policies.RequireRole(c.Request.Context(), clientModels.RoleStaff)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	input.ChangedBy = principal.String()
+
+	reservation, err := apply(c.Request.Context(), id, input)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reservation,
+	})
+}
+
+// authorizeReservation loads a reservation the caller owns, or any reservation for staff.
+// Other clients' reservations are reported as not found.
+func (h *ReservationHandler) authorizeReservation(c *gin.Context, id uint) (*models.Reservation, authModels.Principal, error) {
+	reservation, err := h.useCase.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		return nil, authModels.Principal{}, err
+	}
+
+	principal, err := policies.RequireOwner(c.Request.Context(), reservation.UserID, common.ErrNotFound)
+	if err != nil {
+		return nil, authModels.Principal{}, err
+	}
+	return reservation, principal, nil
+}
+
+// parseStatusChange parses the :id path parameter and the optional status change body
+func parseStatusChange(c *gin.Context) (uint, usecases.StatusChangeInput, error) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		return 0, usecases.StatusChangeInput{}, err
+	}
+
+	var req statusChangeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return 0, usecases.StatusChangeInput{}, fmt.Errorf("%w: %v", common.ErrInvalidInput, err)
+		}
+	}
+
+	return id, usecases.StatusChangeInput{
+		Reason:          req.Reason,
+		OverrideBalance: req.OverrideBalance,
+	}, nil
+}
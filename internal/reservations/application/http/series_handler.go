@@ -0,0 +1,146 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// createSeriesRequest is the request body for POST /reservations/series
+type createSeriesRequest struct {
+	UserID    uint                       `json:"user_id" binding:"required"`
+	SlotID    uint                       `json:"slot_id" binding:"required"`
+	AddressID uint                       `json:"address_id" binding:"required"`
+	ServiceID uint                       `json:"service_id"`
+	VehicleID uint                       `json:"vehicle_id"`
+	StartTime time.Time                  `json:"start_time" binding:"required"`
+	Frequency models.RecurrenceFrequency `json:"frequency" binding:"required"`
+	Until     *time.Time                 `json:"until"`
+	Count     int                        `json:"count"`
+	Notes     string                     `json:"notes"`
+}
+
+// CreateSeries books a recurring series of reservations
+// @Summary Create reservation series
+// @Description Books the same slot weekly, biweekly or monthly from start_time, either until a date
+// @Description or for count occurrences (at most 52). Each occurrence is validated like a single
+// @Description reservation; the ones that cannot be booked are listed in conflicts.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.SeriesResult
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 409 {object} common.APIError "No occurrence available"
+// @Router /api/v1/reservations/series [post]
+func (h *ReservationHandler) CreateSeries(c *gin.Context) {
+	var req createSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), req.UserID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	result, err := h.useCase.CreateSeries(c.Request.Context(), usecases.CreateSeriesInput{
+		UserID:    req.UserID,
+		SlotID:    req.SlotID,
+		AddressID: req.AddressID,
+		ServiceID: req.ServiceID,
+		VehicleID: req.VehicleID,
+		StartTime: req.StartTime,
+		Frequency: req.Frequency,
+		Until:     req.Until,
+		Count:     req.Count,
+		Notes:     req.Notes,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": result,
+	})
+}
+
+// GetSeries returns a series with its occurrences
+// @Summary Get reservation series
+// @Tags reservations
+// @Produce json
+// @Success 200 {object} models.SeriesResult
+// @Failure 404 {object} common.APIError "Series not found"
+// @Router /api/v1/reservations/series/{id} [get]
+func (h *ReservationHandler) GetSeries(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	result, err := h.useCase.GetSeries(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := policies.RequireOwner(c.Request.Context(), result.Series.UserID, common.ErrNotFound); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// CancelSeries cancels every upcoming occurrence of a series
+// @Summary Cancel reservation series
+// @Description Cancels the upcoming active occurrences, applying the cancellation policy to each.
+// @Description Use POST /reservations/{id}/cancel to cancel a single occurrence.
+// @Description Occurrences that could not be cancelled are listed in failed and the series stays active; cancel it again to retry them.
+// @Tags reservations
+// @Produce json
+// @Success 200 {object} models.SeriesResult
+// @Failure 404 {object} common.APIError "Series not found"
+// @Failure 409 {object} common.APIError "Series already cancelled"
+// @Router /api/v1/reservations/series/{id}/cancel [post]
+func (h *ReservationHandler) CancelSeries(c *gin.Context) {
+	id, input, err := parseStatusChange(c)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	series, err := h.useCase.GetSeries(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	principal, err := policies.RequireOwner(c.Request.Context(), series.Series.UserID, common.ErrNotFound)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	input.ChangedBy = principal.String()
+
+	result, err := h.useCase.CancelSeries(c.Request.Context(), id, input)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
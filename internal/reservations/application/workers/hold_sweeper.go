@@ -0,0 +1,59 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// HoldSweeper periodically releases reservation holds that expired before being confirmed
+type HoldSweeper struct {
+	useCase  *usecases.ReservationUseCase
+	interval time.Duration
+}
+
+// NewHoldSweeper creates a new hold sweeper
+func NewHoldSweeper(useCase *usecases.ReservationUseCase, interval time.Duration) *HoldSweeper {
+	return &HoldSweeper{
+		useCase:  useCase,
+		interval: interval,
+	}
+}
+
+// Run sweeps expired holds every interval until ctx is cancelled
+func (s *HoldSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	common.Logger.Info("Reservation hold sweeper started", zap.Duration("interval", s.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			common.Logger.Info("Reservation hold sweeper stopped")
+			return
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep releases the holds that are expired now
+func (s *HoldSweeper) Sweep(ctx context.Context) {
+	released, err := s.useCase.ReleaseExpiredHolds(ctx)
+	for _, hold := range released {
+		common.Logger.Info("Released expired reservation hold",
+			zap.Uint("reservation_id", hold.ID),
+			zap.Uint("slot_id", hold.SlotID),
+			zap.Time("start_time", hold.StartTime),
+			zap.Timep("expires_at", hold.ExpiresAt),
+		)
+	}
+	if err != nil {
+		common.Logger.Error("Failed to release expired reservation holds", zap.Error(err))
+	}
+}
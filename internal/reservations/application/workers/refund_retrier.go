@@ -0,0 +1,58 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// RefundRetrier periodically retries the refunds of cancelled reservations that failed to refund
+type RefundRetrier struct {
+	useCase  *usecases.ReservationUseCase
+	interval time.Duration
+}
+
+// NewRefundRetrier creates a new refund retrier
+func NewRefundRetrier(useCase *usecases.ReservationUseCase, interval time.Duration) *RefundRetrier {
+	return &RefundRetrier{
+		useCase:  useCase,
+		interval: interval,
+	}
+}
+
+// Run retries pending refunds every interval until ctx is cancelled
+func (r *RefundRetrier) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	common.Logger.Info("Cancellation refund retrier started", zap.Duration("interval", r.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			common.Logger.Info("Cancellation refund retrier stopped")
+			return
+		case <-ticker.C:
+			r.Retry(ctx)
+		}
+	}
+}
+
+// Retry retries the refunds that are pending now
+func (r *RefundRetrier) Retry(ctx context.Context) {
+	refunded, err := r.useCase.RetryPendingRefunds(ctx)
+	for _, cancellation := range refunded {
+		common.Logger.Info("Refunded cancelled reservation",
+			zap.Uint("reservation_id", cancellation.ReservationID),
+			zap.Stringer("refund_amount", cancellation.RefundAmount),
+			zap.Int("attempts", cancellation.RefundAttempts),
+		)
+	}
+	if err != nil {
+		common.Logger.Error("Failed to retry cancellation refunds", zap.Error(err))
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// Balance summarizes what has been paid on a reservation and what is left to pay
+type Balance struct {
+	ReservationID uint `json:"reservation_id"`
+	// Total is the reservation's price
+	Total common.Money `json:"total"`
+	// Paid is what has been captured and not refunded, by checkout or in person
+	Paid common.Money `json:"paid"`
+	// Outstanding is what is left to pay; never negative
+	Outstanding common.Money `json:"outstanding"`
+}
+
+// NewBalance returns the balance of a reservation given what has been paid on it.
+// Returns common.ErrConflict if paid is not in the reservation's currency.
+func NewBalance(reservation *Reservation, paid common.Money) (Balance, error) {
+	if paid.Currency == "" {
+		paid.Currency = reservation.Price.Currency
+	}
+
+	outstanding, err := reservation.Price.Sub(paid)
+	if err != nil {
+		return Balance{}, err
+	}
+	if !outstanding.IsPositive() {
+		outstanding = common.Money{Currency: reservation.Price.Currency}
+	}
+
+	return Balance{
+		ReservationID: reservation.ID,
+		Total:         reservation.Price,
+		Paid:          paid,
+		Outstanding:   outstanding,
+	}, nil
+}
+
+// Settled returns true if nothing is left to pay
+func (b Balance) Settled() bool {
+	return !b.Outstanding.IsPositive()
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// CancellationPolicy defines how much of a paid reservation is refunded on cancellation
+type CancellationPolicy struct {
+	// FreeCancellationWindow is how long before StartTime a cancellation is still fully refunded
+	FreeCancellationWindow time.Duration
+	// LateCancellationFeePercent is the percentage of the paid amount retained for
+	// cancellations inside the free window
+	LateCancellationFeePercent int
+}
+
+// RefundPercent returns the percentage of the paid amount to refund when a reservation
+// starting at startTime is cancelled at cancelledAt. No-shows (cancelled at or after
+// StartTime) are not refunded.
+func (p CancellationPolicy) RefundPercent(startTime, cancelledAt time.Time) int {
+	switch {
+	case !cancelledAt.Before(startTime):
+		return 0
+	case startTime.Sub(cancelledAt) >= p.FreeCancellationWindow:
+		return 100
+	default:
+		return 100 - p.LateCancellationFeePercent
+	}
+}
+
+// RefundStatus tracks the refund a cancellation owes
+type RefundStatus string
+
+const (
+	// RefundStatusNone is for cancellations that owe nothing
+	RefundStatusNone RefundStatus = "none"
+	// RefundStatusPending refunds have not reached the client yet and are retried until they do
+	RefundStatusPending  RefundStatus = "pending"
+	RefundStatusRefunded RefundStatus = "refunded"
+)
+
+// ReservationCancellation records what a cancelled reservation had paid and what it is owed back.
+// It is saved together with the cancellation, so the refund is not lost if the payment provider fails.
+type ReservationCancellation struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	ReservationID uint         `gorm:"uniqueIndex;not null" json:"reservation_id"`
+	RefundPercent int          `gorm:"not null" json:"refund_percent"`
+	PaidAmount    common.Money `gorm:"embedded;embeddedPrefix:paid_" json:"paid_amount"`
+	RefundAmount  common.Money `gorm:"embedded;embeddedPrefix:refund_" json:"refund_amount"`
+	RefundStatus  RefundStatus `gorm:"type:varchar(20);index;not null" json:"refund_status"`
+	// RefundAttempts counts the calls made to refund the payments
+	RefundAttempts int `gorm:"not null;default:0" json:"refund_attempts"`
+	// RefundError is why the last attempt failed, while the refund is pending
+	RefundError string     `gorm:"type:text" json:"refund_error,omitempty"`
+	RefundedAt  *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for ReservationCancellation
+func (ReservationCancellation) TableName() string {
+	return "reservation_cancellations"
+}
+
+// CancellationResult describes the outcome of cancelling a reservation
+type CancellationResult struct {
+	Reservation   *Reservation `json:"reservation"`
+	RefundPercent int          `json:"refund_percent"`
+	PaidAmount    common.Money `json:"paid_amount"`
+	RefundAmount  common.Money `json:"refund_amount"`
+	FeeAmount     common.Money `json:"fee_amount"`
+	// RefundStatus is pending if the refund failed; it is retried in the background
+	RefundStatus RefundStatus `json:"refund_status"`
+}
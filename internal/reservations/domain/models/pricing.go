@@ -0,0 +1,19 @@
+package models
+
+import (
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// PricingPolicy defines how the price of a reservation depends on the vehicle washed
+type PricingPolicy struct {
+	// SizeSurchargePercent is added to the service base price for each vehicle size.
+	// Sizes without an entry, and reservations without a vehicle, pay the base price.
+	SizeSurchargePercent map[clientModels.VehicleSize]int
+}
+
+// Price returns the price of a service with basePrice for a vehicle of the given size,
+// rounded to cents
+func (p PricingPolicy) Price(basePrice common.Money, size clientModels.VehicleSize) common.Money {
+	return basePrice.Percent(100 + p.SizeSurchargePercent[size])
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 )
 
 // ReservationStatus represents the status of a reservation
@@ -16,18 +19,52 @@ const (
 	ReservationStatusCompleted ReservationStatus = "completed"
 )
 
+// DefaultReservationDuration is how long a reservation without a service occupies its slot
+const DefaultReservationDuration = 30 * time.Minute
+
+// reservationTransitions lists the statuses each status may move to.
+// Cancelled and completed are terminal: a cancelled reservation is never resurrected.
+var reservationTransitions = map[ReservationStatus][]ReservationStatus{
+	ReservationStatusPending:   {ReservationStatusConfirmed, ReservationStatusCancelled},
+	ReservationStatusConfirmed: {ReservationStatusCompleted, ReservationStatusCancelled},
+}
+
+// CanTransitionTo returns true if a reservation in status s may move to next
+func (s ReservationStatus) CanTransitionTo(next ReservationStatus) bool {
+	for _, allowed := range reservationTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // Reservation represents a car wash reservation
 type Reservation struct {
-	ID        uint              `gorm:"primaryKey" json:"id"`
-	UserID    uint              `gorm:"index;not null" json:"user_id"`
-	SlotID    uint              `gorm:"index;not null" json:"slot_id"`
-	AddressID uint              `gorm:"index;not null" json:"address_id"`
+	ID        uint `gorm:"primaryKey" json:"id"`
+	UserID    uint `gorm:"index;not null" json:"user_id"`
+	SlotID    uint `gorm:"index;not null" json:"slot_id"`
+	AddressID uint `gorm:"index;not null" json:"address_id"`
+	ServiceID uint `gorm:"index" json:"service_id,omitempty"`
+	// SeriesID links an occurrence to its recurring ReservationSeries
+	SeriesID *uint `gorm:"index" json:"series_id,omitempty"`
+	// VehicleID is the client's vehicle to wash; its size sets Price
+	VehicleID *uint `gorm:"index" json:"vehicle_id,omitempty"`
+	// Price is quoted from the service and vehicle when booking
+	Price     common.Money      `gorm:"embedded;embeddedPrefix:price_" json:"price"`
 	StartTime time.Time         `gorm:"not null;index" json:"start_time"`
+	EndTime   time.Time         `gorm:"index" json:"end_time"`
 	Status    ReservationStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
 	Notes     string            `gorm:"type:text" json:"notes,omitempty"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
-	DeletedAt gorm.DeletedAt    `gorm:"index" json:"-"`
+	// ExpiresAt is set on holds: pending reservations released automatically unless confirmed in time
+	ExpiresAt *time.Time     `gorm:"index" json:"expires_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	// Client declares the foreign key from UserID; it is never loaded
+	Client *clientModels.Client `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"-"`
+	// Vehicle is loaded when reading a single reservation so staff see what they are washing
+	Vehicle *clientModels.Vehicle `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"vehicle,omitempty"`
 }
 
 // TableName specifies the table name for Reservation
@@ -35,7 +72,91 @@ func (Reservation) TableName() string {
 	return "reservations"
 }
 
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (r *Reservation) BeforeSave(tx *gorm.DB) error {
+	r.StartTime = r.StartTime.UTC()
+	r.EndTime = r.EndTime.UTC()
+	if r.ExpiresAt != nil {
+		expiresAt := r.ExpiresAt.UTC()
+		r.ExpiresAt = &expiresAt
+	}
+	return nil
+}
+
 // IsActive returns true if the reservation is not cancelled or completed
 func (r *Reservation) IsActive() bool {
 	return r.Status == ReservationStatusPending || r.Status == ReservationStatusConfirmed
 }
+
+// IsHold returns true if the reservation is a pending hold with an expiry
+func (r *Reservation) IsHold() bool {
+	return r.Status == ReservationStatusPending && r.ExpiresAt != nil
+}
+
+// HoldExpired returns true if the reservation is a hold whose expiry has passed at now
+func (r *Reservation) HoldExpired(now time.Time) bool {
+	return r.IsHold() && !r.ExpiresAt.After(now)
+}
+
+// Occupies returns true if the reservation blocks its slot at now:
+// it is active and, if it is a hold, has not expired yet
+func (r *Reservation) Occupies(now time.Time) bool {
+	return r.IsActive() && !r.HoldExpired(now)
+}
+
+// EffectiveEndTime returns when the reservation frees its slot.
+// Reservations created before services existed have no EndTime and last DefaultReservationDuration.
+func (r *Reservation) EffectiveEndTime() time.Time {
+	if r.EndTime.After(r.StartTime) {
+		return r.EndTime
+	}
+	return r.StartTime.Add(DefaultReservationDuration)
+}
+
+// Overlaps returns true if the reservation occupies any part of [start, end)
+func (r *Reservation) Overlaps(start, end time.Time) bool {
+	return r.StartTime.Before(end) && r.EffectiveEndTime().After(start)
+}
+
+// ReservationStatusChange records a single status transition of a reservation
+type ReservationStatusChange struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	ReservationID uint              `gorm:"index;not null" json:"reservation_id"`
+	FromStatus    ReservationStatus `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus      ReservationStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	ChangedBy     string            `gorm:"type:varchar(100)" json:"changed_by"`
+	Reason        string            `gorm:"type:text" json:"reason,omitempty"`
+	ChangedAt     time.Time         `gorm:"not null" json:"changed_at"`
+}
+
+// TableName specifies the table name for ReservationStatusChange
+func (ReservationStatusChange) TableName() string {
+	return "reservation_status_changes"
+}
+
+// ReservationReschedule records a previous slot and start time of a rescheduled reservation
+type ReservationReschedule struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ReservationID uint      `gorm:"index;not null" json:"reservation_id"`
+	FromSlotID    uint      `gorm:"not null" json:"from_slot_id"`
+	FromStartTime time.Time `gorm:"not null" json:"from_start_time"`
+	ToSlotID      uint      `gorm:"not null" json:"to_slot_id"`
+	ToStartTime   time.Time `gorm:"not null" json:"to_start_time"`
+	ToEndTime     time.Time `gorm:"not null" json:"to_end_time"`
+	ChangedBy     string    `gorm:"type:varchar(100)" json:"changed_by"`
+	Reason        string    `gorm:"type:text" json:"reason,omitempty"`
+	RescheduledAt time.Time `gorm:"not null" json:"rescheduled_at"`
+}
+
+// TableName specifies the table name for ReservationReschedule
+func (ReservationReschedule) TableName() string {
+	return "reservation_reschedules"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (r *ReservationReschedule) BeforeSave(tx *gorm.DB) error {
+	r.FromStartTime = r.FromStartTime.UTC()
+	r.ToStartTime = r.ToStartTime.UTC()
+	r.ToEndTime = r.ToEndTime.UTC()
+	return nil
+}
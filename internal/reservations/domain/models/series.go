@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecurrenceFrequency is how often a reservation series repeats
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly   RecurrenceFrequency = "weekly"
+	RecurrenceBiweekly RecurrenceFrequency = "biweekly"
+	RecurrenceMonthly  RecurrenceFrequency = "monthly"
+)
+
+// IsValid returns true for a known frequency
+func (f RecurrenceFrequency) IsValid() bool {
+	switch f {
+	case RecurrenceWeekly, RecurrenceBiweekly, RecurrenceMonthly:
+		return true
+	}
+	return false
+}
+
+// Occurrence returns the start of the n-th occurrence (0 is the first) of a series starting at start.
+// Occurrences keep the wall clock time of start in its location; monthly series use the same day
+// of the month, or the last day of months too short for it (a series on the 31st falls on Feb 28).
+func (f RecurrenceFrequency) Occurrence(start time.Time, n int) time.Time {
+	switch f {
+	case RecurrenceBiweekly:
+		return start.AddDate(0, 0, 14*n)
+	case RecurrenceMonthly:
+		return addMonthsClamped(start, n)
+	default:
+		return start.AddDate(0, 0, 7*n)
+	}
+}
+
+// addMonthsClamped adds n months to t, moving to the last day of the target month
+// instead of overflowing into the next one like time.AddDate does
+func addMonthsClamped(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	// Day 0 of the month after the target is the target's last day
+	lastDay := time.Date(year, month+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+time.Month(n), day, hour, min, sec, t.Nanosecond(), t.Location())
+}
+
+// SeriesStatus represents the status of a reservation series
+type SeriesStatus string
+
+const (
+	SeriesStatusActive    SeriesStatus = "active"
+	SeriesStatusCancelled SeriesStatus = "cancelled"
+)
+
+// ReservationSeries is a recurring booking (e.g., the same bay every Monday at 9:00)
+// materialized as individual Reservation rows that reference it.
+// It ends on Until or after Count occurrences, whichever is set.
+type ReservationSeries struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	UserID         uint                `gorm:"index;not null" json:"user_id"`
+	SlotID         uint                `gorm:"not null" json:"slot_id"`
+	AddressID      uint                `gorm:"not null" json:"address_id"`
+	ServiceID      uint                `json:"service_id,omitempty"`
+	VehicleID      *uint               `json:"vehicle_id,omitempty"`
+	FirstStartTime time.Time           `gorm:"not null" json:"first_start_time"`
+	Frequency      RecurrenceFrequency `gorm:"type:varchar(20);not null" json:"frequency"`
+	Until          *time.Time          `json:"until,omitempty"`
+	Count          int                 `json:"count,omitempty"`
+	Status         SeriesStatus        `gorm:"type:varchar(20);default:'active'" json:"status"`
+	Notes          string              `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt      `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for ReservationSeries
+func (ReservationSeries) TableName() string {
+	return "reservation_series"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (s *ReservationSeries) BeforeSave(tx *gorm.DB) error {
+	s.FirstStartTime = s.FirstStartTime.UTC()
+	if s.Until != nil {
+		until := s.Until.UTC()
+		s.Until = &until
+	}
+	return nil
+}
+
+// OccurrenceConflict reports an occurrence of a series that could not be booked or cancelled
+type OccurrenceConflict struct {
+	// ReservationID is set for booked occurrences that could not be cancelled
+	ReservationID uint      `json:"reservation_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	Reason        string    `json:"reason"`
+}
+
+// SeriesResult is the outcome of creating, reading or cancelling a series
+type SeriesResult struct {
+	Series       *ReservationSeries   `json:"series"`
+	Reservations []Reservation        `json:"reservations"`
+	Conflicts    []OccurrenceConflict `json:"conflicts,omitempty"`
+	// Failed lists the occurrences a cancellation could not cancel; the series stays active
+	// until they are, so cancelling it again retries them
+	Failed []OccurrenceConflict `json:"failed,omitempty"`
+}
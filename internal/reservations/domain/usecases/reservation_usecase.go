@@ -2,8 +2,18 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"go.uber.org/zap"
+
+	addressModels "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
 )
 
 // ReservationRepository defines the interface for reservation data access
@@ -11,27 +21,652 @@ type ReservationRepository interface {
 	FindAll(ctx context.Context) ([]models.Reservation, error)
 	FindByID(ctx context.Context, id uint) (*models.Reservation, error)
 	FindByUserID(ctx context.Context, userID uint) ([]models.Reservation, error)
+	// FindStartingBetween returns reservations starting in [from, to), in chronological order
+	FindStartingBetween(ctx context.Context, from, to time.Time) ([]models.Reservation, error)
+	// CreateIfAvailable atomically checks the slot is free for the reservation's time range and persists it.
+	// Holds expired at now do not keep the slot.
+	// Returns common.ErrSlotNotAvailable when an active reservation already holds it
+	// and common.ErrInvalidInput when the client does not exist.
+	CreateIfAvailable(ctx context.Context, reservation *models.Reservation, now time.Time) error
 	Create(ctx context.Context, reservation *models.Reservation) error
+	// UpdateStatus moves a reservation from change.FromStatus to change.ToStatus and records the change.
+	// Returns common.ErrConflict if the stored status is no longer change.FromStatus.
+	UpdateStatus(ctx context.Context, change *models.ReservationStatusChange) error
+	// Cancel applies a cancelling status change like UpdateStatus and saves cancellation in the same transaction
+	Cancel(ctx context.Context, change *models.ReservationStatusChange, cancellation *models.ReservationCancellation) error
+	// FindCancellation returns the cancellation of a reservation, or common.ErrNotFound if it has none
+	FindCancellation(ctx context.Context, reservationID uint) (*models.ReservationCancellation, error)
+	// FindPendingRefunds returns the cancellations whose refund is still pending, oldest first
+	FindPendingRefunds(ctx context.Context) ([]models.ReservationCancellation, error)
+	UpdateCancellation(ctx context.Context, cancellation *models.ReservationCancellation) error
+	FindStatusChanges(ctx context.Context, reservationID uint) ([]models.ReservationStatusChange, error)
+	// FindExpiredHolds returns pending holds whose expiry is at or before now
+	FindExpiredHolds(ctx context.Context, now time.Time) ([]models.Reservation, error)
+	// Reschedule atomically moves a reservation to a free slot/start time and records the previous one.
+	// Returns common.ErrSlotNotAvailable if the target is taken.
+	Reschedule(ctx context.Context, reschedule *models.ReservationReschedule, now time.Time) error
+	FindReschedules(ctx context.Context, reservationID uint) ([]models.ReservationReschedule, error)
+	// CreateSeries persists a series and its free occurrences atomically, returning the booked ones.
+	// Returns common.ErrSlotNotAvailable if no occurrence is free and common.ErrInvalidInput for an unknown client.
+	CreateSeries(ctx context.Context, series *models.ReservationSeries, occurrences []models.Reservation, now time.Time) ([]models.Reservation, error)
+	FindSeriesByID(ctx context.Context, id uint) (*models.ReservationSeries, error)
+	FindBySeriesID(ctx context.Context, seriesID uint) ([]models.Reservation, error)
+	UpdateSeriesStatus(ctx context.Context, id uint, status models.SeriesStatus) error
 	Update(ctx context.Context, reservation *models.Reservation) error
 	Delete(ctx context.Context, id uint) error
 }
 
+// ScheduleValidator validates a requested booking against the slot schedule
+type ScheduleValidator interface {
+	ValidateStartTime(ctx context.Context, slotID uint, startTime time.Time, duration time.Duration) error
+	// Location returns the business location opening hours are defined in
+	Location() *time.Location
+}
+
+// ServiceCatalog looks up services that can be booked
+type ServiceCatalog interface {
+	GetBookableService(ctx context.Context, id uint) (*serviceModels.Service, error)
+}
+
+// VehicleRegistry looks up the vehicles a client can book a wash for
+type VehicleRegistry interface {
+	GetBookableVehicle(ctx context.Context, clientID, id uint) (*clientModels.Vehicle, error)
+}
+
+// AddressBook looks up the addresses a client can book a wash at
+type AddressBook interface {
+	GetBookableAddress(ctx context.Context, clientID, id uint) (*addressModels.Address, error)
+}
+
+// PaymentLedger exposes the payments of a reservation to the cancellation and completion flows
+type PaymentLedger interface {
+	// PaidAmount returns the captured, not yet refunded amount for a reservation
+	PaidAmount(ctx context.Context, reservationID uint) (common.Money, error)
+	// WithPaidAmount calls fn with the captured, not yet refunded amount for a reservation,
+	// which no payment or refund changes until fn returns
+	WithPaidAmount(ctx context.Context, reservationID uint, fn func(paid common.Money) error) error
+	// RefundReservation makes sure amount has been refunded across the reservation's payments.
+	// Refunds made by earlier calls count toward amount, so a failed call can be retried.
+	RefundReservation(ctx context.Context, reservationID uint, amount common.Money) error
+}
+
+// SlotReleaseListener is notified after an active reservation frees its slot,
+// either because it was cancelled or because its hold expired
+type SlotReleaseListener interface {
+	SlotReleased(ctx context.Context, reservation models.Reservation)
+}
+
+// ConfirmationListener is notified after a reservation is confirmed
+type ConfirmationListener interface {
+	ReservationConfirmed(ctx context.Context, reservation models.Reservation)
+}
+
+// CreateReservationInput holds the data required to create a reservation
+type CreateReservationInput struct {
+	UserID    uint
+	SlotID    uint
+	AddressID uint
+	ServiceID uint
+	// VehicleID is optional; it must belong to UserID
+	VehicleID uint
+	StartTime time.Time
+	Notes     string
+}
+
+// RescheduleReservationInput holds the new slot and start time for a reservation
+type RescheduleReservationInput struct {
+	SlotID    uint
+	StartTime time.Time
+	ChangedBy string
+	Reason    string
+}
+
+// StatusChangeInput identifies who is changing a reservation status and why
+type StatusChangeInput struct {
+	ChangedBy string
+	Reason    string
+	// OverrideBalance lets a reservation be completed before it is fully paid; a Reason is then required
+	OverrideBalance bool
+}
+
 // ReservationUseCase handles reservation business logic
 type ReservationUseCase struct {
-	repo ReservationRepository
+	repo      ReservationRepository
+	schedule  ScheduleValidator
+	services  ServiceCatalog
+	vehicles  VehicleRegistry
+	addresses AddressBook
+	payments  PaymentLedger
+	policy    models.CancellationPolicy
+	pricing   models.PricingPolicy
+	holdTTL   time.Duration
+	now       common.Clock
+
+	releaseListeners      []SlotReleaseListener
+	confirmationListeners []ConfirmationListener
 }
 
-// NewReservationUseCase creates a new reservation use case
-func NewReservationUseCase(repo ReservationRepository) *ReservationUseCase {
+// NewReservationUseCase creates a new reservation use case.
+// Reservations are priced from the service base price and the vehicle size under pricing.
+// Holds created by HoldReservation expire holdTTL after creation unless confirmed.
+// now is the clock holds expire, and cancellations and status changes are dated, against.
+func NewReservationUseCase(repo ReservationRepository, schedule ScheduleValidator, services ServiceCatalog, vehicles VehicleRegistry, addresses AddressBook, payments PaymentLedger, policy models.CancellationPolicy, pricing models.PricingPolicy, holdTTL time.Duration, now common.Clock) *ReservationUseCase {
 	return &ReservationUseCase{
-		repo: repo,
+		repo:      repo,
+		schedule:  schedule,
+		services:  services,
+		vehicles:  vehicles,
+		addresses: addresses,
+		payments:  payments,
+		policy:    policy,
+		pricing:   pricing,
+		holdTTL:   holdTTL,
+		now:       now,
+	}
+}
+
+// AddSlotReleaseListener registers a listener for freed slots (e.g., the waitlist)
+func (uc *ReservationUseCase) AddSlotReleaseListener(listener SlotReleaseListener) {
+	uc.releaseListeners = append(uc.releaseListeners, listener)
+}
+
+// AddConfirmationListener registers a listener for confirmed reservations (e.g., the waitlist)
+func (uc *ReservationUseCase) AddConfirmationListener(listener ConfirmationListener) {
+	uc.confirmationListeners = append(uc.confirmationListeners, listener)
+}
+
+// CreateReservation validates and books a slot at the requested start time
+func (uc *ReservationUseCase) CreateReservation(ctx context.Context, input CreateReservationInput) (*models.Reservation, error) {
+	return uc.book(ctx, input, false)
+}
+
+// HoldReservation books a slot as a short-lived pending reservation while the customer pays.
+// The hold keeps the slot until it is confirmed or its expires_at passes.
+func (uc *ReservationUseCase) HoldReservation(ctx context.Context, input CreateReservationInput) (*models.Reservation, error) {
+	return uc.book(ctx, input, true)
+}
+
+// ReleaseExpiredHolds cancels holds that expired before being confirmed and returns them
+func (uc *ReservationUseCase) ReleaseExpiredHolds(ctx context.Context) ([]models.Reservation, error) {
+	now := uc.now()
+	holds, err := uc.repo.FindExpiredHolds(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	released := make([]models.Reservation, 0, len(holds))
+	for i := range holds {
+		change := &models.ReservationStatusChange{
+			ReservationID: holds[i].ID,
+			FromStatus:    models.ReservationStatusPending,
+			ToStatus:      models.ReservationStatusCancelled,
+			ChangedBy:     "system",
+			Reason:        "hold expired",
+			ChangedAt:     now,
+		}
+
+		if err := uc.repo.UpdateStatus(ctx, change); err != nil {
+			// Confirmed or cancelled since it was loaded
+			if errors.Is(err, common.ErrConflict) {
+				continue
+			}
+			return released, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+
+		holds[i].Status = models.ReservationStatusCancelled
+		released = append(released, holds[i])
+		uc.slotReleased(ctx, holds[i])
+	}
+
+	return released, nil
+}
+
+// book validates and persists a reservation, as a hold expiring after holdTTL when hold is set
+func (uc *ReservationUseCase) book(ctx context.Context, input CreateReservationInput, hold bool) (*models.Reservation, error) {
+	if input.UserID == 0 || input.SlotID == 0 || input.AddressID == 0 {
+		return nil, fmt.Errorf("%w: user_id, slot_id and address_id are required", common.ErrInvalidInput)
 	}
+
+	terms, err := uc.bookingTerms(ctx, input.UserID, input.AddressID, input.ServiceID, input.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.schedule.ValidateStartTime(ctx, input.SlotID, input.StartTime, terms.duration); err != nil {
+		return nil, err
+	}
+
+	reservation := &models.Reservation{
+		UserID:    input.UserID,
+		SlotID:    input.SlotID,
+		AddressID: input.AddressID,
+		ServiceID: input.ServiceID,
+		VehicleID: terms.vehicleID,
+		Price:     terms.price,
+		StartTime: input.StartTime,
+		EndTime:   input.StartTime.Add(terms.duration),
+		Status:    models.ReservationStatusPending,
+		Notes:     input.Notes,
+	}
+
+	now := uc.now()
+	if hold {
+		expiresAt := now.Add(uc.holdTTL)
+		reservation.ExpiresAt = &expiresAt
+	}
+
+	if err := uc.repo.CreateIfAvailable(ctx, reservation, now); err != nil {
+		if errors.Is(err, common.ErrSlotNotAvailable) || errors.Is(err, common.ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return reservation, nil
 }
 
-// TODO: Implement use case methods
-// - CreateReservation
-// - GetReservation
-// - ListReservations
-// - UpdateReservation
-// - CancelReservation
+// bookingTerms holds what a booking's service and vehicle determine
+type bookingTerms struct {
+	duration  time.Duration
+	price     common.Money
+	vehicleID *uint
+}
+
+// bookingTerms resolves how long a booking lasts and what it costs.
+// serviceID and vehicleID are optional; the address and vehicle must belong to userID.
+func (uc *ReservationUseCase) bookingTerms(ctx context.Context, userID, addressID, serviceID, vehicleID uint) (bookingTerms, error) {
+	terms := bookingTerms{
+		duration: models.DefaultReservationDuration,
+		price:    common.Money{Currency: common.DefaultCurrency},
+	}
+
+	if _, err := uc.addresses.GetBookableAddress(ctx, userID, addressID); err != nil {
+		return bookingTerms{}, err
+	}
 
+	size := clientModels.VehicleSizeCar
+	if vehicleID != 0 {
+		vehicle, err := uc.vehicles.GetBookableVehicle(ctx, userID, vehicleID)
+		if err != nil {
+			return bookingTerms{}, err
+		}
+		size = vehicle.Size
+		terms.vehicleID = &vehicle.ID
+	}
+
+	if serviceID != 0 {
+		service, err := uc.services.GetBookableService(ctx, serviceID)
+		if err != nil {
+			return bookingTerms{}, err
+		}
+		terms.duration = service.Duration()
+		terms.price = uc.pricing.Price(service.BasePrice, size)
+	}
+
+	return terms, nil
+}
+
+// GetReservation returns a reservation by ID
+func (uc *ReservationUseCase) GetReservation(ctx context.Context, id uint) (*models.Reservation, error) {
+	reservation, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return reservation, nil
+}
+
+// ListUserReservations returns a client's reservations, in chronological order
+func (uc *ReservationUseCase) ListUserReservations(ctx context.Context, userID uint) ([]models.Reservation, error) {
+	reservations, err := uc.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return reservations, nil
+}
+
+// ListSchedule returns every reservation starting on a business date (YYYY-MM-DD,
+// today if empty), ordered by start time and slot, for staff running the day
+func (uc *ReservationUseCase) ListSchedule(ctx context.Context, date string) ([]models.Reservation, error) {
+	loc := uc.schedule.Location()
+
+	day := uc.now().In(loc)
+	if date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date %q, expected YYYY-MM-DD", common.ErrInvalidInput, date)
+		}
+		day = parsed
+	}
+
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	reservations, err := uc.repo.FindStartingBetween(ctx, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return reservations, nil
+}
+
+// RescheduleReservation moves an active reservation to a new slot and start time.
+// The reservation keeps its ID, so linked payments stay attached.
+func (uc *ReservationUseCase) RescheduleReservation(ctx context.Context, id uint, input RescheduleReservationInput) (*models.Reservation, error) {
+	if input.SlotID == 0 {
+		return nil, fmt.Errorf("%w: slot_id is required", common.ErrInvalidInput)
+	}
+
+	reservation, err := uc.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reservation.IsActive() {
+		return nil, fmt.Errorf("%w: cannot reschedule a %s reservation", common.ErrConflict, reservation.Status)
+	}
+
+	if reservation.SlotID == input.SlotID && reservation.StartTime.Equal(input.StartTime) {
+		return nil, fmt.Errorf("%w: reservation is already at the requested slot and time", common.ErrInvalidInput)
+	}
+
+	// The reservation keeps its service, so it keeps its duration
+	duration := reservation.EffectiveEndTime().Sub(reservation.StartTime)
+	if err := uc.schedule.ValidateStartTime(ctx, input.SlotID, input.StartTime, duration); err != nil {
+		return nil, err
+	}
+
+	reschedule := &models.ReservationReschedule{
+		ReservationID: reservation.ID,
+		FromSlotID:    reservation.SlotID,
+		FromStartTime: reservation.StartTime,
+		ToSlotID:      input.SlotID,
+		ToStartTime:   input.StartTime,
+		ToEndTime:     input.StartTime.Add(duration),
+		ChangedBy:     input.ChangedBy,
+		Reason:        input.Reason,
+		RescheduledAt: uc.now(),
+	}
+
+	if err := uc.repo.Reschedule(ctx, reschedule, reschedule.RescheduledAt); err != nil {
+		if errors.Is(err, common.ErrSlotNotAvailable) || errors.Is(err, common.ErrConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	reservation.SlotID = input.SlotID
+	reservation.StartTime = input.StartTime
+	reservation.EndTime = reschedule.ToEndTime
+	return reservation, nil
+}
+
+// GetRescheduleHistory returns the previous slots and times of a reservation, oldest first
+func (uc *ReservationUseCase) GetRescheduleHistory(ctx context.Context, id uint) ([]models.ReservationReschedule, error) {
+	if _, err := uc.GetReservation(ctx, id); err != nil {
+		return nil, err
+	}
+
+	reschedules, err := uc.repo.FindReschedules(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return reschedules, nil
+}
+
+// ConfirmReservation moves a pending reservation to confirmed
+func (uc *ReservationUseCase) ConfirmReservation(ctx context.Context, id uint, input StatusChangeInput) (*models.Reservation, error) {
+	reservation, err := uc.changeStatus(ctx, id, models.ReservationStatusConfirmed, input)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, listener := range uc.confirmationListeners {
+		listener.ReservationConfirmed(ctx, *reservation)
+	}
+	return reservation, nil
+}
+
+// CancelReservation cancels a pending or confirmed reservation and refunds its payments
+// according to the cancellation policy. The refund owed is saved with the cancellation; if the
+// refund fails it is left pending for RetryPendingRefunds, and the cancellation still succeeds.
+func (uc *ReservationUseCase) CancelReservation(ctx context.Context, id uint, input StatusChangeInput) (*models.CancellationResult, error) {
+	reservation, err := uc.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := uc.newStatusChange(reservation, models.ReservationStatusCancelled, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancel while what has been paid cannot change, so a payment recorded meanwhile is not left out of the refund
+	percent := uc.policy.RefundPercent(reservation.StartTime, change.ChangedAt)
+	var cancellation *models.ReservationCancellation
+	var fee common.Money
+	err = uc.payments.WithPaidAmount(ctx, reservation.ID, func(paid common.Money) error {
+		cancellation = &models.ReservationCancellation{
+			ReservationID: reservation.ID,
+			RefundPercent: percent,
+			PaidAmount:    paid,
+			RefundAmount:  paid.Percent(percent),
+			RefundStatus:  models.RefundStatusNone,
+		}
+		if cancellation.RefundAmount.IsPositive() {
+			cancellation.RefundStatus = models.RefundStatusPending
+		}
+		var err error
+		if fee, err = paid.Sub(cancellation.RefundAmount); err != nil {
+			return err
+		}
+
+		if err := uc.repo.Cancel(ctx, change, cancellation); err != nil {
+			if errors.Is(err, common.ErrConflict) {
+				return err
+			}
+			return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reservation.Status = models.ReservationStatusCancelled
+	uc.slotReleased(ctx, *reservation)
+	uc.refundCancellation(ctx, cancellation)
+
+	return &models.CancellationResult{
+		Reservation:   reservation,
+		RefundPercent: percent,
+		PaidAmount:    cancellation.PaidAmount,
+		RefundAmount:  cancellation.RefundAmount,
+		FeeAmount:     fee,
+		RefundStatus:  cancellation.RefundStatus,
+	}, nil
+}
+
+// RetryPendingRefunds retries the refunds of cancellations that failed to refund,
+// returning the cancellations refunded this time
+func (uc *ReservationUseCase) RetryPendingRefunds(ctx context.Context) ([]models.ReservationCancellation, error) {
+	pending, err := uc.repo.FindPendingRefunds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	refunded := make([]models.ReservationCancellation, 0, len(pending))
+	for i := range pending {
+		uc.refundCancellation(ctx, &pending[i])
+		if pending[i].RefundStatus == models.RefundStatusRefunded {
+			refunded = append(refunded, pending[i])
+		}
+	}
+	return refunded, nil
+}
+
+// refundCancellation refunds what a cancellation owes and records the attempt.
+// A failed refund stays pending, with its error, to be retried.
+func (uc *ReservationUseCase) refundCancellation(ctx context.Context, cancellation *models.ReservationCancellation) {
+	if cancellation.RefundStatus != models.RefundStatusPending {
+		return
+	}
+
+	cancellation.RefundAttempts++
+	if err := uc.payments.RefundReservation(ctx, cancellation.ReservationID, cancellation.RefundAmount); err != nil {
+		common.Logger.Error("Failed to refund cancelled reservation",
+			zap.Uint("reservation_id", cancellation.ReservationID),
+			zap.Stringer("refund_amount", cancellation.RefundAmount),
+			zap.Int("attempts", cancellation.RefundAttempts),
+			zap.Error(err),
+		)
+		cancellation.RefundError = err.Error()
+	} else {
+		refundedAt := uc.now()
+		cancellation.RefundStatus = models.RefundStatusRefunded
+		cancellation.RefundError = ""
+		cancellation.RefundedAt = &refundedAt
+	}
+
+	if err := uc.repo.UpdateCancellation(ctx, cancellation); err != nil {
+		common.Logger.Error("Failed to record cancellation refund",
+			zap.Uint("reservation_id", cancellation.ReservationID),
+			zap.String("refund_status", string(cancellation.RefundStatus)),
+			zap.Error(err),
+		)
+	}
+}
+
+// CompleteReservation marks a confirmed reservation as completed. A reservation with an outstanding
+// balance is only completed with OverrideBalance.
+func (uc *ReservationUseCase) CompleteReservation(ctx context.Context, id uint, input StatusChangeInput) (*models.Reservation, error) {
+	reservation, err := uc.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The balance is checked and the reservation completed without payments or refunds in between
+	var completed *models.Reservation
+	err = uc.payments.WithPaidAmount(ctx, id, func(paid common.Money) error {
+		balance, err := models.NewBalance(reservation, paid)
+		if err != nil {
+			return err
+		}
+
+		if !balance.Settled() {
+			if !input.OverrideBalance {
+				return fmt.Errorf("%w: reservation %d has %s outstanding", common.ErrConflict, id, balance.Outstanding)
+			}
+			if strings.TrimSpace(input.Reason) == "" {
+				return fmt.Errorf("%w: a reason is required to complete a reservation with an outstanding balance", common.ErrInvalidInput)
+			}
+		}
+
+		completed, err = uc.changeStatus(ctx, id, models.ReservationStatusCompleted, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// GetBalance returns what has been paid on a reservation and what is left to pay
+func (uc *ReservationUseCase) GetBalance(ctx context.Context, id uint) (*models.Balance, error) {
+	reservation, err := uc.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	paid, err := uc.payments.PaidAmount(ctx, reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := models.NewBalance(reservation, paid)
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetStatusHistory returns the status changes of a reservation, oldest first
+func (uc *ReservationUseCase) GetStatusHistory(ctx context.Context, id uint) ([]models.ReservationStatusChange, error) {
+	if _, err := uc.GetReservation(ctx, id); err != nil {
+		return nil, err
+	}
+
+	changes, err := uc.repo.FindStatusChanges(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return changes, nil
+}
+
+// GetCancellation returns how a cancelled reservation's payments were settled.
+// Reservations that were not cancelled, and holds that expired, have no cancellation.
+func (uc *ReservationUseCase) GetCancellation(ctx context.Context, reservationID uint) (*models.ReservationCancellation, error) {
+	cancellation, err := uc.repo.FindCancellation(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: reservation %d has no cancellation", common.ErrNotFound, reservationID)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return cancellation, nil
+}
+
+// slotReleased notifies the release listeners that reservation freed its slot
+func (uc *ReservationUseCase) slotReleased(ctx context.Context, reservation models.Reservation) {
+	for _, listener := range uc.releaseListeners {
+		listener.SlotReleased(ctx, reservation)
+	}
+}
+
+// changeStatus applies a state machine transition and records who made it
+func (uc *ReservationUseCase) changeStatus(ctx context.Context, id uint, to models.ReservationStatus, input StatusChangeInput) (*models.Reservation, error) {
+	reservation, err := uc.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := uc.newStatusChange(reservation, to, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateStatus(ctx, change); err != nil {
+		if errors.Is(err, common.ErrConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	reservation.Status = to
+	if to == models.ReservationStatusConfirmed {
+		reservation.ExpiresAt = nil
+	}
+	return reservation, nil
+}
+
+// newStatusChange checks reservation may move to status to and describes the change
+func (uc *ReservationUseCase) newStatusChange(reservation *models.Reservation, to models.ReservationStatus, input StatusChangeInput) (*models.ReservationStatusChange, error) {
+	if !reservation.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: cannot change reservation status from %s to %s", common.ErrConflict, reservation.Status, to)
+	}
+
+	now := uc.now()
+
+	// An expired hold no longer keeps its slot, so it cannot be confirmed
+	if to == models.ReservationStatusConfirmed && reservation.HoldExpired(now) {
+		return nil, fmt.Errorf("%w: reservation hold expired", common.ErrConflict)
+	}
+
+	return &models.ReservationStatusChange{
+		ReservationID: reservation.ID,
+		FromStatus:    reservation.Status,
+		ToStatus:      to,
+		ChangedBy:     input.ChangedBy,
+		Reason:        input.Reason,
+		ChangedAt:     now,
+	}, nil
+}
@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+)
+
+// maxSeriesOccurrences bounds how many reservations a single series may materialize
+const maxSeriesOccurrences = 52
+
+// CreateSeriesInput holds the data required to create a recurring reservation series.
+// Exactly one of Until and Count must be set.
+type CreateSeriesInput struct {
+	UserID    uint
+	SlotID    uint
+	AddressID uint
+	ServiceID uint
+	VehicleID uint
+	StartTime time.Time
+	Frequency models.RecurrenceFrequency
+	Until     *time.Time
+	Count     int
+	Notes     string
+}
+
+// CreateSeries books a recurring series of reservations on the same slot.
+// Every occurrence is validated and booked like a single reservation; occurrences that
+// cannot be booked are reported as conflicts instead of failing the whole series.
+func (uc *ReservationUseCase) CreateSeries(ctx context.Context, input CreateSeriesInput) (*models.SeriesResult, error) {
+	if input.UserID == 0 || input.SlotID == 0 || input.AddressID == 0 {
+		return nil, fmt.Errorf("%w: user_id, slot_id and address_id are required", common.ErrInvalidInput)
+	}
+	if !input.Frequency.IsValid() {
+		return nil, fmt.Errorf("%w: frequency must be weekly, biweekly or monthly", common.ErrInvalidInput)
+	}
+
+	// Repeat on the business wall clock so occurrences keep their hour across DST changes
+	input.StartTime = input.StartTime.In(uc.schedule.Location())
+	starts, err := seriesStarts(input)
+	if err != nil {
+		return nil, err
+	}
+
+	terms, err := uc.bookingTerms(ctx, input.UserID, input.AddressID, input.ServiceID, input.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		occurrences []models.Reservation
+		conflicts   []models.OccurrenceConflict
+	)
+	for i, start := range starts {
+		if err := uc.schedule.ValidateStartTime(ctx, input.SlotID, start, terms.duration); err != nil {
+			// A first occurrence off the grid or outside opening hours is an input error
+			if i == 0 && errors.Is(err, common.ErrInvalidInput) {
+				return nil, err
+			}
+			if errors.Is(err, common.ErrInvalidInput) || errors.Is(err, common.ErrSlotNotAvailable) {
+				conflicts = append(conflicts, models.OccurrenceConflict{StartTime: start, Reason: err.Error()})
+				continue
+			}
+			return nil, err
+		}
+
+		occurrences = append(occurrences, models.Reservation{
+			UserID:    input.UserID,
+			SlotID:    input.SlotID,
+			AddressID: input.AddressID,
+			ServiceID: input.ServiceID,
+			VehicleID: terms.vehicleID,
+			Price:     terms.price,
+			StartTime: start,
+			EndTime:   start.Add(terms.duration),
+			Status:    models.ReservationStatusPending,
+			Notes:     input.Notes,
+		})
+	}
+
+	if len(occurrences) == 0 {
+		return nil, fmt.Errorf("%w: no occurrence of the series can be booked", common.ErrSlotNotAvailable)
+	}
+
+	series := &models.ReservationSeries{
+		UserID:         input.UserID,
+		SlotID:         input.SlotID,
+		AddressID:      input.AddressID,
+		ServiceID:      input.ServiceID,
+		VehicleID:      terms.vehicleID,
+		FirstStartTime: input.StartTime,
+		Frequency:      input.Frequency,
+		Until:          input.Until,
+		Count:          input.Count,
+		Status:         models.SeriesStatusActive,
+		Notes:          input.Notes,
+	}
+
+	created, err := uc.repo.CreateSeries(ctx, series, occurrences, uc.now())
+	if err != nil {
+		if errors.Is(err, common.ErrSlotNotAvailable) {
+			return nil, fmt.Errorf("%w: every occurrence of the series is already booked", common.ErrSlotNotAvailable)
+		}
+		if errors.Is(err, common.ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	// Occurrences the repository skipped were taken by another reservation
+	booked := make(map[time.Time]bool, len(created))
+	for i := range created {
+		booked[created[i].StartTime.UTC()] = true
+	}
+	for i := range occurrences {
+		if !booked[occurrences[i].StartTime.UTC()] {
+			conflicts = append(conflicts, models.OccurrenceConflict{
+				StartTime: occurrences[i].StartTime,
+				Reason:    common.ErrSlotNotAvailable.Error(),
+			})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].StartTime.Before(conflicts[j].StartTime)
+	})
+
+	return &models.SeriesResult{
+		Series:       series,
+		Reservations: created,
+		Conflicts:    conflicts,
+	}, nil
+}
+
+// GetSeries returns a series with all its occurrences
+func (uc *ReservationUseCase) GetSeries(ctx context.Context, id uint) (*models.SeriesResult, error) {
+	series, err := uc.repo.FindSeriesByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	reservations, err := uc.repo.FindBySeriesID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return &models.SeriesResult{
+		Series:       series,
+		Reservations: reservations,
+	}, nil
+}
+
+// CancelSeries cancels every upcoming active occurrence of a series, applying the
+// cancellation policy to each, and ends the series. Single occurrences are cancelled
+// through CancelReservation. Occurrences that fail to cancel are reported in Failed and
+// the series stays active, so cancelling it again picks up where this call stopped.
+func (uc *ReservationUseCase) CancelSeries(ctx context.Context, id uint, input StatusChangeInput) (*models.SeriesResult, error) {
+	result, err := uc.GetSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Series.Status == models.SeriesStatusCancelled {
+		return nil, fmt.Errorf("%w: series %d is already cancelled", common.ErrConflict, id)
+	}
+
+	var failed []models.OccurrenceConflict
+	now := uc.now()
+	for i := range result.Reservations {
+		occurrence := &result.Reservations[i]
+		if !occurrence.IsActive() || !occurrence.StartTime.After(now) {
+			continue
+		}
+
+		if _, err := uc.CancelReservation(ctx, occurrence.ID, input); err != nil {
+			// Skip occurrences cancelled or completed concurrently; any other conflict is a failure
+			if errors.Is(err, common.ErrConflict) {
+				if current, getErr := uc.GetReservation(ctx, occurrence.ID); getErr == nil && !current.IsActive() {
+					continue
+				}
+			}
+			common.Logger.Error("Failed to cancel series occurrence",
+				zap.Uint("series_id", id),
+				zap.Uint("reservation_id", occurrence.ID),
+				zap.Error(err),
+			)
+			failed = append(failed, models.OccurrenceConflict{
+				ReservationID: occurrence.ID,
+				StartTime:     occurrence.StartTime,
+				Reason:        err.Error(),
+			})
+		}
+	}
+
+	if len(failed) == 0 {
+		if err := uc.repo.UpdateSeriesStatus(ctx, id, models.SeriesStatusCancelled); err != nil {
+			return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+	}
+
+	result, err = uc.GetSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result.Failed = failed
+	return result, nil
+}
+
+// seriesStarts lists the start time of every occurrence of a series
+func seriesStarts(input CreateSeriesInput) ([]time.Time, error) {
+	if (input.Until == nil) == (input.Count == 0) {
+		return nil, fmt.Errorf("%w: exactly one of until and count is required", common.ErrInvalidInput)
+	}
+	if input.StartTime.IsZero() {
+		return nil, fmt.Errorf("%w: start_time is required", common.ErrInvalidInput)
+	}
+
+	if input.Count != 0 {
+		if input.Count < 1 || input.Count > maxSeriesOccurrences {
+			return nil, fmt.Errorf("%w: count must be between 1 and %d", common.ErrInvalidInput, maxSeriesOccurrences)
+		}
+
+		starts := make([]time.Time, 0, input.Count)
+		for n := 0; n < input.Count; n++ {
+			starts = append(starts, input.Frequency.Occurrence(input.StartTime, n))
+		}
+		return starts, nil
+	}
+
+	if input.Until.Before(input.StartTime) {
+		return nil, fmt.Errorf("%w: until cannot be before start_time", common.ErrInvalidInput)
+	}
+
+	var starts []time.Time
+	for n := 0; ; n++ {
+		start := input.Frequency.Occurrence(input.StartTime, n)
+		if start.After(*input.Until) {
+			return starts, nil
+		}
+		if n == maxSeriesOccurrences {
+			return nil, fmt.Errorf("%w: a series cannot have more than %d occurrences", common.ErrInvalidInput, maxSeriesOccurrences)
+		}
+		starts = append(starts, start)
+	}
+}
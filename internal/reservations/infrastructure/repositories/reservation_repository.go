@@ -2,11 +2,32 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	"github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
 	"gorm.io/gorm"
 )
 
+// bookingMu serializes availability check + insert across repository instances.
+// SQLite takes its write lock lazily, so two deferred transactions could otherwise
+// both read a free slot before either writes.
+// Being in memory, it only covers a single API process: running several against one
+// database needs the check done under a database lock (e.g. SELECT ... FOR UPDATE) instead.
+var bookingMu sync.Mutex
+
+// maxReservationSpan bounds how far back a reservation can start and still overlap a new one
+const maxReservationSpan = 24 * time.Hour
+
+// activeStatuses are the reservation statuses that occupy a slot
+var activeStatuses = []string{
+	string(models.ReservationStatusPending),
+	string(models.ReservationStatusConfirmed),
+}
+
 // ReservationRepository implements the reservation repository interface
 type ReservationRepository struct {
 	db *gorm.DB
@@ -28,19 +49,36 @@ func (r *ReservationRepository) FindAll(ctx context.Context) ([]models.Reservati
 	return reservations, nil
 }
 
-// FindByID retrieves a reservation by ID
+// FindByID retrieves a reservation by ID with its vehicle, even if the vehicle was since deleted
 func (r *ReservationRepository) FindByID(ctx context.Context, id uint) (*models.Reservation, error) {
 	var reservation models.Reservation
-	if err := r.db.WithContext(ctx).First(&reservation, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Vehicle", withDeleted).First(&reservation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
 		return nil, err
 	}
 	return &reservation, nil
 }
 
-// FindByUserID retrieves all reservations for a user
+// FindByUserID retrieves all reservations for a user, in chronological order
 func (r *ReservationRepository) FindByUserID(ctx context.Context, userID uint) ([]models.Reservation, error) {
 	var reservations []models.Reservation
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&reservations).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("start_time ASC, id ASC").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// FindStartingBetween retrieves reservations starting in [from, to) with their vehicle,
+// ordered by start time and slot
+func (r *ReservationRepository) FindStartingBetween(ctx context.Context, from, to time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	if err := r.db.WithContext(ctx).
+		Preload("Vehicle", withDeleted).
+		Where("start_time >= ? AND start_time < ?", from.UTC(), to.UTC()).
+		Order("start_time ASC, slot_id ASC").
+		Find(&reservations).Error; err != nil {
 		return nil, err
 	}
 	return reservations, nil
@@ -51,6 +89,271 @@ func (r *ReservationRepository) Create(ctx context.Context, reservation *models.
 	return r.db.WithContext(ctx).Create(reservation).Error
 }
 
+// CreateIfAvailable creates a reservation only if no active reservation overlaps its slot and time range.
+// The check and insert run in a single transaction; returns common.ErrSlotNotAvailable on conflict
+// and common.ErrInvalidInput if the client does not exist.
+func (r *ReservationRepository) CreateIfAvailable(ctx context.Context, reservation *models.Reservation, now time.Time) error {
+	bookingMu.Lock()
+	defer bookingMu.Unlock()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureSlotFree(tx, reservation.SlotID, reservation.StartTime, reservation.EffectiveEndTime(), 0, now); err != nil {
+			return err
+		}
+
+		return tx.Create(reservation).Error
+	})
+	return mapClientViolation(err, reservation.UserID)
+}
+
+// CreateSeries creates a series and every occurrence whose slot and time range is free, in one transaction.
+// Occurrences that overlap an active reservation are skipped; the created ones are returned.
+// Returns common.ErrSlotNotAvailable, creating nothing, if no occurrence is free
+// and common.ErrInvalidInput if the client does not exist.
+func (r *ReservationRepository) CreateSeries(ctx context.Context, series *models.ReservationSeries, occurrences []models.Reservation, now time.Time) ([]models.Reservation, error) {
+	bookingMu.Lock()
+	defer bookingMu.Unlock()
+
+	var created []models.Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(series).Error; err != nil {
+			return err
+		}
+
+		for i := range occurrences {
+			occurrence := occurrences[i]
+			occurrence.SeriesID = &series.ID
+
+			if err := ensureSlotFree(tx, occurrence.SlotID, occurrence.StartTime, occurrence.EffectiveEndTime(), 0, now); err != nil {
+				if errors.Is(err, common.ErrSlotNotAvailable) {
+					continue
+				}
+				return err
+			}
+
+			if err := tx.Create(&occurrence).Error; err != nil {
+				return err
+			}
+			created = append(created, occurrence)
+		}
+
+		if len(created) == 0 {
+			return common.ErrSlotNotAvailable
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, mapClientViolation(err, series.UserID)
+	}
+	return created, nil
+}
+
+// FindSeriesByID retrieves a reservation series by ID
+func (r *ReservationRepository) FindSeriesByID(ctx context.Context, id uint) (*models.ReservationSeries, error) {
+	var series models.ReservationSeries
+	if err := r.db.WithContext(ctx).First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindBySeriesID retrieves the occurrences of a series with their vehicle, in chronological order
+func (r *ReservationRepository) FindBySeriesID(ctx context.Context, seriesID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	if err := r.db.WithContext(ctx).
+		Preload("Vehicle", withDeleted).
+		Where("series_id = ?", seriesID).
+		Order("start_time ASC").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// UpdateSeriesStatus sets the status of a series
+func (r *ReservationRepository) UpdateSeriesStatus(ctx context.Context, id uint, status models.SeriesStatus) error {
+	return r.db.WithContext(ctx).Model(&models.ReservationSeries{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// Reschedule moves a reservation to reschedule.ToSlotID/ToStartTime/ToEndTime if that range is free,
+// and records the previous slot and time in the same transaction.
+// Returns common.ErrSlotNotAvailable on conflict and common.ErrConflict if the reservation
+// was moved or is no longer active.
+func (r *ReservationRepository) Reschedule(ctx context.Context, reschedule *models.ReservationReschedule, now time.Time) error {
+	bookingMu.Lock()
+	defer bookingMu.Unlock()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureSlotFree(tx, reschedule.ToSlotID, reschedule.ToStartTime, reschedule.ToEndTime, reschedule.ReservationID, now); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND slot_id = ? AND start_time = ?", reschedule.ReservationID, reschedule.FromSlotID, reschedule.FromStartTime.UTC()).
+			Where("status IN ?", activeStatuses).
+			Updates(map[string]interface{}{
+				"slot_id":    reschedule.ToSlotID,
+				"start_time": reschedule.ToStartTime.UTC(),
+				"end_time":   reschedule.ToEndTime.UTC(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: reservation %d changed while rescheduling", common.ErrConflict, reschedule.ReservationID)
+		}
+
+		return tx.Create(reschedule).Error
+	})
+}
+
+// FindReschedules retrieves the previous slots and times of a reservation, oldest first
+func (r *ReservationRepository) FindReschedules(ctx context.Context, reservationID uint) ([]models.ReservationReschedule, error) {
+	var reschedules []models.ReservationReschedule
+	if err := r.db.WithContext(ctx).
+		Where("reservation_id = ?", reservationID).
+		Order("rescheduled_at ASC, id ASC").
+		Find(&reschedules).Error; err != nil {
+		return nil, err
+	}
+	return reschedules, nil
+}
+
+// withDeleted includes soft-deleted rows when preloading an association
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// mapClientViolation maps a foreign key violation on user_id to common.ErrInvalidInput
+func mapClientViolation(err error, userID uint) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return fmt.Errorf("%w: client %d does not exist", common.ErrInvalidInput, userID)
+	}
+	return err
+}
+
+// ensureSlotFree returns common.ErrSlotNotAvailable if an active reservation other than
+// excludeID occupies any part of [start, end) on the slot. Holds expired at now do not count.
+func ensureSlotFree(tx *gorm.DB, slotID uint, start, end time.Time, excludeID uint, now time.Time) error {
+	query := tx.Where("slot_id = ?", slotID).
+		Where("start_time < ? AND start_time > ?", end.UTC(), start.Add(-maxReservationSpan).UTC()).
+		Where("status IN ?", activeStatuses)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var candidates []models.Reservation
+	if err := query.Find(&candidates).Error; err != nil {
+		return err
+	}
+
+	for i := range candidates {
+		if candidates[i].Occupies(now) && candidates[i].Overlaps(start, end) {
+			return common.ErrSlotNotAvailable
+		}
+	}
+	return nil
+}
+
+// UpdateStatus moves a reservation to change.ToStatus only if it is still in change.FromStatus,
+// and records the change in the same transaction. Returns common.ErrConflict if the status moved.
+// Confirming a hold clears its expiry so it is no longer released.
+func (r *ReservationRepository) UpdateStatus(ctx context.Context, change *models.ReservationStatusChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateStatus(tx, change)
+	})
+}
+
+// Cancel applies a cancelling status change like UpdateStatus and saves the cancellation's
+// refund in the same transaction
+func (r *ReservationRepository) Cancel(ctx context.Context, change *models.ReservationStatusChange, cancellation *models.ReservationCancellation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateStatus(tx, change); err != nil {
+			return err
+		}
+		return tx.Create(cancellation).Error
+	})
+}
+
+// FindCancellation retrieves the cancellation of a reservation
+func (r *ReservationRepository) FindCancellation(ctx context.Context, reservationID uint) (*models.ReservationCancellation, error) {
+	var cancellation models.ReservationCancellation
+	if err := r.db.WithContext(ctx).Where("reservation_id = ?", reservationID).First(&cancellation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &cancellation, nil
+}
+
+// FindPendingRefunds retrieves the cancellations whose refund is still pending, oldest first
+func (r *ReservationRepository) FindPendingRefunds(ctx context.Context) ([]models.ReservationCancellation, error) {
+	var cancellations []models.ReservationCancellation
+	if err := r.db.WithContext(ctx).
+		Where("refund_status = ?", models.RefundStatusPending).
+		Order("id ASC").
+		Find(&cancellations).Error; err != nil {
+		return nil, err
+	}
+	return cancellations, nil
+}
+
+// UpdateCancellation saves the refund progress of a cancellation
+func (r *ReservationRepository) UpdateCancellation(ctx context.Context, cancellation *models.ReservationCancellation) error {
+	return r.db.WithContext(ctx).Save(cancellation).Error
+}
+
+// updateStatus applies change within tx; see UpdateStatus
+func updateStatus(tx *gorm.DB, change *models.ReservationStatusChange) error {
+	updates := map[string]interface{}{"status": change.ToStatus}
+	if change.ToStatus == models.ReservationStatusConfirmed {
+		updates["expires_at"] = nil
+	}
+
+	result := tx.Model(&models.Reservation{}).
+		Where("id = ? AND status = ?", change.ReservationID, change.FromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: reservation %d is no longer %s", common.ErrConflict, change.ReservationID, change.FromStatus)
+	}
+
+	return tx.Create(change).Error
+}
+
+// FindExpiredHolds retrieves pending holds whose expiry is at or before now
+func (r *ReservationRepository) FindExpiredHolds(ctx context.Context, now time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", models.ReservationStatusPending).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now.UTC()).
+		Order("expires_at ASC").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// FindStatusChanges retrieves the status history of a reservation, oldest first
+func (r *ReservationRepository) FindStatusChanges(ctx context.Context, reservationID uint) ([]models.ReservationStatusChange, error) {
+	var changes []models.ReservationStatusChange
+	if err := r.db.WithContext(ctx).
+		Where("reservation_id = ?", reservationID).
+		Order("changed_at ASC, id ASC").
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
 // Update updates an existing reservation
 func (r *ReservationRepository) Update(ctx context.Context, reservation *models.Reservation) error {
 	return r.db.WithContext(ctx).Save(reservation).Error
@@ -60,4 +363,3 @@ func (r *ReservationRepository) Update(ctx context.Context, reservation *models.
 func (r *ReservationRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.Reservation{}, id).Error
 }
-
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/services/domain/usecases"
+)
+
+// ServiceHandler handles HTTP requests for the service catalog
+type ServiceHandler struct {
+	useCase *usecases.ServiceUseCase
+}
+
+// NewServiceHandler creates a new service handler
+func NewServiceHandler(useCase *usecases.ServiceUseCase) *ServiceHandler {
+	return &ServiceHandler{
+		useCase: useCase,
+	}
+}
+
+// serviceRequest is the request body for creating and updating services
+type serviceRequest struct {
+	Name         string       `json:"name" binding:"required"`
+	Description  string       `json:"description"`
+	DurationMins int          `json:"duration_mins" binding:"required"`
+	BasePrice    common.Money `json:"base_price"`
+	IsActive     *bool        `json:"is_active"`
+}
+
+// toInput converts the request body to a use case input
+func (r serviceRequest) toInput() usecases.ServiceInput {
+	return usecases.ServiceInput{
+		Name:         r.Name,
+		Description:  r.Description,
+		DurationMins: r.DurationMins,
+		BasePrice:    r.BasePrice,
+		IsActive:     r.IsActive,
+	}
+}
+
+// RegisterRoutes registers all service routes
+func (h *ServiceHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	services := rg.Group("/services")
+	{
+		services.GET("", h.List)
+		services.GET("/:id", h.GetByID)
+		services.POST("", h.Create)
+		services.PUT("/:id", h.Update)
+		services.DELETE("/:id", h.Delete)
+	}
+}
+
+// List returns the service catalog
+// @Summary List services
+// @Description Returns active services, or all services with ?all=true
+// @Tags services
+// @Produce json
+// @Success 200 {array} models.Service
+// @Router /api/v1/services [get]
+func (h *ServiceHandler) List(c *gin.Context) {
+	services, err := h.useCase.ListServices(c.Request.Context(), c.Query("all") != "true")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": services,
+	})
+}
+
+// GetByID returns a service by ID
+func (h *ServiceHandler) GetByID(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	service, err := h.useCase.GetService(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": service,
+	})
+}
+
+// Create adds a service to the catalog
+func (h *ServiceHandler) Create(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req serviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	service, err := h.useCase.CreateService(c.Request.Context(), req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": service,
+	})
+}
+
+// Update replaces a service's editable fields
+func (h *ServiceHandler) Update(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req serviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	service, err := h.useCase.UpdateService(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": service,
+	})
+}
+
+// Delete removes a service from the catalog
+func (h *ServiceHandler) Delete(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.useCase.DeleteService(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
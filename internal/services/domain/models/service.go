@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+// Service represents a wash offered by the business (e.g., "Lavado básico", "Detallado completo")
+type Service struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Name         string         `gorm:"type:varchar(100);not null" json:"name"`
+	Description  string         `gorm:"type:text" json:"description,omitempty"`
+	DurationMins int            `gorm:"not null" json:"duration_mins"`
+	BasePrice    common.Money   `gorm:"embedded;embeddedPrefix:base_price_" json:"base_price"`
+	IsActive     bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Service
+func (Service) TableName() string {
+	return "services"
+}
+
+// Duration returns how long the service occupies a slot
+func (s *Service) Duration() time.Duration {
+	return time.Duration(s.DurationMins) * time.Minute
+}
@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
+)
+
+// maxDurationMins caps how long a single service may occupy a slot
+const maxDurationMins = 12 * 60
+
+// ServiceRepository defines the interface for service data access
+type ServiceRepository interface {
+	FindAll(ctx context.Context) ([]models.Service, error)
+	FindActive(ctx context.Context) ([]models.Service, error)
+	FindByID(ctx context.Context, id uint) (*models.Service, error)
+	Create(ctx context.Context, service *models.Service) error
+	Update(ctx context.Context, service *models.Service) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// ServiceInput holds the editable fields of a service
+type ServiceInput struct {
+	Name         string
+	Description  string
+	DurationMins int
+	BasePrice    common.Money
+	IsActive     *bool
+}
+
+// ServiceUseCase handles service catalog business logic
+type ServiceUseCase struct {
+	repo ServiceRepository
+}
+
+// NewServiceUseCase creates a new service use case
+func NewServiceUseCase(repo ServiceRepository) *ServiceUseCase {
+	return &ServiceUseCase{
+		repo: repo,
+	}
+}
+
+// ListServices returns all services, or only active ones if activeOnly is set
+func (uc *ServiceUseCase) ListServices(ctx context.Context, activeOnly bool) ([]models.Service, error) {
+	var (
+		services []models.Service
+		err      error
+	)
+	if activeOnly {
+		services, err = uc.repo.FindActive(ctx)
+	} else {
+		services, err = uc.repo.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return services, nil
+}
+
+// GetService returns a service by ID
+func (uc *ServiceUseCase) GetService(ctx context.Context, id uint) (*models.Service, error) {
+	service, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: service %d", common.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return service, nil
+}
+
+// GetBookableService returns an active service by ID, or ErrInvalidInput if it cannot be booked
+func (uc *ServiceUseCase) GetBookableService(ctx context.Context, id uint) (*models.Service, error) {
+	service, err := uc.GetService(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, fmt.Errorf("%w: service %d does not exist", common.ErrInvalidInput, id)
+		}
+		return nil, err
+	}
+
+	if !service.IsActive {
+		return nil, fmt.Errorf("%w: service %d is not offered", common.ErrInvalidInput, id)
+	}
+	return service, nil
+}
+
+// CreateService adds a service to the catalog
+func (uc *ServiceUseCase) CreateService(ctx context.Context, input ServiceInput) (*models.Service, error) {
+	if err := validateServiceInput(input); err != nil {
+		return nil, err
+	}
+
+	service := &models.Service{
+		Name:         strings.TrimSpace(input.Name),
+		Description:  input.Description,
+		DurationMins: input.DurationMins,
+		BasePrice:    input.BasePrice.WithDefaultCurrency(),
+		IsActive:     true,
+	}
+
+	if err := uc.repo.Create(ctx, service); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	// New services default to active; persist an explicit deactivation
+	if input.IsActive != nil && !*input.IsActive {
+		service.IsActive = false
+		if err := uc.repo.Update(ctx, service); err != nil {
+			return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+	}
+
+	return service, nil
+}
+
+// UpdateService replaces the editable fields of a service
+func (uc *ServiceUseCase) UpdateService(ctx context.Context, id uint, input ServiceInput) (*models.Service, error) {
+	if err := validateServiceInput(input); err != nil {
+		return nil, err
+	}
+
+	service, err := uc.GetService(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Name = strings.TrimSpace(input.Name)
+	service.Description = input.Description
+	service.DurationMins = input.DurationMins
+	service.BasePrice = input.BasePrice.WithDefaultCurrency()
+	if input.IsActive != nil {
+		service.IsActive = *input.IsActive
+	}
+
+	if err := uc.repo.Update(ctx, service); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return service, nil
+}
+
+// DeleteService removes a service from the catalog
+func (uc *ServiceUseCase) DeleteService(ctx context.Context, id uint) error {
+	if _, err := uc.GetService(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// validateServiceInput checks the editable fields of a service
+func validateServiceInput(input ServiceInput) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return fmt.Errorf("%w: name is required", common.ErrInvalidInput)
+	}
+	if input.DurationMins <= 0 || input.DurationMins > maxDurationMins {
+		return fmt.Errorf("%w: duration_mins must be between 1 and %d", common.ErrInvalidInput, maxDurationMins)
+	}
+	if input.BasePrice.Cents < 0 {
+		return fmt.Errorf("%w: base_price cannot be negative", common.ErrInvalidInput)
+	}
+	if err := input.BasePrice.WithDefaultCurrency().Validate(); err != nil {
+		return err
+	}
+	return nil
+}
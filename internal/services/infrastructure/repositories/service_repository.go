@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
+	"gorm.io/gorm"
+)
+
+// ServiceRepository implements the service repository interface
+type ServiceRepository struct {
+	db *gorm.DB
+}
+
+// NewServiceRepository creates a new service repository
+func NewServiceRepository(db *gorm.DB) *ServiceRepository {
+	return &ServiceRepository{
+		db: db,
+	}
+}
+
+// FindAll retrieves all services
+func (r *ServiceRepository) FindAll(ctx context.Context) ([]models.Service, error) {
+	var services []models.Service
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&services).Error; err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// FindActive retrieves all services currently offered
+func (r *ServiceRepository) FindActive(ctx context.Context) ([]models.Service, error) {
+	var services []models.Service
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("id ASC").
+		Find(&services).Error; err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// FindByID retrieves a service by ID
+func (r *ServiceRepository) FindByID(ctx context.Context, id uint) (*models.Service, error) {
+	var service models.Service
+	if err := r.db.WithContext(ctx).First(&service, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &service, nil
+}
+
+// Create creates a new service
+func (r *ServiceRepository) Create(ctx context.Context, service *models.Service) error {
+	return r.db.WithContext(ctx).Create(service).Error
+}
+
+// Update updates an existing service
+func (r *ServiceRepository) Update(ctx context.Context, service *models.Service) error {
+	return r.db.WithContext(ctx).Save(service).Error
+}
+
+// Delete soft deletes a service
+func (r *ServiceRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Service{}, id).Error
+}
@@ -1,8 +1,9 @@
 package http
 
 import (
-	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -27,33 +28,53 @@ func (h *AvailabilityHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/availability", h.GetAvailability)
 }
 
-// GetAvailability returns availability for the next 7 days
-// @Summary Get weekly availability
-// @Description Returns availability for all slots and hours for the next 7 days
+// GetAvailability returns availability for a date range, by default the next 7 days
+// @Summary Get availability
+// @Description Returns availability for all slots and hours from today to 7 days ahead.
+// @Description from/to select another range of business dates (at most 31 days, not in the past),
+// @Description slot_id restricts the grid to some slots and, with service_id,
+// @Description only start times where the whole service fits are available.
+// @Description view=matrix also lists, for each hour, the free slot IDs and remaining capacity.
 // @Tags availability
 // @Produce json
+// @Param from query string false "First date (YYYY-MM-DD), defaults to today"
+// @Param to query string false "Last date (YYYY-MM-DD), defaults to from + 7 days"
+// @Param slot_id query []int false "Slots to include, repeated or comma-separated" collectionFormat(csv)
+// @Param service_id query int false "Service to fit into the grid"
+// @Param view query string false "Response shape" Enums(summary, matrix)
 // @Success 200 {object} models.AvailabilityResponse
+// @Failure 400 {object} common.APIError "Invalid date range, slot, service or view"
 // @Failure 404 {object} common.APIError "No slots configured"
 // @Failure 500 {object} common.APIError "Internal server error"
 // @Router /api/v1/availability [get]
 func (h *AvailabilityHandler) GetAvailability(c *gin.Context) {
-	ctx := c.Request.Context()
+	query := usecases.AvailabilityQuery{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+		View: c.Query("view"),
+	}
 
-	availability, err := h.useCase.GetWeekAvailability(ctx)
+	slotIDs, err := common.ParseIDQuery(c, "slot_id")
 	if err != nil {
-		statusCode := common.MapErrorToHTTPStatus(err)
+		common.RespondError(c, err)
+		return
+	}
+	query.SlotIDs = slotIDs
 
-		// Extract underlying error message
-		var apiErr *common.APIError
-		if errors.As(err, &apiErr) {
-			c.JSON(statusCode, apiErr)
+	if raw := c.Query("service_id"); raw != "" {
+		serviceID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || serviceID == 0 {
+			common.RespondError(c, fmt.Errorf("%w: invalid service_id %q", common.ErrInvalidInput, raw))
 			return
 		}
+		query.ServiceID = uint(serviceID)
+	}
 
-		c.JSON(statusCode, common.NewAPIError(statusCode, err.Error(), ""))
+	availability, err := h.useCase.GetAvailability(c.Request.Context(), query)
+	if err != nil {
+		common.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, availability)
 }
-
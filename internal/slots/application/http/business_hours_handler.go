@@ -0,0 +1,122 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+)
+
+// BusinessHoursHandler handles HTTP requests for opening hours administration
+type BusinessHoursHandler struct {
+	useCase *usecases.BusinessHoursUseCase
+}
+
+// NewBusinessHoursHandler creates a new business hours handler
+func NewBusinessHoursHandler(useCase *usecases.BusinessHoursUseCase) *BusinessHoursHandler {
+	return &BusinessHoursHandler{
+		useCase: useCase,
+	}
+}
+
+// setBusinessHoursRequest is the request body for PUT /business-hours
+type setBusinessHoursRequest struct {
+	SlotID    *uint  `json:"slot_id"`
+	Weekday   *int   `json:"weekday" binding:"required"`
+	IsClosed  bool   `json:"is_closed"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+// RegisterRoutes registers business hours routes
+func (h *BusinessHoursHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	hours := rg.Group("/business-hours")
+	{
+		hours.GET("", h.List)
+		hours.PUT("", h.Set)
+		hours.DELETE("/:id", h.Delete)
+	}
+}
+
+// List returns all business-wide and per-slot opening hours
+// @Summary List business hours
+// @Tags business-hours
+// @Produce json
+// @Success 200 {array} models.BusinessHours
+// @Router /api/v1/business-hours [get]
+func (h *BusinessHoursHandler) List(c *gin.Context) {
+	hours, err := h.useCase.ListBusinessHours(c.Request.Context())
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": hours,
+	})
+}
+
+// Set creates or replaces the opening hours for a weekday, optionally for one slot
+// @Summary Set business hours
+// @Description Weekday is 0 (Sunday) to 6 (Saturday). Omit slot_id for business-wide hours.
+// @Tags business-hours
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.BusinessHours
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Router /api/v1/business-hours [put]
+func (h *BusinessHoursHandler) Set(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req setBusinessHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	hours, err := h.useCase.SetBusinessHours(c.Request.Context(), usecases.SetBusinessHoursInput{
+		SlotID:    req.SlotID,
+		Weekday:   time.Weekday(*req.Weekday),
+		IsClosed:  req.IsClosed,
+		OpenTime:  req.OpenTime,
+		CloseTime: req.CloseTime,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": hours,
+	})
+}
+
+// Delete removes opening hours so the weekday falls back to the default
+func (h *BusinessHoursHandler) Delete(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.useCase.DeleteBusinessHours(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
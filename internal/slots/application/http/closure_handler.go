@@ -0,0 +1,235 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/importers"
+)
+
+// maxImportSize caps the size of an uploaded holiday file
+const maxImportSize = 1 << 20
+
+// ClosureHandler handles HTTP requests for holidays and slot blackouts
+type ClosureHandler struct {
+	useCase  *usecases.ClosureUseCase
+	location *time.Location
+}
+
+// NewClosureHandler creates a new closure handler.
+// Dates without a time zone (query params, all-day holidays) are read in location.
+func NewClosureHandler(useCase *usecases.ClosureUseCase, location *time.Location) *ClosureHandler {
+	return &ClosureHandler{
+		useCase:  useCase,
+		location: location,
+	}
+}
+
+// createClosureRequest is the request body for POST /closures
+type createClosureRequest struct {
+	SlotID   *uint     `json:"slot_id"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+// RegisterRoutes registers closure routes
+func (h *ClosureHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	closures := rg.Group("/closures")
+	{
+		closures.GET("", h.List)
+		closures.POST("", h.Create)
+		closures.POST("/import", h.Import)
+		closures.DELETE("/:id", h.Delete)
+	}
+}
+
+// List returns closures in a date range
+// @Summary List closures
+// @Description Returns closures overlapping [from, to]. Defaults to the next year.
+// @Tags closures
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Success 200 {array} models.Closure
+// @Router /api/v1/closures [get]
+func (h *ClosureHandler) List(c *gin.Context) {
+	now := time.Now().In(h.location)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, h.location)
+	to := from.AddDate(1, 0, 0)
+
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, h.location)
+		if err != nil {
+			common.RespondError(c, fmt.Errorf("%w: invalid from %q", common.ErrInvalidInput, raw))
+			return
+		}
+		from = parsed
+	}
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, h.location)
+		if err != nil {
+			common.RespondError(c, fmt.Errorf("%w: invalid to %q", common.ErrInvalidInput, raw))
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	closures, err := h.useCase.ListClosures(c.Request.Context(), from, to)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": closures,
+	})
+}
+
+// Create closes the business or a single slot for a period
+// @Summary Create closure
+// @Description Omit slot_id to close the whole business
+// @Tags closures
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Closure
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Router /api/v1/closures [post]
+func (h *ClosureHandler) Create(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	var req createClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	closure, err := h.useCase.CreateClosure(c.Request.Context(), usecases.CreateClosureInput{
+		SlotID:   req.SlotID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": closure,
+	})
+}
+
+// Import bulk-loads closures from an ICS or CSV holiday file
+// @Summary Import closures
+// @Description Accepts a multipart "file" field or a raw body. The format is taken from ?format=ics|csv,
+// @Description the file extension or the content type. CSV files need a start,end,reason[,slot_id] header.
+// @Tags closures
+// @Accept mpfd,text/calendar,text/csv
+// @Produce json
+// @Success 201 {object} usecases.ImportResult
+// @Failure 400 {object} common.APIError "Invalid file"
+// @Router /api/v1/closures/import [post]
+func (h *ClosureHandler) Import(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	body, name, contentType, err := readImportFile(c)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	defer body.Close()
+
+	var closures []models.Closure
+	switch importFormat(c.Query("format"), name, contentType) {
+	case "ics":
+		closures, err = importers.ParseICS(io.LimitReader(body, maxImportSize), h.location)
+	case "csv":
+		closures, err = importers.ParseCSV(io.LimitReader(body, maxImportSize), h.location)
+	default:
+		err = fmt.Errorf("unknown file format, use ?format=ics or ?format=csv")
+	}
+	if err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	result, err := h.useCase.ImportClosures(c.Request.Context(), closures)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": result,
+	})
+}
+
+// Delete reopens a closed period
+func (h *ClosureHandler) Delete(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if err := h.useCase.DeleteClosure(c.Request.Context(), id); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// readImportFile returns the uploaded file from a multipart "file" field, or the raw request body
+func readImportFile(c *gin.Context) (io.ReadCloser, string, string, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", "", fmt.Errorf("%w: missing file field: %v", common.ErrInvalidInput, err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", "", fmt.Errorf("%w: %v", common.ErrInvalidInput, err)
+		}
+		return file, header.Filename, header.Header.Get("Content-Type"), nil
+	}
+
+	return c.Request.Body, "", c.ContentType(), nil
+}
+
+// importFormat picks the file format from an explicit query param, the file name or the content type
+func importFormat(explicit, filename, contentType string) string {
+	switch {
+	case explicit != "":
+		return strings.ToLower(explicit)
+	case strings.EqualFold(filepath.Ext(filename), ".ics"), strings.Contains(contentType, "calendar"):
+		return "ics"
+	case strings.EqualFold(filepath.Ext(filename), ".csv"), strings.Contains(contentType, "csv"):
+		return "csv"
+	default:
+		return ""
+	}
+}
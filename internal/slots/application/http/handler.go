@@ -4,6 +4,10 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 )
 
 // SlotHandler handles HTTP requests for slots
@@ -59,6 +63,11 @@ func (h *SlotHandler) GetByID(c *gin.Context) {
 
 // Create creates a new slot
 func (h *SlotHandler) Create(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	// TODO: Implement create slot
 	c.JSON(http.StatusCreated, gin.H{
 		"data":    nil,
@@ -68,6 +77,11 @@ func (h *SlotHandler) Create(c *gin.Context) {
 
 // Update updates an existing slot
 func (h *SlotHandler) Update(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	// TODO: Implement update slot
 	c.JSON(http.StatusOK, gin.H{
 		"data":    nil,
@@ -77,7 +91,11 @@ func (h *SlotHandler) Update(c *gin.Context) {
 
 // Delete deletes a slot
 func (h *SlotHandler) Delete(c *gin.Context) {
+	if _, err := policies.RequireRole(c.Request.Context(), clientModels.RoleAdmin); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
 	// TODO: Implement delete slot
 	c.JSON(http.StatusNoContent, nil)
 }
-
@@ -4,10 +4,19 @@ package models
 // Map key is date string in format "YYYY-MM-DD"
 type AvailabilityResponse map[string]DayAvailability
 
+// Availability response views
+const (
+	// AvailabilityViewSummary reports per-slot and per-hour availability only
+	AvailabilityViewSummary = "summary"
+	// AvailabilityViewMatrix also lists the free slots of every hour
+	AvailabilityViewMatrix = "matrix"
+)
+
 // DayAvailability represents availability for a single day
 type DayAvailability struct {
-	Slots []SlotAvailability `json:"slots"`
-	Hours []HourAvailability `json:"hours"`
+	Slots  []SlotAvailability `json:"slots"`
+	Hours  []HourAvailability `json:"hours"`
+	Matrix []HourSlots        `json:"matrix,omitempty"`
 }
 
 // SlotAvailability represents a physical space availability
@@ -23,3 +32,11 @@ type HourAvailability struct {
 	IsAvailable bool   `json:"is_available"`
 }
 
+// HourSlots lists which slots can be booked at a start time (matrix view only)
+type HourSlots struct {
+	Value       string `json:"value"`
+	FreeSlotIDs []uint `json:"free_slot_ids"`
+	// Remaining is how many slots are free; Capacity how many are open at this hour
+	Remaining int `json:"remaining"`
+	Capacity  int `json:"capacity"`
+}
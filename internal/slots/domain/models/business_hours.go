@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// BusinessHours represents the opening hours for a weekday.
+// Rows with a nil SlotID apply to the whole business; rows with a SlotID override them for that slot.
+type BusinessHours struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	SlotID    *uint        `gorm:"index" json:"slot_id,omitempty"`
+	Weekday   time.Weekday `gorm:"not null;index" json:"weekday"`
+	IsClosed  bool         `gorm:"not null;default:false" json:"is_closed"`
+	OpenTime  string       `gorm:"type:varchar(5)" json:"open_time,omitempty"`
+	CloseTime string       `gorm:"type:varchar(5)" json:"close_time,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for BusinessHours
+func (BusinessHours) TableName() string {
+	return "business_hours"
+}
+
+// OpenMinutes returns the opening time as minutes since midnight
+func (b *BusinessHours) OpenMinutes() (int, error) {
+	return ParseClock(b.OpenTime)
+}
+
+// CloseMinutes returns the closing time as minutes since midnight
+func (b *BusinessHours) CloseMinutes() (int, error) {
+	return ParseClock(b.CloseTime)
+}
+
+// ParseClock parses an "HH:MM" wall clock time into minutes since midnight.
+// "24:00" is accepted as end of day.
+func ParseClock(value string) (int, error) {
+	invalid := fmt.Errorf("invalid time %q, expected HH:MM", value)
+	if len(value) != 5 || value[2] != ':' {
+		return 0, invalid
+	}
+
+	digits := [4]byte{value[0], value[1], value[3], value[4]}
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return 0, invalid
+		}
+	}
+
+	hour := int(digits[0]-'0')*10 + int(digits[1]-'0')
+	minute := int(digits[2]-'0')*10 + int(digits[3]-'0')
+	if minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, invalid
+	}
+	return hour*60 + minute, nil
+}
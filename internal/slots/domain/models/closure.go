@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Closure represents a period when the business or a single slot cannot be booked
+// (e.g., a national holiday or a bay taken out of service for maintenance).
+// Rows with a nil SlotID close the whole business.
+type Closure struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	SlotID    *uint          `gorm:"index" json:"slot_id,omitempty"`
+	StartsAt  time.Time      `gorm:"not null;index" json:"starts_at"`
+	EndsAt    time.Time      `gorm:"not null;index" json:"ends_at"`
+	Reason    string         `gorm:"type:varchar(255)" json:"reason"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Closure
+func (Closure) TableName() string {
+	return "closures"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (c *Closure) BeforeSave(tx *gorm.DB) error {
+	c.StartsAt = c.StartsAt.UTC()
+	c.EndsAt = c.EndsAt.UTC()
+	return nil
+}
+
+// AppliesTo returns true if the closure blocks the given slot
+func (c *Closure) AppliesTo(slotID uint) bool {
+	return c.SlotID == nil || *c.SlotID == slotID
+}
+
+// Overlaps returns true if the closure covers any part of [start, end)
+func (c *Closure) Overlaps(start, end time.Time) bool {
+	return c.StartsAt.Before(end) && c.EndsAt.After(start)
+}
@@ -2,51 +2,129 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
 	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
 )
 
 const (
-	// Business hours configuration
-	startHour = 8
-	endHour   = 22
+	// Default business hours, used for weekdays without stored BusinessHours
+	defaultOpenHour  = 8
+	defaultCloseHour = 22
+
+	// Booking grid configuration
 	stepMins  = 30
 	daysAhead = 7
+
+	// maxRangeDays is the longest date range a single availability query may cover
+	maxRangeDays = 31
+
+	dateLayout = "2006-01-02"
 )
 
 // AvailabilityRepository defines the interface for availability data access
 type AvailabilityRepository interface {
 	// FindAllSlots returns all active physical slots
 	FindAllSlots(ctx context.Context) ([]models.Slot, error)
-	// FindReservationsByDateRange returns active reservations within a date range
-	FindReservationsByDateRange(ctx context.Context, start, end time.Time) ([]reservationModels.Reservation, error)
+	// FindReservationsByDateRange returns active reservations within a date range, leaving out holds expired at now
+	FindReservationsByDateRange(ctx context.Context, start, end, now time.Time) ([]reservationModels.Reservation, error)
+	// FindServiceByID returns a service by ID, or common.ErrNotFound
+	FindServiceByID(ctx context.Context, id uint) (*serviceModels.Service, error)
+	// FindBusinessHours returns all business-wide and per-slot opening hours
+	FindBusinessHours(ctx context.Context) ([]models.BusinessHours, error)
+	// FindClosuresByDateRange returns business-wide and per-slot closures overlapping [start, end)
+	FindClosuresByDateRange(ctx context.Context, start, end time.Time) ([]models.Closure, error)
 }
 
 // AvailabilityUseCase handles availability business logic
 type AvailabilityUseCase struct {
-	repo AvailabilityRepository
+	repo     AvailabilityRepository
+	location *time.Location
+	leadTime time.Duration
+	now      common.Clock
 }
 
-// NewAvailabilityUseCase creates a new availability use case
-func NewAvailabilityUseCase(repo AvailabilityRepository) *AvailabilityUseCase {
+// NewAvailabilityUseCase creates a new availability use case.
+// Dates, opening hours and grid keys are computed in the business location.
+// Start times earlier than now plus leadTime are never offered nor accepted.
+func NewAvailabilityUseCase(repo AvailabilityRepository, location *time.Location, leadTime time.Duration, now common.Clock) *AvailabilityUseCase {
 	return &AvailabilityUseCase{
-		repo: repo,
+		repo:     repo,
+		location: location,
+		leadTime: leadTime,
+		now:      now,
 	}
 }
 
-// GetWeekAvailability returns availability for the next 7 days
+// Location returns the business location used for dates and opening hours
+func (uc *AvailabilityUseCase) Location() *time.Location {
+	return uc.location
+}
+
+// AvailabilityQuery narrows the availability grid.
+// Zero values fall back to today, today + daysAhead, every slot and a single grid step.
+type AvailabilityQuery struct {
+	// From and To are inclusive business dates in "YYYY-MM-DD" format
+	From string
+	To   string
+	// SlotIDs restricts the grid to these slots
+	SlotIDs []uint
+	// ServiceID only offers start times where the whole service fits
+	ServiceID uint
+	// View is models.AvailabilityViewSummary (default) or models.AvailabilityViewMatrix
+	View string
+}
+
+// GetWeekAvailability returns availability for the next 7 days for a single grid step
 func (uc *AvailabilityUseCase) GetWeekAvailability(ctx context.Context) (models.AvailabilityResponse, error) {
-	// Get today's date at midnight (local time)
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return uc.GetAvailability(ctx, AvailabilityQuery{})
+}
+
+// GetWeekAvailabilityForService returns availability for the next 7 days, only offering
+// start times where the service's whole duration is free and ends before closing time
+func (uc *AvailabilityUseCase) GetWeekAvailabilityForService(ctx context.Context, serviceID uint) (models.AvailabilityResponse, error) {
+	return uc.GetAvailability(ctx, AvailabilityQuery{ServiceID: serviceID})
+}
 
-	// Calculate date range: today -> today + 7 days
-	startDate := today
-	endDate := today.AddDate(0, 0, daysAhead).Add(24 * time.Hour) // End of day 7
+// GetAvailability returns availability for a date range, optionally restricted to some
+// slots and to start times that fit a service.
+// The range may not start in the past nor span more than maxRangeDays days.
+func (uc *AvailabilityUseCase) GetAvailability(ctx context.Context, query AvailabilityQuery) (models.AvailabilityResponse, error) {
+	from, to, err := uc.resolveDateRange(query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := false
+	switch query.View {
+	case "", models.AvailabilityViewSummary:
+	case models.AvailabilityViewMatrix:
+		matrix = true
+	default:
+		return nil, fmt.Errorf("%w: view must be %q or %q", common.ErrInvalidInput, models.AvailabilityViewSummary, models.AvailabilityViewMatrix)
+	}
+
+	duration := stepMins * time.Minute
+	if query.ServiceID != 0 {
+		service, err := uc.repo.FindServiceByID(ctx, query.ServiceID)
+		if err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return nil, fmt.Errorf("%w: service %d does not exist", common.ErrInvalidInput, query.ServiceID)
+			}
+			return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+
+		if !service.IsActive {
+			return nil, fmt.Errorf("%w: service %d is not offered", common.ErrInvalidInput, query.ServiceID)
+		}
+		duration = service.Duration()
+	}
 
 	// Fetch all slots
 	slots, err := uc.repo.FindAllSlots(ctx)
@@ -59,36 +137,152 @@ func (uc *AvailabilityUseCase) GetWeekAvailability(ctx context.Context) (models.
 		return nil, fmt.Errorf("%w: no slots configured", common.ErrNotFound)
 	}
 
-	// Fetch reservations in range
-	reservations, err := uc.repo.FindReservationsByDateRange(ctx, startDate, endDate)
+	slots, err = filterSlots(slots, query.SlotIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.buildAvailability(ctx, slots, from, to, duration, matrix)
+}
+
+// resolveDateRange parses the inclusive from/to business dates, applying defaults and limits
+func (uc *AvailabilityUseCase) resolveDateRange(rawFrom, rawTo string) (time.Time, time.Time, error) {
+	// Get today's date at midnight in the business location
+	now := uc.now().In(uc.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, uc.location)
+
+	from := today
+	if rawFrom != "" {
+		parsed, err := time.ParseInLocation(dateLayout, rawFrom, uc.location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be a YYYY-MM-DD date", common.ErrInvalidInput)
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, daysAhead)
+	if rawTo != "" {
+		parsed, err := time.ParseInLocation(dateLayout, rawTo, uc.location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be a YYYY-MM-DD date", common.ErrInvalidInput)
+		}
+		to = parsed
+	}
+
+	if from.Before(today) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from cannot be before %s", common.ErrInvalidInput, today.Format(dateLayout))
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to cannot be before from", common.ErrInvalidInput)
+	}
+	if to.After(from.AddDate(0, 0, maxRangeDays-1)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: date range cannot span more than %d days", common.ErrInvalidInput, maxRangeDays)
+	}
+
+	return from, to, nil
+}
+
+// filterSlots keeps only the requested slots, rejecting IDs that do not exist
+func filterSlots(slots []models.Slot, ids []uint) ([]models.Slot, error) {
+	if len(ids) == 0 {
+		return slots, nil
+	}
+
+	byID := make(map[uint]models.Slot, len(slots))
+	for _, slot := range slots {
+		byID[slot.ID] = slot
+	}
+
+	result := make([]models.Slot, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		slot, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: slot %d does not exist", common.ErrInvalidInput, id)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, slot)
+	}
+	return result, nil
+}
+
+// buildAvailability builds the grid for the given slots from the first to the last date,
+// inclusive, for bookings lasting duration. With matrix, each day also lists the free slots per hour.
+func (uc *AvailabilityUseCase) buildAvailability(ctx context.Context, slots []models.Slot, firstDate, lastDate time.Time, duration time.Duration, matrix bool) (models.AvailabilityResponse, error) {
+	// Calculate date range: midnight of the first date -> midnight after the last date
+	startDate := firstDate
+	endDate := lastDate.AddDate(0, 0, 1)
+
+	// Fetch opening hours
+	schedule, err := uc.loadSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch reservations in range, judging holds against the same now as the rest of the grid
+	now := uc.now()
+	reservations, err := uc.repo.FindReservationsByDateRange(ctx, startDate, endDate, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	// Fetch holidays and maintenance blackouts in range
+	closures, err := uc.repo.FindClosuresByDateRange(ctx, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
 	}
 
 	// Build reservation lookup: map[date][slotID][hour] = true
-	reservedMap := buildReservationMap(reservations)
+	reservedMap := buildReservationMap(reservations, now, uc.location)
+
+	// Closed periods are unavailable just like reserved ones
+	markClosures(reservedMap, closures, slots, startDate, endDate, uc.location)
+
+	// So are start times in the past or inside the booking lead time
+	if cutoff := now.Add(uc.leadTime); cutoff.After(startDate) {
+		for _, slot := range slots {
+			markCells(reservedMap, slot.ID, startDate, cutoff, uc.location)
+		}
+	}
+
+	// Number of consecutive grid cells a booking of this duration occupies
+	cells := cellsFor(duration)
 
 	// Build response
 	response := make(models.AvailabilityResponse)
 
-	for d := 0; d <= daysAhead; d++ {
-		currentDate := today.AddDate(0, 0, d)
-		dateKey := currentDate.Format("2006-01-02")
+	for currentDate := startDate; currentDate.Before(endDate); currentDate = currentDate.AddDate(0, 0, 1) {
+		dateKey := currentDate.Format(dateLayout)
 
 		dayAvailability := models.DayAvailability{
 			Slots: make([]models.SlotAvailability, 0, len(slots)),
 			Hours: make([]models.HourAvailability, 0),
 		}
 
-		// Generate hours for the day
-		hours := generateHours()
+		// Generate each slot's hours for the day; a slot override may open earlier or later
+		slotHours := make(map[uint][]string, len(slots))
+		slotIndex := make(map[uint]map[string]int, len(slots))
+		openHours := make(map[string]bool)
+
+		for _, slot := range slots {
+			hours := generateHours(schedule.forSlot(slot.ID, currentDate.Weekday()))
+			slotHours[slot.ID] = hours
+			slotIndex[slot.ID] = make(map[string]int, len(hours))
+			for i, hour := range hours {
+				slotIndex[slot.ID][hour] = i
+				openHours[hour] = true
+			}
+		}
 
 		// Check each slot's availability for this day
 		for _, slot := range slots {
-			// A slot is available for the day if at least one hour is free
+			// A slot is available for the day if at least one start time fits the duration
 			slotHasAvailability := false
-			for _, hour := range hours {
-				if !isReserved(reservedMap, dateKey, slot.ID, hour) {
+			for i := range slotHours[slot.ID] {
+				if fitsAt(reservedMap, dateKey, slot.ID, slotHours[slot.ID], i, cells) {
 					slotHasAvailability = true
 					break
 				}
@@ -101,20 +295,34 @@ func (uc *AvailabilityUseCase) GetWeekAvailability(ctx context.Context) (models.
 			})
 		}
 
-		// Check each hour's availability (available if at least one slot is free)
-		for _, hour := range hours {
-			hourHasAvailability := false
+		// Check each hour's availability (available if at least one open slot fits the duration)
+		for _, hour := range sortedHours(openHours) {
+			freeSlotIDs := make([]uint, 0, len(slots))
+			capacity := 0
 			for _, slot := range slots {
-				if slot.IsAvailable && !isReserved(reservedMap, dateKey, slot.ID, hour) {
-					hourHasAvailability = true
-					break
+				i, open := slotIndex[slot.ID][hour]
+				if !open || !slot.IsAvailable {
+					continue
+				}
+				capacity++
+				if fitsAt(reservedMap, dateKey, slot.ID, slotHours[slot.ID], i, cells) {
+					freeSlotIDs = append(freeSlotIDs, slot.ID)
 				}
 			}
 
 			dayAvailability.Hours = append(dayAvailability.Hours, models.HourAvailability{
 				Value:       hour,
-				IsAvailable: hourHasAvailability,
+				IsAvailable: len(freeSlotIDs) > 0,
 			})
+
+			if matrix {
+				dayAvailability.Matrix = append(dayAvailability.Matrix, models.HourSlots{
+					Value:       hour,
+					FreeSlotIDs: freeSlotIDs,
+					Remaining:   len(freeSlotIDs),
+					Capacity:    capacity,
+				})
+			}
 		}
 
 		response[dateKey] = dayAvailability
@@ -123,43 +331,197 @@ func (uc *AvailabilityUseCase) GetWeekAvailability(ctx context.Context) (models.
 	return response, nil
 }
 
-// generateHours generates time slots from startHour to endHour with stepMins intervals
-func generateHours() []string {
-	hours := make([]string, 0)
+// ValidateStartTime checks that a slot can be booked at the given start time.
+// The start time must fall inside the slot's opening hours for that weekday and on the
+// stepMins grid used by generateHours. A booking lasting duration must also end by closing time.
+// It must not start before now plus the booking lead time.
+// Weekday and clock time are read in the business location, whatever offset startTime carries.
+func (uc *AvailabilityUseCase) ValidateStartTime(ctx context.Context, slotID uint, startTime time.Time, duration time.Duration) error {
+	if startTime.IsZero() {
+		return fmt.Errorf("%w: start_time is required", common.ErrInvalidInput)
+	}
+	startTime = startTime.In(uc.location)
+
+	if startTime.Minute()%stepMins != 0 || startTime.Second() != 0 || startTime.Nanosecond() != 0 {
+		return fmt.Errorf("%w: start_time must be aligned to %d minute intervals", common.ErrInvalidInput, stepMins)
+	}
+
+	if earliest := uc.now().Add(uc.leadTime); startTime.Before(earliest) {
+		return fmt.Errorf("%w: start_time must be at least %d minutes from now", common.ErrInvalidInput, int(uc.leadTime.Minutes()))
+	}
+
+	slots, err := uc.repo.FindAllSlots(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	bookable := false
+	for _, slot := range slots {
+		if slot.ID == slotID {
+			bookable = true
+			break
+		}
+	}
+	if !bookable {
+		return fmt.Errorf("%w: slot %d does not exist or is not bookable", common.ErrSlotNotAvailable, slotID)
+	}
+
+	schedule, err := uc.loadSchedule(ctx)
+	if err != nil {
+		return err
+	}
+
+	hours := schedule.forSlot(slotID, startTime.Weekday())
+	if hours.closed {
+		return fmt.Errorf("%w: slot %d is closed on %s", common.ErrInvalidInput, slotID, startTime.Weekday())
+	}
+
+	start := startTime.Hour()*60 + startTime.Minute()
+	if start < hours.open || start >= hours.close {
+		return fmt.Errorf("%w: start_time must be between %s and %s", common.ErrInvalidInput, formatClock(hours.open), formatClock(hours.close))
+	}
+
+	if start+int(duration.Minutes()) > hours.close {
+		return fmt.Errorf("%w: a %d minute booking starting at %s ends after closing time %s",
+			common.ErrInvalidInput, int(duration.Minutes()), startTime.Format("15:04"), formatClock(hours.close))
+	}
 
-	for h := startHour; h < endHour; h++ {
-		for m := 0; m < 60; m += stepMins {
-			hours = append(hours, fmt.Sprintf("%02d:%02d", h, m))
+	endTime := startTime.Add(duration)
+	closures, err := uc.repo.FindClosuresByDateRange(ctx, startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	for i := range closures {
+		if closures[i].AppliesTo(slotID) && closures[i].Overlaps(startTime, endTime) {
+			return fmt.Errorf("%w: slot %d is closed (%s)", common.ErrSlotNotAvailable, slotID, closures[i].Reason)
 		}
 	}
 
+	return nil
+}
+
+// loadSchedule fetches and resolves the stored opening hours
+func (uc *AvailabilityUseCase) loadSchedule(ctx context.Context) (weeklySchedule, error) {
+	rows, err := uc.repo.FindBusinessHours(ctx)
+	if err != nil {
+		return weeklySchedule{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	schedule, err := newWeeklySchedule(rows)
+	if err != nil {
+		return weeklySchedule{}, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return schedule, nil
+}
+
+// generateHours generates grid start times from opening to closing time with stepMins intervals
+func generateHours(hours openingHours) []string {
+	result := make([]string, 0)
+	if hours.closed {
+		return result
+	}
+
+	for m := hours.open; m < hours.close; m += stepMins {
+		result = append(result, formatClock(m))
+	}
+
+	return result
+}
+
+// sortedHours returns the keys of an "HH:MM" set in chronological order
+func sortedHours(set map[string]bool) []string {
+	hours := make([]string, 0, len(set))
+	for hour := range set {
+		hours = append(hours, hour)
+	}
+	sort.Strings(hours)
 	return hours
 }
 
 // buildReservationMap creates a lookup map: map[date][slotID][hour] = true
-func buildReservationMap(reservations []reservationModels.Reservation) map[string]map[uint]map[string]bool {
+// Every grid cell a reservation overlaps is marked, not only its start time.
+// Keys are formatted in loc, regardless of the location the database returned.
+func buildReservationMap(reservations []reservationModels.Reservation, now time.Time, loc *time.Location) map[string]map[uint]map[string]bool {
 	result := make(map[string]map[uint]map[string]bool)
 
 	for _, r := range reservations {
-		// Only consider active reservations
-		if !r.IsActive() {
+		// Only consider active reservations and unexpired holds
+		if !r.Occupies(now) {
 			continue
 		}
 
-		dateKey := r.StartTime.Format("2006-01-02")
-		hourKey := r.StartTime.Format("15:04")
+		markCells(result, r.SlotID, r.StartTime, r.EffectiveEndTime(), loc)
+	}
 
-		if result[dateKey] == nil {
-			result[dateKey] = make(map[uint]map[string]bool)
+	return result
+}
+
+// markClosures marks every grid cell covered by a closure, clamped to [from, to),
+// for the closed slot or for every slot when the whole business is closed
+func markClosures(reservedMap map[string]map[uint]map[string]bool, closures []models.Closure, slots []models.Slot, from, to time.Time, loc *time.Location) {
+	for i := range closures {
+		start, end := closures[i].StartsAt, closures[i].EndsAt
+		if start.Before(from) {
+			start = from
 		}
-		if result[dateKey][r.SlotID] == nil {
-			result[dateKey][r.SlotID] = make(map[string]bool)
+		if end.After(to) {
+			end = to
 		}
 
-		result[dateKey][r.SlotID][hourKey] = true
+		for _, slot := range slots {
+			if closures[i].AppliesTo(slot.ID) {
+				markCells(reservedMap, slot.ID, start, end, loc)
+			}
+		}
 	}
+}
 
-	return result
+// markCells marks every grid cell of a slot that overlaps [start, end), keyed in loc
+func markCells(reservedMap map[string]map[uint]map[string]bool, slotID uint, start, end time.Time, loc *time.Location) {
+	step := stepMins * time.Minute
+
+	// Align to the start of the grid cell containing start
+	start = start.In(loc)
+	cell := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute()-start.Minute()%stepMins, 0, 0, loc)
+
+	for ; cell.Before(end); cell = cell.Add(step) {
+		dateKey := cell.Format("2006-01-02")
+		hourKey := cell.Format("15:04")
+
+		if reservedMap[dateKey] == nil {
+			reservedMap[dateKey] = make(map[uint]map[string]bool)
+		}
+		if reservedMap[dateKey][slotID] == nil {
+			reservedMap[dateKey][slotID] = make(map[string]bool)
+		}
+
+		reservedMap[dateKey][slotID][hourKey] = true
+	}
+}
+
+// cellsFor returns how many grid cells a booking of the given duration occupies
+func cellsFor(duration time.Duration) int {
+	step := stepMins * time.Minute
+	cells := int((duration + step - 1) / step)
+	if cells < 1 {
+		return 1
+	}
+	return cells
+}
+
+// fitsAt checks if a slot is free for the given number of cells starting at hours[start],
+// without running past the last hour of the day
+func fitsAt(reservedMap map[string]map[uint]map[string]bool, date string, slotID uint, hours []string, start, cells int) bool {
+	if start+cells > len(hours) {
+		return false
+	}
+	for _, hour := range hours[start : start+cells] {
+		if isReserved(reservedMap, date, slotID, hour) {
+			return false
+		}
+	}
+	return true
 }
 
 // isReserved checks if a slot is reserved at a specific date and hour
@@ -172,4 +534,3 @@ func isReserved(reservedMap map[string]map[uint]map[string]bool, date string, sl
 	}
 	return reservedMap[date][slotID][hour]
 }
-
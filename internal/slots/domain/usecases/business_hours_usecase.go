@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+)
+
+// BusinessHoursRepository defines the interface for opening hours data access
+type BusinessHoursRepository interface {
+	FindAll(ctx context.Context) ([]models.BusinessHours, error)
+	FindByID(ctx context.Context, id uint) (*models.BusinessHours, error)
+	// Upsert creates or replaces the opening hours for a weekday and optional slot
+	Upsert(ctx context.Context, hours *models.BusinessHours) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// SlotRepository looks up the slots opening hours can be set for
+type SlotRepository interface {
+	// FindByID returns common.ErrNotFound for unknown or deleted slots
+	FindByID(ctx context.Context, id uint) (*models.Slot, error)
+}
+
+// SetBusinessHoursInput holds the opening hours for a weekday, optionally for a single slot
+type SetBusinessHoursInput struct {
+	SlotID    *uint
+	Weekday   time.Weekday
+	IsClosed  bool
+	OpenTime  string
+	CloseTime string
+}
+
+// BusinessHoursUseCase handles opening hours administration
+type BusinessHoursUseCase struct {
+	repo  BusinessHoursRepository
+	slots SlotRepository
+}
+
+// NewBusinessHoursUseCase creates a new business hours use case
+func NewBusinessHoursUseCase(repo BusinessHoursRepository, slots SlotRepository) *BusinessHoursUseCase {
+	return &BusinessHoursUseCase{
+		repo:  repo,
+		slots: slots,
+	}
+}
+
+// ListBusinessHours returns all business-wide and per-slot opening hours
+func (uc *BusinessHoursUseCase) ListBusinessHours(ctx context.Context) ([]models.BusinessHours, error) {
+	hours, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return hours, nil
+}
+
+// SetBusinessHours creates or replaces the opening hours for a weekday
+func (uc *BusinessHoursUseCase) SetBusinessHours(ctx context.Context, input SetBusinessHoursInput) (*models.BusinessHours, error) {
+	if input.Weekday < time.Sunday || input.Weekday > time.Saturday {
+		return nil, fmt.Errorf("%w: weekday must be between 0 (Sunday) and 6 (Saturday)", common.ErrInvalidInput)
+	}
+
+	if input.SlotID != nil {
+		if _, err := uc.slots.FindByID(ctx, *input.SlotID); err != nil {
+			if errors.Is(err, common.ErrNotFound) {
+				return nil, fmt.Errorf("%w: slot %d does not exist", common.ErrInvalidInput, *input.SlotID)
+			}
+			return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+		}
+	}
+
+	hours := &models.BusinessHours{
+		SlotID:   input.SlotID,
+		Weekday:  input.Weekday,
+		IsClosed: input.IsClosed,
+	}
+
+	if !input.IsClosed {
+		open, err := models.ParseClock(input.OpenTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: open_time: %v", common.ErrInvalidInput, err)
+		}
+		close, err := models.ParseClock(input.CloseTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: close_time: %v", common.ErrInvalidInput, err)
+		}
+		if open >= close {
+			return nil, fmt.Errorf("%w: open_time must be before close_time", common.ErrInvalidInput)
+		}
+		if open%stepMins != 0 || close%stepMins != 0 {
+			return nil, fmt.Errorf("%w: opening hours must be aligned to %d minute intervals", common.ErrInvalidInput, stepMins)
+		}
+
+		hours.OpenTime = input.OpenTime
+		hours.CloseTime = input.CloseTime
+	}
+
+	if err := uc.repo.Upsert(ctx, hours); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return hours, nil
+}
+
+// DeleteBusinessHours removes opening hours; the weekday falls back to the
+// business-wide row (for slot overrides) or the default hours
+func (uc *BusinessHoursUseCase) DeleteBusinessHours(ctx context.Context, id uint) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("%w: business hours %d", common.ErrNotFound, id)
+		}
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
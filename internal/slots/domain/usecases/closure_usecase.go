@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+)
+
+// ClosureRepository defines the interface for closure data access
+type ClosureRepository interface {
+	FindByDateRange(ctx context.Context, start, end time.Time) ([]models.Closure, error)
+	FindByID(ctx context.Context, id uint) (*models.Closure, error)
+	Create(ctx context.Context, closure *models.Closure) error
+	// CreateMany creates closures in one transaction, skipping exact duplicates. Returns how many were created.
+	CreateMany(ctx context.Context, closures []models.Closure) (int, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// CreateClosureInput holds the data required to close the business or a slot
+type CreateClosureInput struct {
+	SlotID   *uint
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+// ImportResult reports the outcome of a bulk closure import
+type ImportResult struct {
+	Parsed  int `json:"parsed"`
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+// ClosureUseCase handles holiday and blackout administration
+type ClosureUseCase struct {
+	repo ClosureRepository
+}
+
+// NewClosureUseCase creates a new closure use case
+func NewClosureUseCase(repo ClosureRepository) *ClosureUseCase {
+	return &ClosureUseCase{
+		repo: repo,
+	}
+}
+
+// ListClosures returns closures overlapping [from, to)
+func (uc *ClosureUseCase) ListClosures(ctx context.Context, from, to time.Time) ([]models.Closure, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", common.ErrInvalidInput)
+	}
+
+	closures, err := uc.repo.FindByDateRange(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return closures, nil
+}
+
+// CreateClosure closes the business, or a single slot, for a period
+func (uc *ClosureUseCase) CreateClosure(ctx context.Context, input CreateClosureInput) (*models.Closure, error) {
+	closure := models.Closure{
+		SlotID:   input.SlotID,
+		StartsAt: input.StartsAt,
+		EndsAt:   input.EndsAt,
+		Reason:   strings.TrimSpace(input.Reason),
+	}
+	if err := validateClosure(&closure); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, &closure); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return &closure, nil
+}
+
+// ImportClosures stores closures parsed from a holiday file, skipping ones already stored
+func (uc *ClosureUseCase) ImportClosures(ctx context.Context, closures []models.Closure) (*ImportResult, error) {
+	for i := range closures {
+		closures[i].Reason = strings.TrimSpace(closures[i].Reason)
+		if err := validateClosure(&closures[i]); err != nil {
+			return nil, fmt.Errorf("closure %d: %w", i+1, err)
+		}
+	}
+
+	created, err := uc.repo.CreateMany(ctx, closures)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	return &ImportResult{
+		Parsed:  len(closures),
+		Created: created,
+		Skipped: len(closures) - created,
+	}, nil
+}
+
+// DeleteClosure reopens a closed period
+func (uc *ClosureUseCase) DeleteClosure(ctx context.Context, id uint) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("%w: closure %d", common.ErrNotFound, id)
+		}
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// validateClosure checks a closure's period and reason
+func validateClosure(closure *models.Closure) error {
+	if closure.StartsAt.IsZero() || closure.EndsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at and ends_at are required", common.ErrInvalidInput)
+	}
+	if !closure.EndsAt.After(closure.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", common.ErrInvalidInput)
+	}
+	if len(closure.Reason) > 255 {
+		return fmt.Errorf("%w: reason must be at most 255 characters", common.ErrInvalidInput)
+	}
+	return nil
+}
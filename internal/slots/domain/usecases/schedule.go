@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+)
+
+// openingHours is the resolved opening interval of a slot on one weekday, in minutes since midnight
+type openingHours struct {
+	open   int
+	close  int
+	closed bool
+}
+
+// defaultOpeningHours applies to weekdays without a configured BusinessHours row
+var defaultOpeningHours = openingHours{
+	open:  defaultOpenHour * 60,
+	close: defaultCloseHour * 60,
+}
+
+// weeklySchedule resolves opening hours from business-wide rows and per-slot overrides
+type weeklySchedule struct {
+	business map[time.Weekday]openingHours
+	slots    map[uint]map[time.Weekday]openingHours
+}
+
+// newWeeklySchedule builds a schedule from stored business hours
+func newWeeklySchedule(rows []models.BusinessHours) (weeklySchedule, error) {
+	schedule := weeklySchedule{
+		business: make(map[time.Weekday]openingHours),
+		slots:    make(map[uint]map[time.Weekday]openingHours),
+	}
+
+	for i := range rows {
+		hours, err := resolveOpeningHours(&rows[i])
+		if err != nil {
+			return weeklySchedule{}, fmt.Errorf("business hours %d: %w", rows[i].ID, err)
+		}
+
+		if rows[i].SlotID == nil {
+			schedule.business[rows[i].Weekday] = hours
+			continue
+		}
+
+		slotID := *rows[i].SlotID
+		if schedule.slots[slotID] == nil {
+			schedule.slots[slotID] = make(map[time.Weekday]openingHours)
+		}
+		schedule.slots[slotID][rows[i].Weekday] = hours
+	}
+
+	return schedule, nil
+}
+
+// forSlot returns the opening hours of a slot on a weekday.
+// A slot override wins over the business-wide row, which wins over the default.
+func (s weeklySchedule) forSlot(slotID uint, day time.Weekday) openingHours {
+	if hours, ok := s.slots[slotID][day]; ok {
+		return hours
+	}
+	if hours, ok := s.business[day]; ok {
+		return hours
+	}
+	return defaultOpeningHours
+}
+
+// resolveOpeningHours parses a BusinessHours row
+func resolveOpeningHours(row *models.BusinessHours) (openingHours, error) {
+	if row.IsClosed {
+		return openingHours{closed: true}, nil
+	}
+
+	open, err := row.OpenMinutes()
+	if err != nil {
+		return openingHours{}, err
+	}
+	close, err := row.CloseMinutes()
+	if err != nil {
+		return openingHours{}, err
+	}
+
+	return openingHours{open: open, close: close}, nil
+}
+
+// formatClock formats minutes since midnight as "HH:MM"
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,255 @@
+package importers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+)
+
+// ErrInvalidFile is returned when a closure file cannot be parsed
+var ErrInvalidFile = errors.New("invalid closure file")
+
+// csvDateTimeLayouts are the accepted formats for CSV start/end columns
+var csvDateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+// ParseICS reads the VEVENTs of an iCalendar file as business-wide closures.
+// All-day events (DTSTART;VALUE=DATE) close whole days in loc; floating times are read in loc.
+func ParseICS(r io.Reader, loc *time.Location) ([]models.Closure, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		closures []models.Closure
+		inEvent  bool
+		start    time.Time
+		end      time.Time
+		allDay   bool
+		summary  string
+	)
+
+	for n, line := range lines {
+		name, params, value := splitICSLine(line)
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, start, end, allDay, summary = true, time.Time{}, time.Time{}, false, ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent {
+				return nil, fmt.Errorf("%w: line %d: END:VEVENT without BEGIN", ErrInvalidFile, n+1)
+			}
+			inEvent = false
+
+			if start.IsZero() {
+				return nil, fmt.Errorf("%w: line %d: event without DTSTART", ErrInvalidFile, n+1)
+			}
+			if end.IsZero() {
+				if !allDay {
+					return nil, fmt.Errorf("%w: line %d: timed event without DTEND", ErrInvalidFile, n+1)
+				}
+				end = start.AddDate(0, 0, 1)
+			}
+			if !end.After(start) {
+				return nil, fmt.Errorf("%w: line %d: event ends before it starts", ErrInvalidFile, n+1)
+			}
+
+			closures = append(closures, models.Closure{
+				StartsAt: start,
+				EndsAt:   end,
+				Reason:   summary,
+			})
+		case !inEvent:
+			continue
+		case name == "DTSTART":
+			start, allDay, err = parseICSTime(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, n+1, err)
+			}
+		case name == "DTEND":
+			end, _, err = parseICSTime(params, value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, n+1, err)
+			}
+		case name == "SUMMARY":
+			summary = unescapeICSText(value)
+		}
+	}
+
+	if inEvent {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalidFile)
+	}
+	return closures, nil
+}
+
+// ParseCSV reads closures from a CSV file with a header row of
+// start,end,reason and an optional slot_id column.
+// Date-only values close whole days in loc: an empty end closes only the start day,
+// and a date-only end is inclusive.
+func ParseCSV(r io.Reader, loc *time.Location) ([]models.Closure, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header: %v", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["start"]; !ok {
+		return nil, fmt.Errorf("%w: header must include a start column", ErrInvalidFile)
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var closures []models.Closure
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+
+		start, startIsDate, err := parseCSVTime(field(record, "start"), loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: start: %v", ErrInvalidFile, line, err)
+		}
+
+		var end time.Time
+		switch raw := field(record, "end"); {
+		case raw == "" && startIsDate:
+			end = start.AddDate(0, 0, 1)
+		case raw == "":
+			return nil, fmt.Errorf("%w: line %d: end is required for timed closures", ErrInvalidFile, line)
+		default:
+			var endIsDate bool
+			end, endIsDate, err = parseCSVTime(raw, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: end: %v", ErrInvalidFile, line, err)
+			}
+			if endIsDate {
+				end = end.AddDate(0, 0, 1)
+			}
+		}
+
+		if !end.After(start) {
+			return nil, fmt.Errorf("%w: line %d: closure ends before it starts", ErrInvalidFile, line)
+		}
+
+		closure := models.Closure{
+			StartsAt: start,
+			EndsAt:   end,
+			Reason:   field(record, "reason"),
+		}
+
+		if raw := field(record, "slot_id"); raw != "" {
+			slotID, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil || slotID == 0 {
+				return nil, fmt.Errorf("%w: line %d: invalid slot_id %q", ErrInvalidFile, line, raw)
+			}
+			id := uint(slotID)
+			closure.SlotID = &id
+		}
+
+		closures = append(closures, closure)
+	}
+
+	return closures, nil
+}
+
+// unfoldICS splits an iCalendar stream into logical lines, joining folded continuation lines
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return lines, nil
+}
+
+// splitICSLine splits "NAME;PARAM=X:VALUE" into its name, parameters and value
+func splitICSLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+
+	params := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		key, val, _ := strings.Cut(part, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value)
+}
+
+// parseICSTime parses a DTSTART/DTEND value, reporting whether it is an all-day date
+func parseICSTime(params map[string]string, value string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	eventLoc := loc
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			eventLoc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, eventLoc)
+	return t, false, err
+}
+
+// parseCSVTime parses a CSV start/end value, reporting whether it is a date without time
+func parseCSVTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range csvDateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognized date %q", value)
+}
+
+// unescapeICSText reverses iCalendar TEXT escaping
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
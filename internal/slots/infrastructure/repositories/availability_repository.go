@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
 	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
 	"gorm.io/gorm"
 )
@@ -33,17 +36,19 @@ func (r *AvailabilityRepository) FindAllSlots(ctx context.Context) ([]models.Slo
 	return slots, nil
 }
 
-// FindReservationsByDateRange returns active reservations within a date range
-func (r *AvailabilityRepository) FindReservationsByDateRange(ctx context.Context, start, end time.Time) ([]reservationModels.Reservation, error) {
+// FindReservationsByDateRange returns active reservations within a date range.
+// Holds unexpired at now are included since they occupy their slot; expired ones are not.
+func (r *AvailabilityRepository) FindReservationsByDateRange(ctx context.Context, start, end, now time.Time) ([]reservationModels.Reservation, error) {
 	var reservations []reservationModels.Reservation
 
 	// Get reservations that are pending or confirmed within the date range
 	if err := r.db.WithContext(ctx).
-		Where("start_time >= ? AND start_time < ?", start, end).
+		Where("start_time >= ? AND start_time < ?", start.UTC(), end.UTC()).
 		Where("status IN ?", []string{
 			string(reservationModels.ReservationStatusPending),
 			string(reservationModels.ReservationStatusConfirmed),
 		}).
+		Where("expires_at IS NULL OR expires_at > ?", now.UTC()).
 		Find(&reservations).Error; err != nil {
 		return nil, err
 	}
@@ -51,3 +56,28 @@ func (r *AvailabilityRepository) FindReservationsByDateRange(ctx context.Context
 	return reservations, nil
 }
 
+// FindServiceByID returns a service by ID, or common.ErrNotFound
+func (r *AvailabilityRepository) FindServiceByID(ctx context.Context, id uint) (*serviceModels.Service, error) {
+	var service serviceModels.Service
+	if err := r.db.WithContext(ctx).First(&service, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &service, nil
+}
+
+// FindBusinessHours returns all business-wide and per-slot opening hours
+func (r *AvailabilityRepository) FindBusinessHours(ctx context.Context) ([]models.BusinessHours, error) {
+	var hours []models.BusinessHours
+	if err := r.db.WithContext(ctx).Find(&hours).Error; err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+// FindClosuresByDateRange returns business-wide and per-slot closures overlapping [start, end)
+func (r *AvailabilityRepository) FindClosuresByDateRange(ctx context.Context, start, end time.Time) ([]models.Closure, error) {
+	return findClosuresByDateRange(r.db.WithContext(ctx), start, end)
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"gorm.io/gorm"
+)
+
+// BusinessHoursRepository implements the business hours repository interface
+type BusinessHoursRepository struct {
+	db *gorm.DB
+}
+
+// NewBusinessHoursRepository creates a new business hours repository
+func NewBusinessHoursRepository(db *gorm.DB) *BusinessHoursRepository {
+	return &BusinessHoursRepository{
+		db: db,
+	}
+}
+
+// FindAll retrieves all opening hours, business-wide rows first
+func (r *BusinessHoursRepository) FindAll(ctx context.Context) ([]models.BusinessHours, error) {
+	var hours []models.BusinessHours
+	if err := r.db.WithContext(ctx).
+		Order("slot_id IS NOT NULL, slot_id ASC, weekday ASC").
+		Find(&hours).Error; err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+// FindByID retrieves opening hours by ID
+func (r *BusinessHoursRepository) FindByID(ctx context.Context, id uint) (*models.BusinessHours, error) {
+	var hours models.BusinessHours
+	if err := r.db.WithContext(ctx).First(&hours, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &hours, nil
+}
+
+// Upsert creates or replaces the opening hours for a weekday and optional slot
+func (r *BusinessHoursRepository) Upsert(ctx context.Context, hours *models.BusinessHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("weekday = ?", hours.Weekday)
+		if hours.SlotID == nil {
+			query = query.Where("slot_id IS NULL")
+		} else {
+			query = query.Where("slot_id = ?", *hours.SlotID)
+		}
+
+		var existing models.BusinessHours
+		err := query.First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return tx.Create(hours).Error
+		case err != nil:
+			return err
+		}
+
+		hours.ID = existing.ID
+		hours.CreatedAt = existing.CreatedAt
+		hours.UpdatedAt = time.Now()
+		return tx.Save(hours).Error
+	})
+}
+
+// Delete removes opening hours, restoring the fallback for that weekday
+func (r *BusinessHoursRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.BusinessHours{}, id).Error
+}
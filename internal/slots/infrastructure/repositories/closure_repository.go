@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"gorm.io/gorm"
+)
+
+// ClosureRepository implements the closure repository interface
+type ClosureRepository struct {
+	db *gorm.DB
+}
+
+// NewClosureRepository creates a new closure repository
+func NewClosureRepository(db *gorm.DB) *ClosureRepository {
+	return &ClosureRepository{
+		db: db,
+	}
+}
+
+// FindByDateRange retrieves closures overlapping [start, end)
+func (r *ClosureRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]models.Closure, error) {
+	return findClosuresByDateRange(r.db.WithContext(ctx), start, end)
+}
+
+// FindByID retrieves a closure by ID
+func (r *ClosureRepository) FindByID(ctx context.Context, id uint) (*models.Closure, error) {
+	var closure models.Closure
+	if err := r.db.WithContext(ctx).First(&closure, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &closure, nil
+}
+
+// Create creates a new closure
+func (r *ClosureRepository) Create(ctx context.Context, closure *models.Closure) error {
+	return r.db.WithContext(ctx).Create(closure).Error
+}
+
+// CreateMany creates closures in a single transaction, skipping exact duplicates
+// of existing closures (same slot, start and end). Returns how many were created.
+func (r *ClosureRepository) CreateMany(ctx context.Context, closures []models.Closure) (int, error) {
+	created := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range closures {
+			query := tx.Model(&models.Closure{}).
+				Where("starts_at = ? AND ends_at = ?", closures[i].StartsAt.UTC(), closures[i].EndsAt.UTC())
+			if closures[i].SlotID == nil {
+				query = query.Where("slot_id IS NULL")
+			} else {
+				query = query.Where("slot_id = ?", *closures[i].SlotID)
+			}
+
+			var count int64
+			if err := query.Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+
+			if err := tx.Create(&closures[i]).Error; err != nil {
+				return err
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// Delete soft deletes a closure
+func (r *ClosureRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Closure{}, id).Error
+}
+
+// findClosuresByDateRange retrieves closures overlapping [start, end)
+func findClosuresByDateRange(db *gorm.DB, start, end time.Time) ([]models.Closure, error) {
+	var closures []models.Closure
+	if err := db.
+		Where("starts_at < ? AND ends_at > ?", end.UTC(), start.UTC()).
+		Order("starts_at ASC").
+		Find(&closures).Error; err != nil {
+		return nil, err
+	}
+	return closures, nil
+}
@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
 	"gorm.io/gorm"
 )
@@ -32,6 +34,9 @@ func (r *SlotRepository) FindAll(ctx context.Context) ([]models.Slot, error) {
 func (r *SlotRepository) FindByID(ctx context.Context, id uint) (*models.Slot, error) {
 	var slot models.Slot
 	if err := r.db.WithContext(ctx).First(&slot, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
 		return nil, err
 	}
 	return &slot, nil
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/domain/policies"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/usecases"
+)
+
+// WaitlistHandler handles HTTP requests for the waitlist
+type WaitlistHandler struct {
+	useCase *usecases.WaitlistUseCase
+}
+
+// NewWaitlistHandler creates a new waitlist handler
+func NewWaitlistHandler(useCase *usecases.WaitlistUseCase) *WaitlistHandler {
+	return &WaitlistHandler{
+		useCase: useCase,
+	}
+}
+
+// joinWaitlistRequest is the request body for POST /waitlist
+type joinWaitlistRequest struct {
+	UserID    uint      `json:"user_id" binding:"required"`
+	AddressID uint      `json:"address_id" binding:"required"`
+	SlotID    *uint     `json:"slot_id"`
+	ServiceID uint      `json:"service_id"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+}
+
+// RegisterRoutes registers all waitlist routes
+func (h *WaitlistHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	waitlist := rg.Group("/waitlist")
+	{
+		waitlist.GET("", h.List)
+		waitlist.GET("/:id", h.GetByID)
+		waitlist.POST("", h.Join)
+		waitlist.DELETE("/:id", h.Leave)
+	}
+}
+
+// List returns a customer's waitlist entries
+// @Summary List waitlist entries
+// @Tags waitlist
+// @Produce json
+// @Param user_id query int false "Customer; required for staff, defaults to the caller for customers"
+// @Success 200 {array} models.WaitlistEntry
+// @Failure 400 {object} common.APIError "Missing user_id"
+// @Failure 403 {object} common.APIError "Another customer's entries"
+// @Router /api/v1/waitlist [get]
+func (h *WaitlistHandler) List(c *gin.Context) {
+	requested, err := common.ParseOptionalIDQuery(c, "user_id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	_, userID, err := policies.ScopeClient(c.Request.Context(), requested)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+	if userID == 0 {
+		common.RespondError(c, fmt.Errorf("%w: user_id is required", common.ErrInvalidInput))
+		return
+	}
+
+	entries, err := h.useCase.ListUserEntries(c.Request.Context(), userID)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entries,
+	})
+}
+
+// GetByID returns a waitlist entry by ID
+func (h *WaitlistHandler) GetByID(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	entry, err := h.useCase.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := policies.RequireOwner(c.Request.Context(), entry.UserID, common.ErrNotFound); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entry,
+	})
+}
+
+// Join registers interest in a fully booked start time
+// @Summary Join waitlist
+// @Description When a matching reservation is cancelled, the first customer in line is offered a hold on the freed slot
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.WaitlistEntry
+// @Failure 400 {object} common.APIError "Invalid input"
+// @Failure 409 {object} common.APIError "Already waiting for this time"
+// @Router /api/v1/waitlist [post]
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	var req joinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondError(c, fmt.Errorf("%w: %v", common.ErrInvalidInput, err))
+		return
+	}
+
+	if _, err := policies.RequireClient(c.Request.Context(), req.UserID); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	entry, err := h.useCase.JoinWaitlist(c.Request.Context(), usecases.JoinWaitlistInput{
+		UserID:    req.UserID,
+		AddressID: req.AddressID,
+		SlotID:    req.SlotID,
+		ServiceID: req.ServiceID,
+		StartTime: req.StartTime,
+	})
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": entry,
+	})
+}
+
+// Leave withdraws a waiting entry
+func (h *WaitlistHandler) Leave(c *gin.Context) {
+	id, err := common.ParseIDParam(c, "id")
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	current, err := h.useCase.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	if _, err := policies.RequireOwner(c.Request.Context(), current.UserID, common.ErrNotFound); err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	entry, err := h.useCase.LeaveWaitlist(c.Request.Context(), id)
+	if err != nil {
+		common.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entry,
+	})
+}
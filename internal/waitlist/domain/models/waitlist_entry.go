@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistStatus represents the status of a waitlist entry
+type WaitlistStatus string
+
+const (
+	// WaitlistStatusWaiting entries are in line for a freed spot
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	// WaitlistStatusOffered entries received a hold on a freed spot
+	WaitlistStatusOffered WaitlistStatus = "offered"
+	// WaitlistStatusFulfilled entries confirmed their offered hold
+	WaitlistStatusFulfilled WaitlistStatus = "fulfilled"
+	// WaitlistStatusExpired entries let their offered hold lapse
+	WaitlistStatusExpired WaitlistStatus = "expired"
+	// WaitlistStatusCancelled entries were withdrawn by the customer
+	WaitlistStatusCancelled WaitlistStatus = "cancelled"
+)
+
+// WaitlistEntry represents a customer waiting for a fully booked start time.
+// A nil SlotID accepts any slot.
+type WaitlistEntry struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	UserID    uint           `gorm:"index;not null" json:"user_id"`
+	AddressID uint           `gorm:"not null" json:"address_id"`
+	SlotID    *uint          `gorm:"index" json:"slot_id,omitempty"`
+	ServiceID uint           `json:"service_id,omitempty"`
+	StartTime time.Time      `gorm:"not null;index" json:"start_time"`
+	Status    WaitlistStatus `gorm:"type:varchar(20);default:'waiting';index" json:"status"`
+	// OfferedReservationID is the hold created for this entry when a spot was freed
+	OfferedReservationID *uint          `gorm:"index" json:"offered_reservation_id,omitempty"`
+	OfferedAt            *time.Time     `json:"offered_at,omitempty"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for WaitlistEntry
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
+
+// BeforeSave stores times in UTC so SQLite's text comparisons order them correctly
+func (e *WaitlistEntry) BeforeSave(tx *gorm.DB) error {
+	e.StartTime = e.StartTime.UTC()
+	if e.OfferedAt != nil {
+		offeredAt := e.OfferedAt.UTC()
+		e.OfferedAt = &offeredAt
+	}
+	return nil
+}
+
+// Accepts returns true if the entry can be offered a spot on slotID
+func (e *WaitlistEntry) Accepts(slotID uint) bool {
+	return e.SlotID == nil || *e.SlotID == slotID
+}
+
+// WaitlistOfferEvent is emitted when a freed spot is offered to a waitlisted customer
+type WaitlistOfferEvent struct {
+	EntryID       uint      `json:"entry_id"`
+	UserID        uint      `json:"user_id"`
+	ReservationID uint      `json:"reservation_id"`
+	SlotID        uint      `json:"slot_id"`
+	StartTime     time.Time `json:"start_time"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
@@ -0,0 +1,250 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/models"
+)
+
+// WaitlistRepository defines the interface for waitlist data access
+type WaitlistRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error)
+	FindByUserID(ctx context.Context, userID uint) ([]models.WaitlistEntry, error)
+	// FindWaiting returns waiting entries starting within [from, to), oldest first
+	FindWaiting(ctx context.Context, from, to time.Time) ([]models.WaitlistEntry, error)
+	// FindByOfferedReservationID returns the entry a hold was offered to, or common.ErrNotFound
+	FindByOfferedReservationID(ctx context.Context, reservationID uint) (*models.WaitlistEntry, error)
+	Create(ctx context.Context, entry *models.WaitlistEntry) error
+	Update(ctx context.Context, entry *models.WaitlistEntry) error
+}
+
+// ReservationHolder places temporary holds on a slot
+type ReservationHolder interface {
+	HoldReservation(ctx context.Context, input reservationUsecases.CreateReservationInput) (*reservationModels.Reservation, error)
+}
+
+// WaitlistNotifier delivers waitlist events to customers
+type WaitlistNotifier interface {
+	NotifyOffer(ctx context.Context, event models.WaitlistOfferEvent) error
+}
+
+// JoinWaitlistInput holds the data required to join the waitlist
+type JoinWaitlistInput struct {
+	UserID    uint
+	AddressID uint
+	SlotID    *uint
+	ServiceID uint
+	StartTime time.Time
+}
+
+// WaitlistUseCase handles waitlist business logic
+type WaitlistUseCase struct {
+	repo     WaitlistRepository
+	holds    ReservationHolder
+	notifier WaitlistNotifier
+	now      common.Clock
+}
+
+// NewWaitlistUseCase creates a new waitlist use case
+func NewWaitlistUseCase(repo WaitlistRepository, holds ReservationHolder, notifier WaitlistNotifier, now common.Clock) *WaitlistUseCase {
+	return &WaitlistUseCase{
+		repo:     repo,
+		holds:    holds,
+		notifier: notifier,
+		now:      now,
+	}
+}
+
+// JoinWaitlist registers interest in a start time, optionally on a specific slot and for a service
+func (uc *WaitlistUseCase) JoinWaitlist(ctx context.Context, input JoinWaitlistInput) (*models.WaitlistEntry, error) {
+	if input.UserID == 0 || input.AddressID == 0 {
+		return nil, fmt.Errorf("%w: user_id and address_id are required", common.ErrInvalidInput)
+	}
+	if input.SlotID != nil && *input.SlotID == 0 {
+		return nil, fmt.Errorf("%w: slot_id must be positive", common.ErrInvalidInput)
+	}
+	if !input.StartTime.After(uc.now()) {
+		return nil, fmt.Errorf("%w: start_time must be in the future", common.ErrInvalidInput)
+	}
+
+	existing, err := uc.repo.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	for i := range existing {
+		if existing[i].Status == models.WaitlistStatusWaiting &&
+			existing[i].StartTime.Equal(input.StartTime) &&
+			sameSlot(existing[i].SlotID, input.SlotID) {
+			return nil, fmt.Errorf("%w: already on the waitlist for this time", common.ErrConflict)
+		}
+	}
+
+	entry := &models.WaitlistEntry{
+		UserID:    input.UserID,
+		AddressID: input.AddressID,
+		SlotID:    input.SlotID,
+		ServiceID: input.ServiceID,
+		StartTime: input.StartTime,
+		Status:    models.WaitlistStatusWaiting,
+	}
+
+	if err := uc.repo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return entry, nil
+}
+
+// GetEntry returns a waitlist entry by ID
+func (uc *WaitlistUseCase) GetEntry(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	entry, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return entry, nil
+}
+
+// ListUserEntries returns a customer's waitlist entries
+func (uc *WaitlistUseCase) ListUserEntries(ctx context.Context, userID uint) ([]models.WaitlistEntry, error) {
+	entries, err := uc.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return entries, nil
+}
+
+// LeaveWaitlist withdraws a waiting entry
+func (uc *WaitlistUseCase) LeaveWaitlist(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	entry, err := uc.GetEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != models.WaitlistStatusWaiting {
+		return nil, fmt.Errorf("%w: cannot leave the waitlist from status %s", common.ErrConflict, entry.Status)
+	}
+
+	entry.Status = models.WaitlistStatusCancelled
+	if err := uc.repo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInternalServer, err)
+	}
+	return entry, nil
+}
+
+// SlotReleased offers a freed slot to the waitlisted customers whose start times fall within
+// the released reservation, in line order, each as a hold that expires unless confirmed.
+// Entries whose time was taken by an earlier offer keep waiting. If the released reservation
+// was an unconfirmed waitlist offer, that entry expires.
+func (uc *WaitlistUseCase) SlotReleased(ctx context.Context, reservation reservationModels.Reservation) {
+	// Confirming a hold clears its expiry, so only lapsed offers still carry one
+	if reservation.ExpiresAt != nil {
+		uc.closeOffer(ctx, reservation.ID, models.WaitlistStatusExpired)
+	}
+
+	entries, err := uc.repo.FindWaiting(ctx, reservation.StartTime, reservation.EffectiveEndTime())
+	if err != nil {
+		common.Logger.Error("Failed to load waitlist", zap.Uint("reservation_id", reservation.ID), zap.Error(err))
+		return
+	}
+
+	for i := range entries {
+		if !entries[i].Accepts(reservation.SlotID) {
+			continue
+		}
+
+		hold, err := uc.holds.HoldReservation(ctx, reservationUsecases.CreateReservationInput{
+			UserID:    entries[i].UserID,
+			SlotID:    reservation.SlotID,
+			AddressID: entries[i].AddressID,
+			ServiceID: entries[i].ServiceID,
+			StartTime: entries[i].StartTime,
+			Notes:     fmt.Sprintf("Waitlist entry %d", entries[i].ID),
+		})
+		if err != nil {
+			// The freed time may not fit this entry's service or may have gone to an earlier
+			// entry; try the next one in line
+			if errors.Is(err, common.ErrSlotNotAvailable) || errors.Is(err, common.ErrInvalidInput) {
+				continue
+			}
+			common.Logger.Error("Failed to hold slot for waitlist entry", zap.Uint("entry_id", entries[i].ID), zap.Error(err))
+			return
+		}
+
+		uc.offer(ctx, &entries[i], hold)
+	}
+}
+
+// offer records the hold on the entry and notifies the customer
+func (uc *WaitlistUseCase) offer(ctx context.Context, entry *models.WaitlistEntry, hold *reservationModels.Reservation) {
+	now := uc.now()
+	entry.Status = models.WaitlistStatusOffered
+	entry.OfferedReservationID = &hold.ID
+	entry.OfferedAt = &now
+
+	if err := uc.repo.Update(ctx, entry); err != nil {
+		common.Logger.Error("Failed to record waitlist offer",
+			zap.Uint("entry_id", entry.ID),
+			zap.Uint("reservation_id", hold.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	event := models.WaitlistOfferEvent{
+		EntryID:       entry.ID,
+		UserID:        entry.UserID,
+		ReservationID: hold.ID,
+		SlotID:        hold.SlotID,
+		StartTime:     hold.StartTime,
+	}
+	if hold.ExpiresAt != nil {
+		event.ExpiresAt = *hold.ExpiresAt
+	}
+
+	if err := uc.notifier.NotifyOffer(ctx, event); err != nil {
+		common.Logger.Error("Failed to notify waitlist offer", zap.Uint("entry_id", entry.ID), zap.Error(err))
+	}
+}
+
+// ReservationConfirmed marks the entry whose offered hold was confirmed as fulfilled
+func (uc *WaitlistUseCase) ReservationConfirmed(ctx context.Context, reservation reservationModels.Reservation) {
+	uc.closeOffer(ctx, reservation.ID, models.WaitlistStatusFulfilled)
+}
+
+// closeOffer moves the entry offered reservationID, if any and still offered, to status
+func (uc *WaitlistUseCase) closeOffer(ctx context.Context, reservationID uint, status models.WaitlistStatus) {
+	entry, err := uc.repo.FindByOfferedReservationID(ctx, reservationID)
+	if err != nil {
+		if !errors.Is(err, common.ErrNotFound) {
+			common.Logger.Error("Failed to load waitlist offer", zap.Uint("reservation_id", reservationID), zap.Error(err))
+		}
+		return
+	}
+
+	if entry.Status != models.WaitlistStatusOffered {
+		return
+	}
+
+	entry.Status = status
+	if err := uc.repo.Update(ctx, entry); err != nil {
+		common.Logger.Error("Failed to close waitlist offer", zap.Uint("entry_id", entry.ID), zap.String("status", string(status)), zap.Error(err))
+	}
+}
+
+// sameSlot compares two optional slot IDs
+func sameSlot(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
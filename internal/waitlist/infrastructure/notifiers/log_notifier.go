@@ -0,0 +1,32 @@
+package notifiers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/models"
+)
+
+// LogNotifier writes waitlist events to the application log.
+// It stands in for email/SMS delivery until a real channel is configured.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new log notifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// NotifyOffer logs a waitlist offer
+func (n *LogNotifier) NotifyOffer(ctx context.Context, event models.WaitlistOfferEvent) error {
+	common.Logger.Info("Waitlist spot offered",
+		zap.Uint("entry_id", event.EntryID),
+		zap.Uint("user_id", event.UserID),
+		zap.Uint("reservation_id", event.ReservationID),
+		zap.Uint("slot_id", event.SlotID),
+		zap.Time("start_time", event.StartTime),
+		zap.Time("expires_at", event.ExpiresAt),
+	)
+	return nil
+}
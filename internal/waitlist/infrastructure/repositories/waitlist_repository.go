@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/models"
+	"gorm.io/gorm"
+)
+
+// WaitlistRepository implements the waitlist repository interface
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves a waitlist entry by ID
+func (r *WaitlistRepository) FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := r.db.WithContext(ctx).First(&entry, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByUserID retrieves all waitlist entries of a user, soonest first
+func (r *WaitlistRepository) FindByUserID(ctx context.Context, userID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("start_time ASC, id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindWaiting retrieves waiting entries starting within [from, to), in the order they joined
+func (r *WaitlistRepository) FindWaiting(ctx context.Context, from, to time.Time) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", models.WaitlistStatusWaiting).
+		Where("start_time >= ? AND start_time < ?", from.UTC(), to.UTC()).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindByOfferedReservationID retrieves the entry a reservation hold was offered to
+func (r *WaitlistRepository) FindByOfferedReservationID(ctx context.Context, reservationID uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := r.db.WithContext(ctx).Where("offered_reservation_id = ?", reservationID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Create creates a new waitlist entry
+func (r *WaitlistRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// Update updates an existing waitlist entry
+func (r *WaitlistRepository) Update(ctx context.Context, entry *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Save(entry).Error
+}
@@ -0,0 +1,297 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	authHttp "github.com/Jose-Ig/lavalo-backend/internal/auth/application/http"
+	authModels "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	authUsecases "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/ratelimit"
+	authRepos "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/repositories"
+	authTokens "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/tokens"
+	clientHttp "github.com/Jose-Ig/lavalo-backend/internal/clients/application/http"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	serviceHttp "github.com/Jose-Ig/lavalo-backend/internal/services/application/http"
+	serviceUsecases "github.com/Jose-Ig/lavalo-backend/internal/services/domain/usecases"
+	serviceRepos "github.com/Jose-Ig/lavalo-backend/internal/services/infrastructure/repositories"
+)
+
+// newAPIKeyUseCase wires an API key use case over a test database with a client 1,
+// a default limit of 60 requests per minute and a controllable clock. Deleting a client
+// through the returned client use case revokes its keys, as in the API.
+func newAPIKeyUseCase(t *testing.T) (*authUsecases.APIKeyUseCase, *testClock, *clientUsecases.ClientUseCase) {
+	t.Helper()
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&authModels.APIKey{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	clients := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+	if _, err := clients.CreateClient(context.Background(), clientUsecases.ClientInput{Name: "Concesionario", Email: "flota@example.com"}); err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	clock := &testClock{now: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	limiter := ratelimit.NewFixedWindowLimiter(time.Minute)
+	keys := authUsecases.NewAPIKeyUseCase(authRepos.NewAPIKeyRepository(db), clients, limiter, 60, clock.Now)
+	clients.AddClientDeletionListener(keys)
+	return keys, clock, clients
+}
+
+// issueKey issues an API key for client 1
+func issueKey(t *testing.T, useCase *authUsecases.APIKeyUseCase, rateLimit int, scopes ...authModels.Scope) (*authModels.APIKey, string) {
+	t.Helper()
+
+	key, value, err := useCase.IssueAPIKey(context.Background(), authUsecases.IssueAPIKeyInput{
+		ClientID:           1,
+		Name:               "Sistema de flota",
+		Scopes:             scopes,
+		RateLimitPerMinute: rateLimit,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return key, value
+}
+
+func TestAPIKey_Issue(t *testing.T) {
+	useCase, _, _ := newAPIKeyUseCase(t)
+	ctx := context.Background()
+
+	key, value := issueKey(t, useCase, 0, authModels.ScopeAvailabilityRead, authModels.ScopeAvailabilityRead)
+	if len(key.Scopes) != 1 || key.RateLimitPerMinute != 60 {
+		t.Errorf("expected deduplicated scopes and the default rate limit, got %v %d", key.Scopes, key.RateLimitPerMinute)
+	}
+	if value[:len(key.Prefix)] != key.Prefix || key.KeyHash == "" || key.KeyHash == value {
+		t.Errorf("expected the prefix in clear and only a hash of the key stored")
+	}
+
+	principal, err := useCase.AuthenticateAPIKey(ctx, value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.ClientID != 1 || principal.APIKeyID != key.ID || principal.IsStaff() || principal.String() != "apikey:1" {
+		t.Errorf("expected the key to act as client 1 without staff rights, got %+v", principal)
+	}
+
+	cases := map[string]authUsecases.IssueAPIKeyInput{
+		"no name":        {ClientID: 1, Scopes: []authModels.Scope{authModels.ScopeServicesRead}},
+		"no scopes":      {ClientID: 1, Name: "x"},
+		"unknown scope":  {ClientID: 1, Name: "x", Scopes: []authModels.Scope{"payments:write"}},
+		"negative limit": {ClientID: 1, Name: "x", Scopes: []authModels.Scope{authModels.ScopeServicesRead}, RateLimitPerMinute: -1},
+		"limit too high": {ClientID: 1, Name: "x", Scopes: []authModels.Scope{authModels.ScopeServicesRead}, RateLimitPerMinute: 100000},
+		"unknown client": {ClientID: 99, Name: "x", Scopes: []authModels.Scope{authModels.ScopeServicesRead}},
+		"blank name":     {ClientID: 1, Name: "  ", Scopes: []authModels.Scope{authModels.ScopeServicesRead}},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := useCase.IssueAPIKey(ctx, input); !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAPIKey_RevokeAndLastUsed(t *testing.T) {
+	useCase, clock, _ := newAPIKeyUseCase(t)
+	ctx := context.Background()
+
+	key, value := issueKey(t, useCase, 0, authModels.ScopeServicesRead)
+
+	if _, err := useCase.AuthenticateAPIKey(ctx, value); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, err := useCase.ListAPIKeys(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].LastUsedAt == nil || !keys[0].LastUsedAt.Equal(clock.now) {
+		t.Fatalf("expected last_used_at to be the time of use, got %+v", keys)
+	}
+
+	if _, err := useCase.RevokeAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := useCase.RevokeAPIKey(ctx, key.ID); err != nil {
+		t.Errorf("expected revoking twice to be a no-op, got %v", err)
+	}
+	if _, err := useCase.AuthenticateAPIKey(ctx, value); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected a revoked key to be rejected, got %v", err)
+	}
+	if _, err := useCase.RevokeAPIKey(ctx, 99); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAPIKey_DeletedClient(t *testing.T) {
+	useCase, _, clients := newAPIKeyUseCase(t)
+	ctx := context.Background()
+
+	_, value := issueKey(t, useCase, 0, authModels.ScopeServicesRead)
+	if err := clients.DeleteClient(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := useCase.AuthenticateAPIKey(ctx, value); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected the key of a deleted client to be rejected, got %v", err)
+	}
+	keys, err := useCase.ListAPIKeys(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Errorf("expected deleting the client to revoke its key, got %+v", keys)
+	}
+}
+
+func TestAPIKey_DeletedClientWithoutRevocation(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&authModels.APIKey{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	ctx := context.Background()
+
+	// No deletion listener: the key is left unrevoked, as when revoking fails
+	clientRepo := clientRepos.NewClientRepository(db)
+	clients := clientUsecases.NewClientUseCase(clientRepo)
+	clock := &testClock{now: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	useCase := authUsecases.NewAPIKeyUseCase(authRepos.NewAPIKeyRepository(db), clients, ratelimit.NewFixedWindowLimiter(time.Minute), 60, clock.Now)
+
+	_, value := issueKey(t, useCase, 0, authModels.ScopeServicesRead)
+	if err := clientRepo.Delete(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := useCase.AuthenticateAPIKey(ctx, value); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected the key of a deleted client to be rejected, got %v", err)
+	}
+}
+
+func TestAPIKey_RateLimit(t *testing.T) {
+	useCase, clock, _ := newAPIKeyUseCase(t)
+	ctx := context.Background()
+
+	_, value := issueKey(t, useCase, 2, authModels.ScopeServicesRead)
+	_, other := issueKey(t, useCase, 2, authModels.ScopeServicesRead)
+
+	for i := 0; i < 2; i++ {
+		if _, err := useCase.AuthenticateAPIKey(ctx, value); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	clock.now = clock.now.Add(15 * time.Second)
+	_, err := useCase.AuthenticateAPIKey(ctx, value)
+	var limited *authUsecases.RateLimitError
+	if !errors.As(err, &limited) || !errors.Is(err, common.ErrTooManyRequests) {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	if limited.RetryAfter != 45*time.Second {
+		t.Errorf("expected to retry when the minute ends in 45s, got %s", limited.RetryAfter)
+	}
+
+	// Limits are per key
+	if _, err := useCase.AuthenticateAPIKey(ctx, other); err != nil {
+		t.Errorf("expected another key to be unaffected, got %v", err)
+	}
+
+	clock.now = clock.now.Add(45 * time.Second)
+	if _, err := useCase.AuthenticateAPIKey(ctx, value); err != nil {
+		t.Errorf("expected the limit to reset in the next minute, got %v", err)
+	}
+}
+
+func TestAPIKey_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&authModels.APIKey{}, &authModels.RefreshToken{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	clients := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+	signer := authTokens.NewJWTSigner([]byte("test-secret"), "lavalo-test")
+	auth := authUsecases.NewAuthUseCase(clients, authRepos.NewRefreshTokenRepository(db), signer, time.Hour, 24*time.Hour, time.Now)
+	clock := &testClock{now: time.Now()}
+	keys := authUsecases.NewAPIKeyUseCase(authRepos.NewAPIKeyRepository(db), clients, ratelimit.NewFixedWindowLimiter(time.Minute), 60, clock.Now)
+
+	_, tokens, err := auth.Signup(context.Background(), authUsecases.SignupInput{
+		Client:   clientUsecases.ClientInput{Name: "Concesionario", Email: "flota@example.com"},
+		Password: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	v1.GET("/availability", authHttp.OptionalAuth(auth, keys), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	protected := v1.Group("", authHttp.RequireAuth(auth, keys))
+	serviceHttp.NewServiceHandler(serviceUsecases.NewServiceUseCase(serviceRepos.NewServiceRepository(db))).RegisterRoutes(protected)
+	clientHttp.NewVehicleHandler(clientUsecases.NewVehicleUseCase(clientRepos.NewVehicleRepository(db))).RegisterRoutes(protected)
+	authHttp.NewAuthHandler(auth).RegisterProtectedRoutes(protected)
+
+	_, catalog := issueKey(t, keys, 0, authModels.ScopeServicesRead, authModels.ScopeAvailabilityRead)
+	_, vehiclesOnly := issueKey(t, keys, 0, authModels.ScopeVehiclesRead)
+	_, limited := issueKey(t, keys, 1, authModels.ScopeServicesRead)
+	revokedKey, revoked := issueKey(t, keys, 0, authModels.ScopeServicesRead)
+	if _, err := keys.RevokeAPIKey(context.Background(), revokedKey.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		apiKey string
+		bearer string
+		want   int
+	}{
+		{"key with scope", "GET", "/api/v1/services", catalog, "", http.StatusOK},
+		{"key without scope", "GET", "/api/v1/services", vehiclesOnly, "", http.StatusForbidden},
+		{"key on own vehicles", "GET", "/api/v1/vehicles", vehiclesOnly, "", http.StatusOK},
+		{"route closed to keys", "POST", "/api/v1/services", catalog, "", http.StatusForbidden},
+		{"me is closed to keys", "GET", "/api/v1/auth/me", catalog, "", http.StatusForbidden},
+		{"revoked key", "GET", "/api/v1/services", revoked, "", http.StatusUnauthorized},
+		{"key and token together", "GET", "/api/v1/services", catalog, tokens.AccessToken, http.StatusBadRequest},
+		{"token still works", "GET", "/api/v1/auth/me", "", tokens.AccessToken, http.StatusOK},
+		{"anonymous public route", "GET", "/api/v1/availability", "", "", http.StatusOK},
+		{"public route with scope", "GET", "/api/v1/availability", catalog, "", http.StatusOK},
+		{"public route without scope", "GET", "/api/v1/availability", vehiclesOnly, "", http.StatusForbidden},
+		{"public route with bad key", "GET", "/api/v1/availability", "lvl_nope", "", http.StatusUnauthorized},
+		{"within rate limit", "GET", "/api/v1/services", limited, "", http.StatusOK},
+		{"over rate limit", "GET", "/api/v1/services", limited, "", http.StatusTooManyRequests},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.apiKey != "" {
+				req.Header.Set(authHttp.APIKeyHeader, tc.apiKey)
+			}
+			if tc.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.bearer)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+			if tc.want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+				t.Errorf("expected a Retry-After header")
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	authHttp "github.com/Jose-Ig/lavalo-backend/internal/auth/application/http"
+	authModels "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	authUsecases "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	authRepos "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/repositories"
+	authTokens "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/tokens"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+const testPassword = "correct horse battery"
+
+// testClock is a settable clock for token expiry tests
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// newAuthUseCase wires an auth use case over a test database with a controllable clock
+func newAuthUseCase(t *testing.T) (*authUsecases.AuthUseCase, *testClock) {
+	t.Helper()
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&authModels.RefreshToken{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	clock := &testClock{now: time.Now()}
+	clients := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+	signer := authTokens.NewJWTSigner([]byte("test-secret"), "lavalo-test")
+	useCase := authUsecases.NewAuthUseCase(clients, authRepos.NewRefreshTokenRepository(db), signer, 15*time.Minute, 24*time.Hour, clock.Now)
+	return useCase, clock
+}
+
+// signup registers a client with testPassword
+func signup(t *testing.T, useCase *authUsecases.AuthUseCase, email string) *authModels.TokenPair {
+	t.Helper()
+
+	_, tokens, err := useCase.Signup(context.Background(), authUsecases.SignupInput{
+		Client:   clientUsecases.ClientInput{Name: "Ana", Email: email},
+		Password: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return tokens
+}
+
+func TestSignupAndLogin(t *testing.T) {
+	useCase, _ := newAuthUseCase(t)
+	ctx := context.Background()
+
+	tokens := signup(t, useCase, "ana@example.com")
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.TokenType != "Bearer" {
+		t.Fatalf("expected a bearer token pair, got %+v", tokens)
+	}
+
+	if _, err := useCase.Login(ctx, "ANA@example.com", testPassword); err != nil {
+		t.Errorf("expected login with the email in any case to succeed, got %v", err)
+	}
+
+	cases := map[string]struct {
+		email    string
+		password string
+	}{
+		"wrong password": {"ana@example.com", "wrong password"},
+		"unknown email":  {"nobody@example.com", testPassword},
+		"no password":    {"client1@example.com", ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := useCase.Login(ctx, tc.email, tc.password); !errors.Is(err, common.ErrUnauthorized) {
+				t.Errorf("expected ErrUnauthorized, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSignup_Validation(t *testing.T) {
+	useCase, _ := newAuthUseCase(t)
+	ctx := context.Background()
+
+	for name, password := range map[string]string{
+		"too short": "short",
+		"too long":  strings.Repeat("a", 73),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := useCase.Signup(ctx, authUsecases.SignupInput{
+				Client:   clientUsecases.ClientInput{Name: "Ana", Email: "ana@example.com"},
+				Password: password,
+			})
+			if !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+
+	signup(t, useCase, "ana@example.com")
+	_, _, err := useCase.Signup(ctx, authUsecases.SignupInput{
+		Client:   clientUsecases.ClientInput{Name: "Ana", Email: "ana@example.com"},
+		Password: testPassword,
+	})
+	if !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected signing up twice to conflict, got %v", err)
+	}
+}
+
+func TestRefresh_RotatesAndDetectsReuse(t *testing.T) {
+	useCase, _ := newAuthUseCase(t)
+	ctx := context.Background()
+
+	first := signup(t, useCase, "ana@example.com")
+
+	second, err := useCase.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatalf("expected a new refresh token")
+	}
+
+	// Replaying the rotated token revokes the whole family, including the newest token
+	if _, err := useCase.Refresh(ctx, first.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected reuse to be rejected, got %v", err)
+	}
+	if _, err := useCase.Refresh(ctx, second.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected the newest token to be revoked after reuse, got %v", err)
+	}
+}
+
+func TestRefresh_ExpiredAndLoggedOut(t *testing.T) {
+	useCase, clock := newAuthUseCase(t)
+	ctx := context.Background()
+
+	tokens := signup(t, useCase, "ana@example.com")
+	if err := useCase.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := useCase.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected a logged out token to be rejected, got %v", err)
+	}
+
+	tokens, err := useCase.Login(ctx, "ana@example.com", testPassword)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.now = clock.now.Add(25 * time.Hour)
+	if _, err := useCase.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected an expired token to be rejected, got %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	useCase, clock := newAuthUseCase(t)
+	ctx := context.Background()
+
+	tokens := signup(t, useCase, "ana@example.com")
+
+	principal, err := useCase.Authenticate(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.ClientID != testClients+1 {
+		t.Errorf("expected client %d, got %d", testClients+1, principal.ClientID)
+	}
+
+	otherSigner := authTokens.NewJWTSigner([]byte("other-secret"), "lavalo-test")
+	forged, err := otherSigner.Sign(authModels.AccessClaims{ClientID: 1, IssuedAt: clock.now, ExpiresAt: clock.now.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := strings.Split(tokens.AccessToken, ".")
+	cases := map[string]string{
+		"empty":          "",
+		"malformed":      "not-a-token",
+		"forged":         forged,
+		"tampered claim": parts[0] + "." + parts[1] + "x." + parts[2],
+	}
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := useCase.Authenticate(ctx, token); !errors.Is(err, common.ErrUnauthorized) {
+				t.Errorf("expected ErrUnauthorized, got %v", err)
+			}
+		})
+	}
+
+	clock.now = clock.now.Add(15 * time.Minute)
+	if _, err := useCase.Authenticate(ctx, tokens.AccessToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected an expired access token to be rejected, got %v", err)
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useCase, _ := newAuthUseCase(t)
+
+	router := gin.New()
+	handler := authHttp.NewAuthHandler(useCase)
+	keys, _, _ := newAPIKeyUseCase(t)
+	handler.RegisterProtectedRoutes(router.Group("", authHttp.RequireAuth(useCase, keys)))
+
+	tokens := signup(t, useCase, "ana@example.com")
+
+	cases := map[string]struct {
+		header string
+		apiKey string
+		want   int
+	}{
+		"no header":       {"", "", http.StatusUnauthorized},
+		"wrong scheme":    {"Basic " + tokens.AccessToken, "", http.StatusUnauthorized},
+		"invalid token":   {"Bearer nope", "", http.StatusUnauthorized},
+		"unknown API key": {"", "lvl_nope", http.StatusUnauthorized},
+		"valid token":     {"Bearer " + tokens.AccessToken, "", http.StatusOK},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			if tc.apiKey != "" {
+				req.Header.Set(authHttp.APIKeyHeader, tc.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+			if tc.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("expected a WWW-Authenticate challenge")
+			}
+			if tc.want == http.StatusOK && !strings.Contains(rec.Body.String(), "ana@example.com") {
+				t.Errorf("expected the current client, got %s", rec.Body.String())
+			}
+		})
+	}
+}
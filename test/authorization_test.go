@@ -0,0 +1,392 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	addressHttp "github.com/Jose-Ig/lavalo-backend/internal/addresses/application/http"
+	addressModels "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	addressUsecases "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/usecases"
+	addressRepos "github.com/Jose-Ig/lavalo-backend/internal/addresses/infrastructure/repositories"
+	authHttp "github.com/Jose-Ig/lavalo-backend/internal/auth/application/http"
+	authModels "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	authUsecases "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/ratelimit"
+	authRepos "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/repositories"
+	authTokens "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/tokens"
+	clientHttp "github.com/Jose-Ig/lavalo-backend/internal/clients/application/http"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentHttp "github.com/Jose-Ig/lavalo-backend/internal/payments/application/http"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationHttp "github.com/Jose-Ig/lavalo-backend/internal/reservations/application/http"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	serviceHttp "github.com/Jose-Ig/lavalo-backend/internal/services/application/http"
+	serviceUsecases "github.com/Jose-Ig/lavalo-backend/internal/services/domain/usecases"
+	serviceRepos "github.com/Jose-Ig/lavalo-backend/internal/services/infrastructure/repositories"
+	slotHttp "github.com/Jose-Ig/lavalo-backend/internal/slots/application/http"
+	slotModels "github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	slotUsecases "github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+	slotRepos "github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/repositories"
+	waitlistHttp "github.com/Jose-Ig/lavalo-backend/internal/waitlist/application/http"
+	waitlistModels "github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/models"
+	waitlistUsecases "github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/usecases"
+	waitlistRepos "github.com/Jose-Ig/lavalo-backend/internal/waitlist/infrastructure/repositories"
+)
+
+// Callers of the API in authorization tests. Owner booked every fixture; other is another customer.
+const (
+	callerOwner = "owner"
+	callerOther = "other"
+	callerStaff = "staff"
+	callerAdmin = "admin"
+)
+
+// callerClients maps each caller to its client ID and role
+var callerClients = map[string]struct {
+	id   uint
+	role clientModels.Role
+}{
+	callerOwner: {1, clientModels.RoleCustomer},
+	callerOther: {2, clientModels.RoleCustomer},
+	callerStaff: {3, clientModels.RoleStaff},
+	callerAdmin: {4, clientModels.RoleAdmin},
+}
+
+// apiFixture holds the records owned by callerOwner
+type apiFixture struct {
+	reservationID uint
+	confirmedID   uint
+	seriesID      uint
+	entryID       uint
+	vehicleID     uint
+	addressID     uint
+	paymentID     uint
+	serviceID     uint
+	hoursID       uint
+	closureID     uint
+	apiKeyID      uint
+}
+
+// newAPITest serves every authorization-checked route over a fresh database with fixtures,
+// returning the router, the fixtures and an access token per caller
+func newAPITest(t *testing.T) (*gin.Engine, apiFixture, map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(
+		&authModels.RefreshToken{},
+		&authModels.APIKey{},
+		&addressModels.Address{},
+		&waitlistModels.WaitlistEntry{},
+		&slotModels.BusinessHours{},
+		&slotModels.Closure{},
+	); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	clients := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+	vehicles := clientUsecases.NewVehicleUseCase(clientRepos.NewVehicleRepository(db))
+	addresses := addressUsecases.NewAddressUseCase(addressRepos.NewAddressRepository(db), clients)
+	paymentRepo := paymentRepos.NewPaymentRepository(db)
+	payments := paymentUsecases.NewPaymentUseCase(paymentRepo)
+	services := serviceUsecases.NewServiceUseCase(serviceRepos.NewServiceRepository(db))
+	hours := slotUsecases.NewBusinessHoursUseCase(slotRepos.NewBusinessHoursRepository(db), slotRepos.NewSlotRepository(db))
+	closures := slotUsecases.NewClosureUseCase(slotRepos.NewClosureRepository(db))
+	reservations := newReservationUseCase(db)
+	waitlist := waitlistUsecases.NewWaitlistUseCase(waitlistRepos.NewWaitlistRepository(db), reservations, &recordingNotifier{}, time.Now)
+	_, provider := newFakeMercadoPago(t)
+	checkout := paymentUsecases.NewCheckoutUseCase(paymentRepo, provider, reservations, paymentModels.DepositPolicy{Percent: 30})
+	refunds := paymentUsecases.NewRefundUseCase(paymentRepo, paymentRepos.NewRefundRepository(db), provider)
+	webhooks := paymentUsecases.NewWebhookUseCase(paymentRepo, paymentRepos.NewWebhookEventRepository(db), provider, reservations, refunds)
+
+	signer := authTokens.NewJWTSigner([]byte("test-secret"), "lavalo-test")
+	auth := authUsecases.NewAuthUseCase(clients, authRepos.NewRefreshTokenRepository(db), signer, time.Hour, 24*time.Hour, time.Now)
+	keys := authUsecases.NewAPIKeyUseCase(authRepos.NewAPIKeyRepository(db), clients, ratelimit.NewFixedWindowLimiter(time.Minute), 60, time.Now)
+
+	tokens := make(map[string]string)
+	for caller, client := range callerClients {
+		if _, err := clients.SetRole(ctx, client.id, client.role); err != nil {
+			t.Fatalf("failed to set role: %v", err)
+		}
+		token, err := signer.Sign(authModels.AccessClaims{
+			ClientID:  client.id,
+			Role:      client.role,
+			IssuedAt:  time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		tokens[caller] = token
+	}
+
+	var f apiFixture
+	must := func(id uint, err error) uint {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to create fixture: %v", err)
+		}
+		return id
+	}
+
+	booking := reservationUsecases.CreateReservationInput{UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(9, 0)}
+	reservation, err := reservations.CreateReservation(ctx, booking)
+	f.reservationID = must(reservation.ID, err)
+
+	booking.StartTime = tomorrowAt(9, 30)
+	confirmed, err := reservations.CreateReservation(ctx, booking)
+	f.confirmedID = must(confirmed.ID, err)
+	_, err = reservations.ConfirmReservation(ctx, f.confirmedID, reservationUsecases.StatusChangeInput{ChangedBy: "staff"})
+	must(0, err)
+	must(0, db.Model(&reservationModels.Reservation{}).Where("id = ?", f.confirmedID).Updates(map[string]interface{}{"price_cents": 250000, "price_currency": "ARS"}).Error)
+
+	series, err := reservations.CreateSeries(ctx, reservationUsecases.CreateSeriesInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(15, 0), Frequency: reservationModels.RecurrenceWeekly, Count: 2,
+	})
+	f.seriesID = must(series.Series.ID, err)
+
+	entry, err := waitlist.JoinWaitlist(ctx, waitlistUsecases.JoinWaitlistInput{UserID: 1, AddressID: 1, StartTime: tomorrowAt(9, 0)})
+	f.entryID = must(entry.ID, err)
+
+	vehicle, err := vehicles.CreateVehicle(ctx, clientUsecases.VehicleInput{ClientID: 1, Plate: "AB123CD"})
+	f.vehicleID = must(vehicle.ID, err)
+
+	address, err := addresses.CreateAddress(ctx, addressUsecases.AddressInput{UserID: 1, Street: "Av. Corrientes", Number: "1234", City: "CABA"})
+	f.addressID = must(address.ID, err)
+
+	payment := paymentModels.Payment{ReservationID: f.reservationID, Amount: common.NewMoney(100000, "ARS"), Status: paymentModels.PaymentStatusCompleted}
+	must(0, db.Create(&payment).Error)
+	f.paymentID = payment.ID
+
+	service, err := services.CreateService(ctx, serviceUsecases.ServiceInput{Name: "Lavado", DurationMins: 30, BasePrice: common.NewMoney(100000, "ARS")})
+	f.serviceID = must(service.ID, err)
+
+	businessHours, err := hours.SetBusinessHours(ctx, slotUsecases.SetBusinessHoursInput{Weekday: time.Sunday, IsClosed: true})
+	f.hoursID = must(businessHours.ID, err)
+
+	closure, err := closures.CreateClosure(ctx, slotUsecases.CreateClosureInput{StartsAt: tomorrowAt(0, 0).AddDate(0, 1, 0), EndsAt: tomorrowAt(0, 0).AddDate(0, 1, 1), Reason: "Feriado"})
+	f.closureID = must(closure.ID, err)
+
+	apiKey, _, err := keys.IssueAPIKey(ctx, authUsecases.IssueAPIKeyInput{ClientID: 1, Name: "Flota", Scopes: []authModels.Scope{authModels.ScopeReservationsRead}})
+	f.apiKeyID = must(apiKey.ID, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	protected := router.Group("", authHttp.RequireAuth(auth, keys))
+	authHttp.NewAuthHandler(auth).RegisterProtectedRoutes(protected)
+	authHttp.NewAPIKeyHandler(keys).RegisterRoutes(protected)
+	reservationHttp.NewReservationHandler(reservations).RegisterRoutes(protected)
+	waitlistHttp.NewWaitlistHandler(waitlist).RegisterRoutes(protected)
+	serviceHttp.NewServiceHandler(services).RegisterRoutes(protected)
+	slotHttp.NewSlotHandler().RegisterRoutes(protected)
+	slotHttp.NewBusinessHoursHandler(hours).RegisterRoutes(protected)
+	slotHttp.NewClosureHandler(closures, time.Local).RegisterRoutes(protected)
+	clientHttp.NewClientHandler(clients).RegisterRoutes(protected)
+	clientHttp.NewVehicleHandler(vehicles).RegisterRoutes(protected)
+	addressHttp.NewAddressHandler(addresses).RegisterRoutes(protected)
+	paymentHttp.NewPaymentHandler(payments, checkout, webhooks, refunds, reservations).RegisterRoutes(protected)
+
+	return router, f, tokens
+}
+
+// routeCase is a route and the callers allowed through its policy, with the status they get.
+// Every other caller must get 403.
+type routeCase struct {
+	method  string
+	path    string
+	body    string
+	allowed map[string]int
+}
+
+// everyone allows all authenticated callers
+func everyone(status int) map[string]int {
+	return map[string]int{callerOwner: status, callerOther: status, callerStaff: status, callerAdmin: status}
+}
+
+// ownerOrStaff allows the owner of the fixtures and staff
+func ownerOrStaff(status int) map[string]int {
+	return map[string]int{callerOwner: status, callerStaff: status, callerAdmin: status}
+}
+
+// ownerOrStaffByID allows the owner of a fixture looked up by ID and staff; other customers get
+// 404, as if the ID did not exist
+func ownerOrStaffByID(status int) map[string]int {
+	allowed := ownerOrStaff(status)
+	allowed[callerOther] = http.StatusNotFound
+	return allowed
+}
+
+// staffOnly allows staff and admins
+func staffOnly(status int) map[string]int {
+	return map[string]int{callerStaff: status, callerAdmin: status}
+}
+
+// adminOnly allows admins
+func adminOnly(status int) map[string]int {
+	return map[string]int{callerAdmin: status}
+}
+
+// routeCases lists the authorization policy of every protected route
+func routeCases(f apiFixture) map[string]routeCase {
+	at := func(hour int) string {
+		return tomorrowAt(hour, 0).Format(time.RFC3339)
+	}
+	booking := func(hour int) string {
+		return fmt.Sprintf(`{"user_id":1,"slot_id":1,"address_id":1,"start_time":%q}`, at(hour))
+	}
+
+	return map[string]routeCase{
+		"me":                           {"GET", "/auth/me", "", everyone(http.StatusOK)},
+		"list API keys":                {"GET", "/api-keys", "", adminOnly(http.StatusOK)},
+		"issue API key":                {"POST", "/api-keys", `{"client_id":1,"name":"Concesionario","scopes":["availability:read"]}`, adminOnly(http.StatusCreated)},
+		"revoke API key":               {"DELETE", fmt.Sprintf("/api-keys/%d", f.apiKeyID), "", adminOnly(http.StatusNoContent)},
+		"list own reservations":        {"GET", "/reservations", "", everyone(http.StatusOK)},
+		"list client reservations":     {"GET", "/reservations?user_id=1", "", ownerOrStaff(http.StatusOK)},
+		"get reservation":              {"GET", fmt.Sprintf("/reservations/%d", f.reservationID), "", ownerOrStaffByID(http.StatusOK)},
+		"create reservation":           {"POST", "/reservations", booking(12), ownerOrStaff(http.StatusCreated)},
+		"hold reservation":             {"POST", "/reservations/holds", booking(12), ownerOrStaff(http.StatusCreated)},
+		"update reservation":           {"PUT", fmt.Sprintf("/reservations/%d", f.reservationID), "{}", staffOnly(http.StatusOK)},
+		"delete reservation":           {"DELETE", fmt.Sprintf("/reservations/%d", f.reservationID), "", adminOnly(http.StatusNoContent)},
+		"confirm reservation":          {"POST", fmt.Sprintf("/reservations/%d/confirm", f.reservationID), "", staffOnly(http.StatusOK)},
+		"cancel reservation":           {"POST", fmt.Sprintf("/reservations/%d/cancel", f.reservationID), "", ownerOrStaffByID(http.StatusOK)},
+		"complete reservation":         {"POST", fmt.Sprintf("/reservations/%d/complete", f.confirmedID), `{"override_balance":true,"reason":"Paga el lunes"}`, staffOnly(http.StatusOK)},
+		"reservation balance":          {"GET", fmt.Sprintf("/reservations/%d/balance", f.reservationID), "", ownerOrStaffByID(http.StatusOK)},
+		"reservation history":          {"GET", fmt.Sprintf("/reservations/%d/history", f.reservationID), "", ownerOrStaffByID(http.StatusOK)},
+		"reschedule reservation":       {"PUT", fmt.Sprintf("/reservations/%d/reschedule", f.reservationID), fmt.Sprintf(`{"slot_id":1,"start_time":%q}`, at(18)), ownerOrStaffByID(http.StatusOK)},
+		"reservation reschedules":      {"GET", fmt.Sprintf("/reservations/%d/reschedules", f.reservationID), "", ownerOrStaffByID(http.StatusOK)},
+		"create series":                {"POST", "/reservations/series", fmt.Sprintf(`{"user_id":1,"slot_id":1,"address_id":1,"start_time":%q,"frequency":"weekly","count":2}`, at(17)), ownerOrStaff(http.StatusCreated)},
+		"get series":                   {"GET", fmt.Sprintf("/reservations/series/%d", f.seriesID), "", ownerOrStaffByID(http.StatusOK)},
+		"cancel series":                {"POST", fmt.Sprintf("/reservations/series/%d/cancel", f.seriesID), "", ownerOrStaffByID(http.StatusOK)},
+		"list own waitlist":            {"GET", "/waitlist", "", map[string]int{callerOwner: http.StatusOK, callerOther: http.StatusOK, callerStaff: http.StatusBadRequest, callerAdmin: http.StatusBadRequest}},
+		"list client waitlist":         {"GET", "/waitlist?user_id=1", "", ownerOrStaff(http.StatusOK)},
+		"get waitlist entry":           {"GET", fmt.Sprintf("/waitlist/%d", f.entryID), "", ownerOrStaffByID(http.StatusOK)},
+		"join waitlist":                {"POST", "/waitlist", fmt.Sprintf(`{"user_id":1,"address_id":1,"start_time":%q}`, at(11)), ownerOrStaff(http.StatusCreated)},
+		"leave waitlist":               {"DELETE", fmt.Sprintf("/waitlist/%d", f.entryID), "", ownerOrStaffByID(http.StatusOK)},
+		"list services":                {"GET", "/services", "", everyone(http.StatusOK)},
+		"get service":                  {"GET", fmt.Sprintf("/services/%d", f.serviceID), "", everyone(http.StatusOK)},
+		"create service":               {"POST", "/services", `{"name":"Encerado","duration_mins":60,"base_price":{"cents":500000,"currency":"ARS"}}`, adminOnly(http.StatusCreated)},
+		"update service":               {"PUT", fmt.Sprintf("/services/%d", f.serviceID), `{"name":"Lavado","duration_mins":30,"base_price":{"cents":150000}}`, adminOnly(http.StatusOK)},
+		"delete service":               {"DELETE", fmt.Sprintf("/services/%d", f.serviceID), "", adminOnly(http.StatusNoContent)},
+		"list slots":                   {"GET", "/slots", "", everyone(http.StatusOK)},
+		"create slot":                  {"POST", "/slots", "{}", adminOnly(http.StatusCreated)},
+		"update slot":                  {"PUT", "/slots/1", "{}", adminOnly(http.StatusOK)},
+		"delete slot":                  {"DELETE", "/slots/1", "", adminOnly(http.StatusNoContent)},
+		"list business hours":          {"GET", "/business-hours", "", everyone(http.StatusOK)},
+		"set business hours":           {"PUT", "/business-hours", `{"weekday":1,"open_time":"09:00","close_time":"18:00"}`, adminOnly(http.StatusOK)},
+		"delete business hours":        {"DELETE", fmt.Sprintf("/business-hours/%d", f.hoursID), "", adminOnly(http.StatusNoContent)},
+		"list closures":                {"GET", "/closures", "", everyone(http.StatusOK)},
+		"create closure":               {"POST", "/closures", fmt.Sprintf(`{"starts_at":%q,"ends_at":%q}`, at(8), at(10)), adminOnly(http.StatusCreated)},
+		"import closures":              {"POST", "/closures/import?format=csv", "start,end,reason\n2030-01-01,2030-01-01,Feriado\n", adminOnly(http.StatusCreated)},
+		"delete closure":               {"DELETE", fmt.Sprintf("/closures/%d", f.closureID), "", adminOnly(http.StatusNoContent)},
+		"list clients":                 {"GET", "/clients", "", staffOnly(http.StatusOK)},
+		"get client":                   {"GET", "/clients/1", "", ownerOrStaff(http.StatusOK)},
+		"create client":                {"POST", "/clients", `{"name":"Walk-in","email":"walkin@example.com"}`, staffOnly(http.StatusCreated)},
+		"update client":                {"PUT", "/clients/1", `{"name":"Client 1","email":"client1@example.com"}`, ownerOrStaff(http.StatusOK)},
+		"delete client":                {"DELETE", "/clients/5", "", adminOnly(http.StatusNoContent)},
+		"set client role":              {"PUT", "/clients/5/role", `{"role":"staff"}`, adminOnly(http.StatusOK)},
+		"list own vehicles":            {"GET", "/vehicles", "", map[string]int{callerOwner: http.StatusOK, callerOther: http.StatusOK, callerStaff: http.StatusBadRequest, callerAdmin: http.StatusBadRequest}},
+		"list client vehicles":         {"GET", "/vehicles?client_id=1", "", ownerOrStaff(http.StatusOK)},
+		"get vehicle":                  {"GET", fmt.Sprintf("/vehicles/%d", f.vehicleID), "", ownerOrStaffByID(http.StatusOK)},
+		"create vehicle":               {"POST", "/vehicles", `{"client_id":1,"plate":"AC456DE"}`, ownerOrStaff(http.StatusCreated)},
+		"update vehicle":               {"PUT", fmt.Sprintf("/vehicles/%d", f.vehicleID), `{"client_id":1,"plate":"AB123CD","size":"suv"}`, ownerOrStaffByID(http.StatusOK)},
+		"delete vehicle":               {"DELETE", fmt.Sprintf("/vehicles/%d", f.vehicleID), "", ownerOrStaffByID(http.StatusNoContent)},
+		"list own addresses":           {"GET", "/addresses", "", map[string]int{callerOwner: http.StatusOK, callerOther: http.StatusOK, callerStaff: http.StatusBadRequest, callerAdmin: http.StatusBadRequest}},
+		"list client addresses":        {"GET", "/addresses?user_id=1", "", ownerOrStaff(http.StatusOK)},
+		"get address":                  {"GET", fmt.Sprintf("/addresses/%d", f.addressID), "", ownerOrStaffByID(http.StatusOK)},
+		"create address":               {"POST", "/addresses", `{"user_id":1,"street":"Florida","city":"CABA"}`, ownerOrStaff(http.StatusCreated)},
+		"update address":               {"PUT", fmt.Sprintf("/addresses/%d", f.addressID), `{"user_id":1,"street":"Florida","city":"CABA"}`, ownerOrStaffByID(http.StatusOK)},
+		"delete address":               {"DELETE", fmt.Sprintf("/addresses/%d", f.addressID), "", ownerOrStaffByID(http.StatusNoContent)},
+		"list own payments":            {"GET", "/payments", "", map[string]int{callerOwner: http.StatusOK, callerOther: http.StatusOK, callerStaff: http.StatusBadRequest, callerAdmin: http.StatusBadRequest}},
+		"list client payments":         {"GET", "/payments?user_id=1", "", ownerOrStaff(http.StatusOK)},
+		"list reservation payments":    {"GET", fmt.Sprintf("/payments?reservation_id=%d", f.reservationID), "", ownerOrStaffByID(http.StatusOK)},
+		"get payment":                  {"GET", fmt.Sprintf("/payments/%d", f.paymentID), "", ownerOrStaffByID(http.StatusOK)},
+		"create payment":               {"POST", "/payments", fmt.Sprintf(`{"reservation_id":%d}`, f.confirmedID), ownerOrStaffByID(http.StatusCreated)},
+		"create deposit payment":       {"POST", "/payments", fmt.Sprintf(`{"reservation_id":%d,"deposit":true}`, f.confirmedID), ownerOrStaffByID(http.StatusCreated)},
+		"record cash payment":          {"POST", "/payments/cash", fmt.Sprintf(`{"reservation_id":%d,"amount":{"cents":100000}}`, f.confirmedID), staffOnly(http.StatusCreated)},
+		"list payment refunds":         {"GET", fmt.Sprintf("/payments/%d/refunds", f.paymentID), "", ownerOrStaffByID(http.StatusOK)},
+		"refund payment":               {"POST", fmt.Sprintf("/payments/%d/refunds", f.paymentID), `{"amount":{"cents":50000},"reason":"Lavado incompleto"}`, staffOnly(http.StatusCreated)},
+		"transfer vehicle to stranger": {"PUT", fmt.Sprintf("/vehicles/%d", f.vehicleID), `{"client_id":2,"plate":"AB123CD"}`, map[string]int{callerOther: http.StatusNotFound, callerStaff: http.StatusOK, callerAdmin: http.StatusOK}},
+	}
+}
+
+func TestAuthorization_Routes(t *testing.T) {
+	shared, f, tokens := newAPITest(t)
+
+	for name, route := range routeCases(f) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for caller := range callerClients {
+				want, ok := route.allowed[caller]
+				if !ok {
+					want = http.StatusForbidden
+				}
+
+				t.Run(caller, func(t *testing.T) {
+					t.Parallel()
+					// Allowed writes change state, so they get a fresh API; reads and denied requests share one
+					router := shared
+					if ok && route.method != http.MethodGet {
+						router, _, _ = newAPITest(t)
+					}
+
+					req := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+					req.Header.Set("Authorization", "Bearer "+tokens[caller])
+					if route.body != "" {
+						req.Header.Set("Content-Type", "application/json")
+					}
+
+					rec := httptest.NewRecorder()
+					router.ServeHTTP(rec, req)
+
+					if rec.Code != want {
+						t.Errorf("%s %s as %s: expected %d, got %d: %s", route.method, route.path, caller, want, rec.Code, rec.Body.String())
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestAuthorization_ListsOnlyOwnRecords(t *testing.T) {
+	router, _, tokens := newAPITest(t)
+
+	get := func(path, caller string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens[caller])
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s as %s: expected 200, got %d", path, caller, rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	for _, path := range []string{"/reservations", "/addresses", "/payments", "/vehicles", "/waitlist"} {
+		// The other customer sees at most their own seeded address
+		if body := get(path, callerOther); strings.Contains(body, `"id":1,`) {
+			t.Errorf("GET %s: expected another customer not to see the owner's records, got %s", path, body)
+		}
+		if body := get(path, callerOwner); !strings.Contains(body, `"id":1`) {
+			t.Errorf("GET %s: expected the owner to see their records, got %s", path, body)
+		}
+	}
+
+	// Staff without a client get the day's schedule
+	if body := get("/reservations?date="+tomorrowAt(0, 0).Format("2006-01-02"), callerStaff); strings.Count(body, `"user_id":1`) != 3 {
+		t.Errorf("expected tomorrow's schedule to hold 3 reservations, got %s", body)
+	}
+}
@@ -2,10 +2,13 @@ package test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
 	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
 	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
 	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
 )
@@ -14,23 +17,63 @@ import (
 type mockAvailabilityRepository struct {
 	slots        []models.Slot
 	reservations []reservationModels.Reservation
+	services     []serviceModels.Service
+	hours        []models.BusinessHours
+	closures     []models.Closure
 }
 
 func (m *mockAvailabilityRepository) FindAllSlots(ctx context.Context) ([]models.Slot, error) {
 	return m.slots, nil
 }
 
-func (m *mockAvailabilityRepository) FindReservationsByDateRange(ctx context.Context, start, end time.Time) ([]reservationModels.Reservation, error) {
+func (m *mockAvailabilityRepository) FindReservationsByDateRange(ctx context.Context, start, end, now time.Time) ([]reservationModels.Reservation, error) {
 	return m.reservations, nil
 }
 
+func (m *mockAvailabilityRepository) FindBusinessHours(ctx context.Context) ([]models.BusinessHours, error) {
+	return m.hours, nil
+}
+
+func (m *mockAvailabilityRepository) FindClosuresByDateRange(ctx context.Context, start, end time.Time) ([]models.Closure, error) {
+	var result []models.Closure
+	for _, c := range m.closures {
+		if c.Overlaps(start, end) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAvailabilityRepository) FindServiceByID(ctx context.Context, id uint) (*serviceModels.Service, error) {
+	for i := range m.services {
+		if m.services[i].ID == id {
+			return &m.services[i], nil
+		}
+	}
+	return nil, common.ErrNotFound
+}
+
+// testNow is the fixed current time of availability tests: Monday 2026-03-02, before opening
+var testNow = time.Date(2026, 3, 2, 6, 0, 0, 0, time.Local)
+
+// fixedClock returns a clock that always reports now
+func fixedClock(now time.Time) common.Clock {
+	return func() time.Time { return now }
+}
+
+// newAvailabilityUseCase creates an availability use case in the local zone,
+// without lead time and with the clock fixed at testNow
+func newAvailabilityUseCase(repo usecases.AvailabilityRepository) *usecases.AvailabilityUseCase {
+	return usecases.NewAvailabilityUseCase(repo, time.Local, 0, fixedClock(testNow))
+}
+
 func TestGetWeekAvailability_NoSlots(t *testing.T) {
 	repo := &mockAvailabilityRepository{
 		slots:        []models.Slot{},
 		reservations: []reservationModels.Reservation{},
 	}
 
-	uc := usecases.NewAvailabilityUseCase(repo)
+	uc := newAvailabilityUseCase(repo)
 
 	_, err := uc.GetWeekAvailability(context.Background())
 	if err == nil {
@@ -47,7 +90,7 @@ func TestGetWeekAvailability_AllAvailable(t *testing.T) {
 		reservations: []reservationModels.Reservation{},
 	}
 
-	uc := usecases.NewAvailabilityUseCase(repo)
+	uc := newAvailabilityUseCase(repo)
 
 	availability, err := uc.GetWeekAvailability(context.Background())
 	if err != nil {
@@ -60,7 +103,7 @@ func TestGetWeekAvailability_AllAvailable(t *testing.T) {
 	}
 
 	// Check today's availability
-	today := time.Now().Format("2006-01-02")
+	today := testNow.Format("2006-01-02")
 	dayAvail, ok := availability[today]
 	if !ok {
 		t.Fatalf("expected availability for today (%s), not found", today)
@@ -93,7 +136,7 @@ func TestGetWeekAvailability_AllAvailable(t *testing.T) {
 }
 
 func TestGetWeekAvailability_WithReservations(t *testing.T) {
-	now := time.Now()
+	now := testNow
 	today := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, now.Location())
 
 	repo := &mockAvailabilityRepository{
@@ -111,7 +154,7 @@ func TestGetWeekAvailability_WithReservations(t *testing.T) {
 		},
 	}
 
-	uc := usecases.NewAvailabilityUseCase(repo)
+	uc := newAvailabilityUseCase(repo)
 
 	availability, err := uc.GetWeekAvailability(context.Background())
 	if err != nil {
@@ -140,7 +183,7 @@ func TestGetWeekAvailability_WithReservations(t *testing.T) {
 }
 
 func TestGetWeekAvailability_HourBlockedWhenAllSlotsReserved(t *testing.T) {
-	now := time.Now()
+	now := testNow
 	today := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, now.Location())
 
 	repo := &mockAvailabilityRepository{
@@ -164,7 +207,7 @@ func TestGetWeekAvailability_HourBlockedWhenAllSlotsReserved(t *testing.T) {
 		},
 	}
 
-	uc := usecases.NewAvailabilityUseCase(repo)
+	uc := newAvailabilityUseCase(repo)
 
 	availability, err := uc.GetWeekAvailability(context.Background())
 	if err != nil {
@@ -188,7 +231,7 @@ func TestGetWeekAvailability_HourBlockedWhenAllSlotsReserved(t *testing.T) {
 }
 
 func TestGetWeekAvailability_CancelledReservationsIgnored(t *testing.T) {
-	now := time.Now()
+	now := testNow
 	today := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, now.Location())
 
 	repo := &mockAvailabilityRepository{
@@ -205,7 +248,7 @@ func TestGetWeekAvailability_CancelledReservationsIgnored(t *testing.T) {
 		},
 	}
 
-	uc := usecases.NewAvailabilityUseCase(repo)
+	uc := newAvailabilityUseCase(repo)
 
 	availability, err := uc.GetWeekAvailability(context.Background())
 	if err != nil {
@@ -222,3 +265,427 @@ func TestGetWeekAvailability_CancelledReservationsIgnored(t *testing.T) {
 	}
 }
 
+// hourAvailable returns the availability of a given hour value in a day
+func hourAvailable(t *testing.T, day models.DayAvailability, value string) bool {
+	t.Helper()
+	for _, hour := range day.Hours {
+		if hour.Value == value {
+			return hour.IsAvailable
+		}
+	}
+	t.Fatalf("hour %s not found", value)
+	return false
+}
+
+func TestGetWeekAvailability_LongReservationBlocksEveryCell(t *testing.T) {
+	now := testNow
+	start := time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, now.Location())
+
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+		reservations: []reservationModels.Reservation{
+			{
+				ID:        1,
+				SlotID:    1,
+				StartTime: start,
+				EndTime:   start.Add(2 * time.Hour),
+				Status:    reservationModels.ReservationStatusConfirmed,
+			},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+
+	availability, err := uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dayAvail := availability[start.Format("2006-01-02")]
+
+	for _, hour := range []string{"10:00", "10:30", "11:00", "11:30"} {
+		if hourAvailable(t, dayAvail, hour) {
+			t.Errorf("%s should NOT be available (covered by a 2 hour reservation)", hour)
+		}
+	}
+	for _, hour := range []string{"09:30", "12:00"} {
+		if !hourAvailable(t, dayAvail, hour) {
+			t.Errorf("%s should be available", hour)
+		}
+	}
+}
+
+func TestGetWeekAvailabilityForService_OnlyOffersStartsThatFit(t *testing.T) {
+	now := testNow
+	booked := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+		reservations: []reservationModels.Reservation{
+			{
+				ID:        1,
+				SlotID:    1,
+				StartTime: booked,
+				Status:    reservationModels.ReservationStatusConfirmed,
+			},
+		},
+		services: []serviceModels.Service{
+			{ID: 7, Name: "Detallado completo", DurationMins: 120, IsActive: true},
+			{ID: 8, Name: "Encerado", DurationMins: 60, IsActive: false},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+
+	availability, err := uc.GetWeekAvailabilityForService(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dayAvail := availability[booked.Format("2006-01-02")]
+
+	// 10:00-12:00 ends right when the existing reservation starts
+	if !hourAvailable(t, dayAvail, "10:00") {
+		t.Error("10:00 should be available for a 2 hour service")
+	}
+	// Starts between 10:30 and 12:00 would overlap the 12:00 reservation
+	for _, hour := range []string{"10:30", "11:00", "11:30", "12:00"} {
+		if hourAvailable(t, dayAvail, hour) {
+			t.Errorf("%s should NOT be available for a 2 hour service", hour)
+		}
+	}
+	// The last start that ends by 22:00 is 20:00
+	if !hourAvailable(t, dayAvail, "20:00") {
+		t.Error("20:00 should be available for a 2 hour service")
+	}
+	if hourAvailable(t, dayAvail, "20:30") {
+		t.Error("20:30 should NOT be available (2 hour service would end after closing)")
+	}
+
+	if _, err := uc.GetWeekAvailabilityForService(context.Background(), 8); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for inactive service, got %v", err)
+	}
+	if _, err := uc.GetWeekAvailabilityForService(context.Background(), 99); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for unknown service, got %v", err)
+	}
+}
+
+func TestGetWeekAvailability_BusinessHoursPerWeekdayAndSlot(t *testing.T) {
+	slotID := uint(2)
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+			{ID: 2, Label: "Espacio 2", IsAvailable: true},
+		},
+		hours: []models.BusinessHours{
+			{Weekday: time.Sunday, IsClosed: true},
+			{Weekday: time.Saturday, OpenTime: "09:00", CloseTime: "14:00"},
+			// Slot 2 stays open until 16:00 on Saturdays
+			{SlotID: &slotID, Weekday: time.Saturday, OpenTime: "09:00", CloseTime: "16:00"},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+
+	availability, err := uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checked := map[time.Weekday]bool{}
+	for dateKey, dayAvail := range availability {
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil {
+			t.Fatalf("invalid date key %s", dateKey)
+		}
+
+		switch date.Weekday() {
+		case time.Sunday:
+			checked[time.Sunday] = true
+			if len(dayAvail.Hours) != 0 {
+				t.Errorf("expected no hours on Sunday, got %d", len(dayAvail.Hours))
+			}
+			for _, slot := range dayAvail.Slots {
+				if slot.IsAvailable {
+					t.Errorf("slot %d should not be available on Sunday", slot.ID)
+				}
+			}
+		case time.Saturday:
+			checked[time.Saturday] = true
+			// 09:00-16:00 in 30 minute steps from the union of both slots
+			if len(dayAvail.Hours) != 14 {
+				t.Errorf("expected 14 hours on Saturday, got %d", len(dayAvail.Hours))
+			}
+			if dayAvail.Hours[0].Value != "09:00" {
+				t.Errorf("expected Saturday to open at 09:00, got %s", dayAvail.Hours[0].Value)
+			}
+			if !hourAvailable(t, dayAvail, "15:30") {
+				t.Error("15:30 should be available on Saturday (slot 2 override)")
+			}
+		case time.Monday:
+			checked[time.Monday] = true
+			if len(dayAvail.Hours) != 28 {
+				t.Errorf("expected default 28 hours on Monday, got %d", len(dayAvail.Hours))
+			}
+		}
+	}
+
+	for _, day := range []time.Weekday{time.Sunday, time.Saturday, time.Monday} {
+		if !checked[day] {
+			t.Errorf("expected %s within the 8 day window", day)
+		}
+	}
+}
+
+func TestValidateStartTime_UsesBusinessHours(t *testing.T) {
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+		hours: []models.BusinessHours{
+			{Weekday: time.Sunday, IsClosed: true},
+			{Weekday: time.Saturday, OpenTime: "09:00", CloseTime: "14:00"},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+	ctx := context.Background()
+
+	// 2026-03-07 is a Saturday, 2026-03-08 a Sunday
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 7, hour, minute, 0, 0, time.Local)
+	}
+
+	if err := uc.ValidateStartTime(ctx, 1, saturday(9, 0), time.Hour); err != nil {
+		t.Errorf("expected Saturday 09:00 to be bookable, got %v", err)
+	}
+	if err := uc.ValidateStartTime(ctx, 1, saturday(8, 30), 30*time.Minute); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected Saturday 08:30 to be rejected, got %v", err)
+	}
+	if err := uc.ValidateStartTime(ctx, 1, saturday(13, 30), time.Hour); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected a booking past 14:00 to be rejected, got %v", err)
+	}
+	sunday := time.Date(2026, 3, 8, 10, 0, 0, 0, time.Local)
+	if err := uc.ValidateStartTime(ctx, 1, sunday, 30*time.Minute); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected Sunday to be rejected, got %v", err)
+	}
+}
+
+func TestGetAvailability_DateRangeAndSlotFilter(t *testing.T) {
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+			{ID: 2, Label: "Espacio 2", IsAvailable: true},
+			{ID: 3, Label: "Espacio 3", IsAvailable: true},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+
+	from := time.Date(2026, 3, 4, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 2)
+
+	result, err := uc.GetAvailability(context.Background(), usecases.AvailabilityQuery{
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		SlotIDs: []uint{3, 1, 3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(result))
+	}
+	for d := 0; d < 3; d++ {
+		dateKey := from.AddDate(0, 0, d).Format("2006-01-02")
+		dayAvail, ok := result[dateKey]
+		if !ok {
+			t.Fatalf("missing date %s", dateKey)
+		}
+		if len(dayAvail.Slots) != 2 || dayAvail.Slots[0].ID != 3 || dayAvail.Slots[1].ID != 1 {
+			t.Errorf("expected slots 3 and 1 on %s, got %+v", dateKey, dayAvail.Slots)
+		}
+	}
+}
+
+func TestGetAvailability_InvalidQuery(t *testing.T) {
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+
+	today := time.Date(testNow.Year(), testNow.Month(), testNow.Day(), 0, 0, 0, 0, time.Local)
+	date := func(days int) string {
+		return today.AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	tests := []struct {
+		name  string
+		query usecases.AvailabilityQuery
+	}{
+		{"malformed from", usecases.AvailabilityQuery{From: "2026/03/01"}},
+		{"malformed to", usecases.AvailabilityQuery{To: "tomorrow"}},
+		{"from in the past", usecases.AvailabilityQuery{From: date(-1)}},
+		{"to before from", usecases.AvailabilityQuery{From: date(3), To: date(2)}},
+		{"span too long", usecases.AvailabilityQuery{From: date(0), To: date(31)}},
+		{"unknown slot", usecases.AvailabilityQuery{SlotIDs: []uint{1, 9}}},
+		{"unknown service", usecases.AvailabilityQuery{ServiceID: 42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.GetAvailability(context.Background(), tt.query)
+			if !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+
+	// The longest allowed range is accepted
+	if _, err := uc.GetAvailability(context.Background(), usecases.AvailabilityQuery{From: date(0), To: date(30)}); err != nil {
+		t.Errorf("expected a 31 day range to be accepted, got %v", err)
+	}
+}
+
+func TestGetWeekAvailability_HidesPastHoursAndLeadTime(t *testing.T) {
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+	}
+
+	// At 18:10 with a one hour lead time, the first bookable start is 19:30
+	now := time.Date(2026, 3, 2, 18, 10, 0, 0, time.Local)
+	uc := usecases.NewAvailabilityUseCase(repo, time.Local, time.Hour, fixedClock(now))
+
+	availability, err := uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	today := availability["2026-03-02"]
+	for _, hour := range []string{"08:00", "18:00", "19:00"} {
+		if hourAvailable(t, today, hour) {
+			t.Errorf("%s should NOT be available (past or inside the lead time)", hour)
+		}
+	}
+	for _, hour := range []string{"19:30", "21:30"} {
+		if !hourAvailable(t, today, hour) {
+			t.Errorf("%s should be available", hour)
+		}
+	}
+
+	if !hourAvailable(t, availability["2026-03-03"], "08:00") {
+		t.Error("08:00 tomorrow should be available")
+	}
+}
+
+func TestValidateStartTime_RejectsPastAndLeadTime(t *testing.T) {
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+	}
+
+	now := time.Date(2026, 3, 2, 18, 10, 0, 0, time.Local)
+	uc := usecases.NewAvailabilityUseCase(repo, time.Local, time.Hour, fixedClock(now))
+	ctx := context.Background()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 2, hour, minute, 0, 0, time.Local)
+	}
+
+	for _, start := range []time.Time{at(10, 0), at(18, 0), at(19, 0)} {
+		if err := uc.ValidateStartTime(ctx, 1, start, 30*time.Minute); !errors.Is(err, common.ErrInvalidInput) {
+			t.Errorf("expected %s to be rejected, got %v", start.Format("15:04"), err)
+		}
+	}
+	if err := uc.ValidateStartTime(ctx, 1, at(19, 30), 30*time.Minute); err != nil {
+		t.Errorf("expected 19:30 to be bookable, got %v", err)
+	}
+}
+
+func TestGetAvailability_MatrixView(t *testing.T) {
+	slotID := uint(3)
+	start := time.Date(2026, 3, 3, 10, 0, 0, 0, time.Local)
+
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+			{ID: 2, Label: "Espacio 2", IsAvailable: true},
+			{ID: 3, Label: "Espacio 3", IsAvailable: true},
+		},
+		reservations: []reservationModels.Reservation{
+			{ID: 1, SlotID: 1, StartTime: start, EndTime: start.Add(time.Hour), Status: reservationModels.ReservationStatusConfirmed},
+		},
+		hours: []models.BusinessHours{
+			// Slot 3 only opens at 10:30 on Tuesdays
+			{SlotID: &slotID, Weekday: time.Tuesday, OpenTime: "10:30", CloseTime: "22:00"},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+	ctx := context.Background()
+
+	result, err := uc.GetAvailability(ctx, usecases.AvailabilityQuery{From: "2026-03-03", To: "2026-03-03", View: models.AvailabilityViewMatrix})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	matrix := make(map[string]models.HourSlots)
+	for _, cell := range result["2026-03-03"].Matrix {
+		matrix[cell.Value] = cell
+	}
+
+	tests := []struct {
+		hour      string
+		free      []uint
+		remaining int
+		capacity  int
+	}{
+		{"10:00", []uint{2}, 1, 2},
+		{"10:30", []uint{2, 3}, 2, 3},
+		{"11:00", []uint{1, 2, 3}, 3, 3},
+	}
+
+	for _, tt := range tests {
+		cell, ok := matrix[tt.hour]
+		if !ok {
+			t.Fatalf("hour %s missing from matrix", tt.hour)
+		}
+		if cell.Remaining != tt.remaining || cell.Capacity != tt.capacity {
+			t.Errorf("%s: expected %d/%d remaining, got %d/%d", tt.hour, tt.remaining, tt.capacity, cell.Remaining, cell.Capacity)
+		}
+		if len(cell.FreeSlotIDs) != len(tt.free) {
+			t.Errorf("%s: expected free slots %v, got %v", tt.hour, tt.free, cell.FreeSlotIDs)
+			continue
+		}
+		for i := range tt.free {
+			if cell.FreeSlotIDs[i] != tt.free[i] {
+				t.Errorf("%s: expected free slots %v, got %v", tt.hour, tt.free, cell.FreeSlotIDs)
+				break
+			}
+		}
+	}
+
+	// The summary view omits the matrix
+	summary, err := uc.GetAvailability(ctx, usecases.AvailabilityQuery{From: "2026-03-03", To: "2026-03-03"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary["2026-03-03"].Matrix != nil {
+		t.Error("expected no matrix in the summary view")
+	}
+
+	if _, err := uc.GetAvailability(ctx, usecases.AvailabilityQuery{View: "grid"}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for an unknown view, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+	slotRepos "github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/repositories"
+)
+
+func TestSetBusinessHours_UpsertsPerWeekdayAndSlot(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.BusinessHours{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	uc := usecases.NewBusinessHoursUseCase(slotRepos.NewBusinessHoursRepository(db), slotRepos.NewSlotRepository(db))
+	ctx := context.Background()
+
+	slot := models.Slot{Label: "Espacio 1", IsAvailable: true}
+	if err := db.Create(&slot).Error; err != nil {
+		t.Fatalf("failed to seed slot: %v", err)
+	}
+	slotID := slot.ID
+	inputs := []usecases.SetBusinessHoursInput{
+		{Weekday: time.Saturday, OpenTime: "09:00", CloseTime: "13:00"},
+		{Weekday: time.Saturday, OpenTime: "09:00", CloseTime: "14:00"},
+		{SlotID: &slotID, Weekday: time.Saturday, OpenTime: "10:00", CloseTime: "12:00"},
+		{Weekday: time.Sunday, IsClosed: true},
+	}
+	for _, input := range inputs {
+		if _, err := uc.SetBusinessHours(ctx, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	hours, err := uc.ListBusinessHours(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hours) != 3 {
+		t.Fatalf("expected 3 rows (Saturday replaced), got %d", len(hours))
+	}
+
+	for _, h := range hours {
+		if h.SlotID == nil && h.Weekday == time.Saturday && h.CloseTime != "14:00" {
+			t.Errorf("expected Saturday to close at 14:00, got %s", h.CloseTime)
+		}
+	}
+}
+
+func TestSetBusinessHours_InvalidInput(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.BusinessHours{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	uc := usecases.NewBusinessHoursUseCase(slotRepos.NewBusinessHoursRepository(db), slotRepos.NewSlotRepository(db))
+
+	unknownSlot := uint(999)
+	cases := map[string]usecases.SetBusinessHoursInput{
+		"bad weekday":      {Weekday: 7, OpenTime: "09:00", CloseTime: "14:00"},
+		"bad format":       {Weekday: time.Monday, OpenTime: "9:00", CloseTime: "14:00"},
+		"open after close": {Weekday: time.Monday, OpenTime: "15:00", CloseTime: "14:00"},
+		"off grid":         {Weekday: time.Monday, OpenTime: "09:15", CloseTime: "14:00"},
+		"unknown slot":     {SlotID: &unknownSlot, Weekday: time.Monday, OpenTime: "09:00", CloseTime: "14:00"},
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.SetBusinessHours(context.Background(), input); !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
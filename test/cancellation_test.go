@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+func TestCancellationPolicy_RefundPercent(t *testing.T) {
+	policy := reservationModels.CancellationPolicy{
+		FreeCancellationWindow:     24 * time.Hour,
+		LateCancellationFeePercent: 50,
+	}
+	start := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		cancelledAt time.Time
+		expected    int
+	}{
+		{"well before window", start.Add(-72 * time.Hour), 100},
+		{"exactly at cutoff", start.Add(-24 * time.Hour), 100},
+		{"inside window", start.Add(-2 * time.Hour), 50},
+		{"at start time (no-show)", start, 0},
+		{"after start time (no-show)", start.Add(time.Hour), 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.RefundPercent(start, tc.cancelledAt); got != tc.expected {
+				t.Errorf("expected %d%%, got %d%%", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCancelReservation_RefundsPayments(t *testing.T) {
+	cases := []struct {
+		name           string
+		startsIn       time.Duration
+		expectedRefund int64
+		expectedStatus paymentModels.PaymentStatus
+	}{
+		{"free cancellation", 72 * time.Hour, 100000, paymentModels.PaymentStatusRefunded},
+		{"late cancellation", 2 * time.Hour, 50000, paymentModels.PaymentStatusPartiallyRefunded},
+		{"no-show", -time.Hour, 0, paymentModels.PaymentStatusCompleted},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			uc := newReservationUseCase(db)
+			ctx := context.Background()
+
+			reservation := reservationModels.Reservation{
+				UserID:    1,
+				SlotID:    1,
+				AddressID: 1,
+				StartTime: time.Now().Add(tc.startsIn),
+				Status:    reservationModels.ReservationStatusConfirmed,
+			}
+			if err := db.Create(&reservation).Error; err != nil {
+				t.Fatalf("failed to seed reservation: %v", err)
+			}
+
+			payment := paymentModels.Payment{
+				ReservationID: reservation.ID,
+				Amount:        common.NewMoney(100000, "ARS"),
+				Status:        paymentModels.PaymentStatusCompleted,
+			}
+			if err := db.Create(&payment).Error; err != nil {
+				t.Fatalf("failed to seed payment: %v", err)
+			}
+
+			result, err := uc.CancelReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.RefundAmount.Cents != tc.expectedRefund {
+				t.Errorf("expected refund %d cents, got %s", tc.expectedRefund, result.RefundAmount)
+			}
+			if fee, err := result.PaidAmount.Sub(result.RefundAmount); err != nil || !fee.Equal(result.FeeAmount) {
+				t.Errorf("fee %s does not match paid %s minus refund %s", result.FeeAmount, result.PaidAmount, result.RefundAmount)
+			}
+
+			var stored paymentModels.Payment
+			if err := db.First(&stored, payment.ID).Error; err != nil {
+				t.Fatalf("failed to reload payment: %v", err)
+			}
+			if stored.Status != tc.expectedStatus {
+				t.Errorf("expected payment status %s, got %s", tc.expectedStatus, stored.Status)
+			}
+			if stored.RefundedAmount.Cents != tc.expectedRefund {
+				t.Errorf("expected refunded amount %d cents, got %s", tc.expectedRefund, stored.RefundedAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	addressModels "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	addressUsecases "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/usecases"
+	addressRepos "github.com/Jose-Ig/lavalo-backend/internal/addresses/infrastructure/repositories"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+func TestCreateClient(t *testing.T) {
+	uc := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(newTestDB(t)))
+	ctx := context.Background()
+
+	client, err := uc.CreateClient(ctx, clientUsecases.ClientInput{
+		Name:           " Ana Pérez ",
+		Email:          "Ana@Example.com",
+		Phone:          "+54 11 5555-0000",
+		DocumentNumber: "30.123.456",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.Name != "Ana Pérez" || client.Email != "ana@example.com" {
+		t.Errorf("expected normalized name and email, got %q %q", client.Name, client.Email)
+	}
+	if client.DocumentNumber == nil || *client.DocumentNumber != "30123456" {
+		t.Errorf("expected the DNI without dots, got %v", client.DocumentNumber)
+	}
+
+	// Clients without a DNI do not collide on the unique index
+	if _, err := uc.CreateClient(ctx, clientUsecases.ClientInput{Name: "Sin DNI", Email: "sin-dni@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, input := range map[string]clientUsecases.ClientInput{
+		"same email": {Name: "Otra Ana", Email: "ana@example.com"},
+		"same DNI":   {Name: "Otra Ana", Email: "otra@example.com", DocumentNumber: "30123456"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.CreateClient(ctx, input); !errors.Is(err, common.ErrConflict) {
+				t.Errorf("expected ErrConflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateClient_Validation(t *testing.T) {
+	uc := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(newTestDB(t)))
+
+	cases := map[string]clientUsecases.ClientInput{
+		"missing name":  {Email: "ana@example.com"},
+		"invalid email": {Name: "Ana", Email: "ana"},
+		"display name":  {Name: "Ana", Email: "Ana <ana@example.com>"},
+		"short DNI":     {Name: "Ana", Email: "ana@example.com", DocumentNumber: "123"},
+		"letters DNI":   {Name: "Ana", Email: "ana@example.com", DocumentNumber: "30ABC456"},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.CreateClient(context.Background(), input); !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteClient(t *testing.T) {
+	uc := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(newTestDB(t)))
+	ctx := context.Background()
+
+	updated, err := uc.UpdateClient(ctx, 1, clientUsecases.ClientInput{Name: "Renamed", Email: "renamed@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Name != "Renamed" {
+		t.Errorf("expected the new name, got %q", updated.Name)
+	}
+
+	if _, err := uc.UpdateClient(ctx, 1, clientUsecases.ClientInput{Name: "Renamed", Email: "client2@example.com"}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected taking another client's email to conflict, got %v", err)
+	}
+
+	if err := uc.DeleteClient(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.GetClient(ctx, 1); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := uc.DeleteClient(ctx, 999); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClientForeignKeys(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&addressModels.Address{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err := newReservationUseCase(db).CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    999,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+	})
+	if !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected a reservation for an unknown client to be rejected, got %v", err)
+	}
+
+	addresses := addressRepos.NewAddressRepository(db)
+	if err := addresses.Create(ctx, &addressModels.Address{UserID: 999, Street: "Corrientes", City: "CABA"}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected an address for an unknown client to be rejected, got %v", err)
+	}
+	if err := addresses.Create(ctx, &addressModels.Address{UserID: 1, Street: "Corrientes", City: "CABA"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeletedClient(t *testing.T) {
+	db := newTestDB(t)
+	clients := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+	addresses := addressUsecases.NewAddressUseCase(addressRepos.NewAddressRepository(db), clients)
+	ctx := context.Background()
+
+	client, err := clients.CreateClient(ctx, clientUsecases.ClientInput{Name: "Ana Pérez", Email: "ana@example.com", DocumentNumber: "30123456"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	address, err := addresses.CreateAddress(ctx, addressUsecases.AddressInput{UserID: client.ID, Street: "Corrientes", City: "CABA"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := clients.DeleteClient(ctx, client.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The row is kept, so the foreign keys alone would still accept the deleted client
+	_, err = newReservationUseCase(db).CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    client.ID,
+		SlotID:    1,
+		AddressID: address.ID,
+		StartTime: tomorrowAt(10, 0),
+	})
+	if !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected a reservation for a deleted client to be rejected, got %v", err)
+	}
+	if _, err := addresses.CreateAddress(ctx, addressUsecases.AddressInput{UserID: client.ID, Street: "Florida", City: "CABA"}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected an address for a deleted client to be rejected, got %v", err)
+	}
+	if _, err := addresses.UpdateAddress(ctx, address.ID, addressUsecases.AddressInput{UserID: client.ID, Street: "Florida", City: "CABA"}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected updating a deleted client's address to be rejected, got %v", err)
+	}
+
+	// The same person can register again with their email and DNI
+	again, err := clients.CreateClient(ctx, clientUsecases.ClientInput{Name: "Ana Pérez", Email: "ana@example.com", DocumentNumber: "30.123.456"})
+	if err != nil {
+		t.Fatalf("expected the deleted client's email and DNI to be reusable, got %v", err)
+	}
+	if again.ID == client.ID {
+		t.Errorf("expected a new client, got the deleted one")
+	}
+	if _, err := clients.CreateClient(ctx, clientUsecases.ClientInput{Name: "Otra Ana", Email: "ana@example.com"}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected the email to stay unique among clients that were not deleted, got %v", err)
+	}
+}
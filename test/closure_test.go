@@ -0,0 +1,199 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/importers"
+	slotRepos "github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/repositories"
+)
+
+func TestGetWeekAvailability_ClosuresBlockGrid(t *testing.T) {
+	now := testNow
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	slotID := uint(2)
+
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+			{ID: 2, Label: "Espacio 2", IsAvailable: true},
+		},
+		closures: []models.Closure{
+			// Whole business closed tomorrow
+			{StartsAt: tomorrow, EndsAt: tomorrow.AddDate(0, 0, 1), Reason: "Feriado"},
+			// Slot 2 in maintenance until 10:00 the day after
+			{SlotID: &slotID, StartsAt: tomorrow.AddDate(0, 0, 1), EndsAt: tomorrow.AddDate(0, 0, 1).Add(10 * time.Hour), Reason: "Mantenimiento"},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+
+	availability, err := uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	holiday := availability[tomorrow.Format("2006-01-02")]
+	for _, hour := range holiday.Hours {
+		if hour.IsAvailable {
+			t.Errorf("%s should NOT be available on a holiday", hour.Value)
+		}
+	}
+	for _, slot := range holiday.Slots {
+		if slot.IsAvailable {
+			t.Errorf("slot %d should NOT be available on a holiday", slot.ID)
+		}
+	}
+
+	// Slot 1 still covers the morning while slot 2 is in maintenance
+	maintenance := availability[tomorrow.AddDate(0, 0, 1).Format("2006-01-02")]
+	if !hourAvailable(t, maintenance, "08:00") {
+		t.Error("08:00 should be available on slot 1")
+	}
+
+	repo.slots = repo.slots[1:]
+	availability, err = uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	maintenance = availability[tomorrow.AddDate(0, 0, 1).Format("2006-01-02")]
+	if hourAvailable(t, maintenance, "09:30") {
+		t.Error("09:30 should NOT be available while slot 2 is in maintenance")
+	}
+	if !hourAvailable(t, maintenance, "10:00") {
+		t.Error("10:00 should be available after maintenance")
+	}
+}
+
+func TestValidateStartTime_RejectsClosures(t *testing.T) {
+	day := time.Date(2026, 5, 25, 0, 0, 0, 0, time.Local)
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+		closures: []models.Closure{
+			{StartsAt: day.Add(12 * time.Hour), EndsAt: day.Add(14 * time.Hour), Reason: "Acto patrio"},
+		},
+	}
+
+	uc := newAvailabilityUseCase(repo)
+	ctx := context.Background()
+
+	if err := uc.ValidateStartTime(ctx, 1, day.Add(11*time.Hour), time.Hour); err != nil {
+		t.Errorf("expected 11:00-12:00 to be bookable, got %v", err)
+	}
+	if err := uc.ValidateStartTime(ctx, 1, day.Add(11*time.Hour+30*time.Minute), time.Hour); !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Errorf("expected booking into the closure to be rejected, got %v", err)
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260525",
+		"DTEND;VALUE=DATE:20260526",
+		"SUMMARY:Día de la Revolución",
+		"  de Mayo",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260709T120000Z",
+		"DTEND:20260709T150000Z",
+		"SUMMARY:Independencia\\, acto",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	closures, err := importers.ParseICS(strings.NewReader(ics), loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(closures) != 2 {
+		t.Fatalf("expected 2 closures, got %d", len(closures))
+	}
+
+	if !closures[0].StartsAt.Equal(time.Date(2026, 5, 25, 0, 0, 0, 0, loc)) ||
+		!closures[0].EndsAt.Equal(time.Date(2026, 5, 26, 0, 0, 0, 0, loc)) {
+		t.Errorf("unexpected all-day range %s - %s", closures[0].StartsAt, closures[0].EndsAt)
+	}
+	if closures[0].Reason != "Día de la Revolución de Mayo" {
+		t.Errorf("expected unfolded summary, got %q", closures[0].Reason)
+	}
+	if closures[1].Reason != "Independencia, acto" || closures[1].EndsAt.Sub(closures[1].StartsAt) != 3*time.Hour {
+		t.Errorf("unexpected timed closure %+v", closures[1])
+	}
+
+	if _, err := importers.ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:x\n"), loc); !errors.Is(err, importers.ErrInvalidFile) {
+		t.Errorf("expected ErrInvalidFile for unterminated event, got %v", err)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	csv := strings.Join([]string{
+		"start,end,reason,slot_id",
+		"2026-12-25,,Navidad,",
+		"2026-12-31,2027-01-01,Fin de año,",
+		"2026-06-10 08:00,2026-06-10 12:00,Mantenimiento,2",
+	}, "\n")
+
+	closures, err := importers.ParseCSV(strings.NewReader(csv), time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(closures) != 3 {
+		t.Fatalf("expected 3 closures, got %d", len(closures))
+	}
+
+	if closures[0].EndsAt.Sub(closures[0].StartsAt) != 24*time.Hour {
+		t.Errorf("expected single day closure, got %s", closures[0].EndsAt.Sub(closures[0].StartsAt))
+	}
+	// Date-only end is inclusive
+	if closures[1].EndsAt.Sub(closures[1].StartsAt) != 48*time.Hour {
+		t.Errorf("expected two day closure, got %s", closures[1].EndsAt.Sub(closures[1].StartsAt))
+	}
+	if closures[2].SlotID == nil || *closures[2].SlotID != 2 || closures[2].EndsAt.Sub(closures[2].StartsAt) != 4*time.Hour {
+		t.Errorf("unexpected slot closure %+v", closures[2])
+	}
+
+	if _, err := importers.ParseCSV(strings.NewReader("start,end\n2026-01-02,2026-01-01\n"), time.UTC); !errors.Is(err, importers.ErrInvalidFile) {
+		t.Errorf("expected ErrInvalidFile for reversed range, got %v", err)
+	}
+}
+
+func TestImportClosures_SkipsDuplicates(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Closure{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	uc := usecases.NewClosureUseCase(slotRepos.NewClosureRepository(db))
+
+	day := time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)
+	closures := func() []models.Closure {
+		return []models.Closure{{StartsAt: day, EndsAt: day.AddDate(0, 0, 1), Reason: "Navidad"}}
+	}
+
+	first, err := uc.ImportClosures(context.Background(), closures())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := uc.ImportClosures(context.Background(), closures())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first.Created != 1 || second.Created != 0 || second.Skipped != 1 {
+		t.Errorf("expected re-import to skip duplicates, got %+v then %+v", first, second)
+	}
+}
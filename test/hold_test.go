@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	slotRepos "github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/repositories"
+)
+
+// expireHold moves a hold's expiry into the past
+func expireHold(t *testing.T, db *gorm.DB, id uint) {
+	t.Helper()
+	if err := db.Model(&reservationModels.Reservation{}).Where("id = ?", id).
+		Update("expires_at", time.Now().Add(-time.Minute).UTC()).Error; err != nil {
+		t.Fatalf("failed to expire hold: %v", err)
+	}
+}
+
+func TestHoldReservation_OccupiesSlotUntilExpired(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	ctx := context.Background()
+
+	input := reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+	}
+
+	hold, err := uc.HoldReservation(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hold.Status != reservationModels.ReservationStatusPending || hold.ExpiresAt == nil {
+		t.Fatalf("expected a pending hold with an expiry, got %+v", hold)
+	}
+	if d := time.Until(*hold.ExpiresAt); d <= 9*time.Minute || d > 10*time.Minute {
+		t.Errorf("expected the hold to expire in 10 minutes, got %s", d)
+	}
+
+	input.UserID, input.AddressID = 2, 2
+	if _, err := uc.CreateReservation(ctx, input); !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Fatalf("expected the hold to block the slot, got %v", err)
+	}
+
+	repo := slotRepos.NewAvailabilityRepository(db)
+	day := tomorrowAt(0, 0)
+	found, err := repo.FindReservationsByDateRange(ctx, day, day.AddDate(0, 0, 1), time.Now())
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected the unexpired hold in availability, got %d (%v)", len(found), err)
+	}
+
+	expireHold(t, db, hold.ID)
+
+	found, err = repo.FindReservationsByDateRange(ctx, day, day.AddDate(0, 0, 1), time.Now())
+	if err != nil || len(found) != 0 {
+		t.Fatalf("expected the expired hold to be ignored by availability, got %d (%v)", len(found), err)
+	}
+
+	// The slot can be booked even before the sweeper runs
+	if _, err := uc.CreateReservation(ctx, input); err != nil {
+		t.Fatalf("expected the slot to be free after the hold expired, got %v", err)
+	}
+
+	if _, err := uc.ConfirmReservation(ctx, hold.ID, reservationUsecases.StatusChangeInput{}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected confirming an expired hold to conflict, got %v", err)
+	}
+}
+
+func TestReleaseExpiredHolds(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	ctx := context.Background()
+
+	hold := func(hour int) *reservationModels.Reservation {
+		r, err := uc.HoldReservation(ctx, reservationUsecases.CreateReservationInput{
+			UserID:    1,
+			SlotID:    1,
+			AddressID: 1,
+			StartTime: tomorrowAt(hour, 0),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return r
+	}
+
+	expired := hold(10)
+	confirmed := hold(12)
+	active := hold(14)
+
+	if _, err := uc.ConfirmReservation(ctx, confirmed.ID, reservationUsecases.StatusChangeInput{ChangedBy: "payments"}); err != nil {
+		t.Fatalf("unexpected error confirming hold: %v", err)
+	}
+	expireHold(t, db, expired.ID)
+	// Confirmed reservations are never released, even with a stale expiry
+	expireHold(t, db, confirmed.ID)
+
+	released, err := uc.ReleaseExpiredHolds(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(released) != 1 || released[0].ID != expired.ID {
+		t.Fatalf("expected only hold %d to be released, got %+v", expired.ID, released)
+	}
+
+	history, err := uc.GetStatusHistory(ctx, expired.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].ToStatus != reservationModels.ReservationStatusCancelled || history[0].Reason != "hold expired" {
+		t.Errorf("expected the release to be recorded, got %+v", history)
+	}
+
+	for id, want := range map[uint]reservationModels.ReservationStatus{
+		confirmed.ID: reservationModels.ReservationStatusConfirmed,
+		active.ID:    reservationModels.ReservationStatusPending,
+	} {
+		got, err := uc.GetReservation(ctx, id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Status != want {
+			t.Errorf("reservation %d: expected %s, got %s", id, want, got.Status)
+		}
+	}
+
+	// Running again finds nothing left to release
+	released, err = uc.ReleaseExpiredHolds(ctx)
+	if err != nil || len(released) != 0 {
+		t.Errorf("expected nothing to release, got %d (%v)", len(released), err)
+	}
+}
+
+func TestHoldReservation_ExpiresOnTheInjectedClock(t *testing.T) {
+	db := newTestDB(t)
+	clock := &testClock{now: time.Now()}
+	uc := newReservationUseCaseAt(db, clock.Now)
+	repo := slotRepos.NewAvailabilityRepository(db)
+	ctx := context.Background()
+
+	input := reservationUsecases.CreateReservationInput{UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0)}
+	hold, err := uc.HoldReservation(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := clock.now.Add(10 * time.Minute); !hold.ExpiresAt.Equal(want) {
+		t.Errorf("expected the hold to expire at %s, got %s", want, hold.ExpiresAt)
+	}
+
+	// Booking and availability agree the hold keeps its slot until the clock passes its expiry
+	day := tomorrowAt(0, 0)
+	input.UserID, input.AddressID = 2, 2
+	clock.now = clock.now.Add(9 * time.Minute)
+	if _, err := uc.CreateReservation(ctx, input); !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Fatalf("expected the hold to block the slot, got %v", err)
+	}
+	if found, err := repo.FindReservationsByDateRange(ctx, day, day.AddDate(0, 0, 1), clock.now); err != nil || len(found) != 1 {
+		t.Fatalf("expected the hold in availability, got %d (%v)", len(found), err)
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	if found, err := repo.FindReservationsByDateRange(ctx, day, day.AddDate(0, 0, 1), clock.now); err != nil || len(found) != 0 {
+		t.Fatalf("expected the expired hold to be left out of availability, got %d (%v)", len(found), err)
+	}
+	if _, err := uc.ConfirmReservation(ctx, hold.ID, reservationUsecases.StatusChangeInput{}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected confirming the expired hold to conflict, got %v", err)
+	}
+	if _, err := uc.CreateReservation(ctx, input); err != nil {
+		t.Fatalf("expected the slot to be free once the hold expired, got %v", err)
+	}
+
+	released, err := uc.ReleaseExpiredHolds(ctx)
+	if err != nil || len(released) != 1 || released[0].ID != hold.ID {
+		t.Errorf("expected the hold to be released, got %+v (%v)", released, err)
+	}
+}
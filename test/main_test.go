@@ -0,0 +1,16 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+func TestMain(m *testing.M) {
+	// Use cases log through the shared logger; keep test output quiet
+	common.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+)
+
+// ars returns pesos as Money
+func ars(pesos int64) common.Money {
+	return common.NewMoney(pesos*100, "ARS")
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	price := common.NewMoney(150050, "ARS")
+
+	sum, err := price.Add(ars(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := sum.Sub(ars(500)); err != nil || got.Cents != 101050 || got.Currency != "ARS" {
+		t.Errorf("expected ARS 1010.50, got %s %v", got, err)
+	}
+	if got := price.String(); got != "ARS 1500.50" {
+		t.Errorf("expected ARS 1500.50, got %s", got)
+	}
+	if got := common.NewMoney(-5, "ARS").String(); got != "ARS -0.05" {
+		t.Errorf("expected ARS -0.05, got %s", got)
+	}
+	if got, err := ars(10).Min(ars(3)); err != nil || !got.Equal(ars(3)) {
+		t.Errorf("expected ARS 3.00, got %s %v", got, err)
+	}
+	if less, err := ars(3).LessThan(ars(10)); err != nil || !less {
+		t.Errorf("expected ARS 3.00 to be less than ARS 10.00, got %v %v", less, err)
+	}
+
+	// A sum that has not started takes the currency of what is added to it
+	var total common.Money
+	if got, err := total.Add(price); err != nil || !got.Equal(price) {
+		t.Errorf("expected %s, got %s %v", price, got, err)
+	}
+}
+
+func TestMoney_MixedCurrencies(t *testing.T) {
+	price := ars(10)
+	dollars := common.NewMoney(100, "USD")
+
+	operations := map[string]func() error{
+		"add":       func() error { _, err := price.Add(dollars); return err },
+		"sub":       func() error { _, err := price.Sub(dollars); return err },
+		"min":       func() error { _, err := price.Min(dollars); return err },
+		"less than": func() error { _, err := price.LessThan(dollars); return err },
+	}
+	for name, operation := range operations {
+		t.Run(name, func(t *testing.T) {
+			if err := operation(); !errors.Is(err, common.ErrConflict) {
+				t.Errorf("expected ErrConflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMoney_Percent(t *testing.T) {
+	cases := map[string]struct {
+		cents   int64
+		percent int
+		want    int64
+	}{
+		"whole":          {100000, 50, 50000},
+		"surcharge":      {1000000, 140, 1400000},
+		"rounds half up": {1005, 50, 503},
+		"rounds down":    {1001, 33, 330},
+		"negative":       {-1005, 50, -503},
+		"zero percent":   {1005, 0, 0},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := common.NewMoney(tc.cents, "ARS").Percent(tc.percent); got.Cents != tc.want {
+				t.Errorf("expected %d cents, got %d", tc.want, got.Cents)
+			}
+		})
+	}
+}
+
+func TestMoney_FromMajor(t *testing.T) {
+	// 0.29 and 19.99 are not exact in binary floating point
+	cases := map[float64]int64{0.29: 29, 19.99: 1999, 1234.56: 123456}
+	for major, want := range cases {
+		if got := common.MoneyFromMajor(major, "ARS"); got.Cents != want {
+			t.Errorf("expected %v to be %d cents, got %d", major, want, got.Cents)
+		}
+	}
+	if got := common.NewMoney(123456, "ARS").Major(); got != 1234.56 {
+		t.Errorf("expected 1234.56, got %v", got)
+	}
+}
+
+func TestMoney_Validate(t *testing.T) {
+	cases := map[string]struct {
+		money common.Money
+		want  error
+	}{
+		"valid":            {ars(10), nil},
+		"free":             {common.NewMoney(0, "USD"), nil},
+		"negative":         {common.NewMoney(-1, "ARS"), common.ErrInvalidInput},
+		"missing currency": {common.NewMoney(100, ""), common.ErrInvalidInput},
+		"lowercase":        {common.NewMoney(100, "ars"), common.ErrInvalidInput},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.money.Validate(); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+// legacyPayment is the payments table as it was with decimal amounts
+type legacyPayment struct {
+	ID             uint    `gorm:"primaryKey"`
+	ReservationID  uint    `gorm:"index;not null"`
+	Amount         float64 `gorm:"type:decimal(10,2);not null"`
+	Currency       string  `gorm:"type:varchar(3);default:'ARS'"`
+	RefundedAmount float64 `gorm:"type:decimal(10,2);not null;default:0"`
+	Status         string  `gorm:"type:varchar(20);default:'pending'"`
+}
+
+func (legacyPayment) TableName() string {
+	return "payments"
+}
+
+func TestMigrateDecimalMoney(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "legacy.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	legacy := []legacyPayment{
+		{ReservationID: 1, Amount: 1234.56, Currency: "ARS", Status: "completed"},
+		{ReservationID: 2, Amount: 0.29, Currency: "USD", RefundedAmount: 0.1, Status: "partially_refunded"},
+		{ReservationID: 3, Amount: 19.99, RefundedAmount: 19.99, Status: "refunded"},
+	}
+	if err := db.AutoMigrate(&legacyPayment{}); err != nil {
+		t.Fatalf("failed to create legacy table: %v", err)
+	}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("failed to seed legacy payments: %v", err)
+	}
+	// Rows without a currency are migrated to the default currency
+	if err := db.Model(&legacyPayment{}).Where("reservation_id = ?", 3).Update("currency", nil).Error; err != nil {
+		t.Fatalf("failed to seed legacy payments: %v", err)
+	}
+
+	if err := db.AutoMigrate(&paymentModels.Payment{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	columns := []common.DecimalMoneyColumn{
+		{Table: "payments", Column: "amount", Prefix: "amount_", CurrencyColumn: "currency"},
+		{Table: "payments", Column: "refunded_amount", Prefix: "refunded_", CurrencyColumn: "currency"},
+	}
+	// The second run finds nothing left to migrate
+	for range 2 {
+		if err := common.MigrateDecimalMoney(db, columns...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	for _, column := range []string{"amount", "refunded_amount", "currency"} {
+		if db.Migrator().HasColumn("payments", column) {
+			t.Errorf("expected column %s to be dropped", column)
+		}
+	}
+
+	var payments []paymentModels.Payment
+	if err := db.Order("id").Find(&payments).Error; err != nil {
+		t.Fatalf("failed to read payments: %v", err)
+	}
+	want := []struct{ amount, refunded common.Money }{
+		{common.NewMoney(123456, "ARS"), common.NewMoney(0, "ARS")},
+		{common.NewMoney(29, "USD"), common.NewMoney(10, "USD")},
+		{common.NewMoney(1999, "ARS"), common.NewMoney(1999, "ARS")},
+	}
+	if len(payments) != len(want) {
+		t.Fatalf("expected %d payments, got %d", len(want), len(payments))
+	}
+	for i, payment := range payments {
+		if payment.Amount != want[i].amount || payment.RefundedAmount != want[i].refunded {
+			t.Errorf("payment %d: expected %s refunded %s, got %s refunded %s",
+				payment.ID, want[i].amount, want[i].refunded, payment.Amount, payment.RefundedAmount)
+		}
+	}
+
+	created := paymentModels.Payment{ReservationID: 4, Amount: ars(2500)}
+	if err := db.Create(&created).Error; err != nil {
+		t.Errorf("expected new payments to be stored, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authModels "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/models"
+	authUsecases "github.com/Jose-Ig/lavalo-backend/internal/auth/domain/usecases"
+	authRepos "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/repositories"
+	authSenders "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/senders"
+	authTokens "github.com/Jose-Ig/lavalo-backend/internal/auth/infrastructure/tokens"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+)
+
+const testOTPMaxAttempts = 3
+
+// recordingSender keeps the login codes sent
+type recordingSender struct {
+	messages []authModels.CodeMessage
+}
+
+func (s *recordingSender) SendCode(ctx context.Context, message authModels.CodeMessage) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+// last returns the code of the last message sent
+func (s *recordingSender) last(t *testing.T) string {
+	t.Helper()
+	if len(s.messages) == 0 {
+		t.Fatal("expected a code to be sent")
+	}
+	return s.messages[len(s.messages)-1].Code
+}
+
+// newOTPUseCase wires a one-time code use case with a registered client ana@example.com,
+// phone +54 11 5555-0000, a recording sender and a controllable clock
+func newOTPUseCase(t *testing.T) (*authUsecases.OTPUseCase, *recordingSender, *testClock) {
+	t.Helper()
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&authModels.RefreshToken{}, &authModels.OneTimeCode{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	clients := clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db))
+	if _, err := clients.CreateClient(context.Background(), clientUsecases.ClientInput{
+		Name: "Ana", Email: "ana@example.com", Phone: "+54 11 5555-0000",
+	}); err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	clock := &testClock{now: time.Now()}
+	signer := authTokens.NewJWTSigner([]byte("test-secret"), "lavalo-test")
+	auth := authUsecases.NewAuthUseCase(clients, authRepos.NewRefreshTokenRepository(db), signer, 15*time.Minute, 24*time.Hour, clock.Now)
+
+	sender := &recordingSender{}
+	useCase := authUsecases.NewOTPUseCase(auth, authRepos.NewOneTimeCodeRepository(db), sender, 10*time.Minute, testOTPMaxAttempts)
+	return useCase, sender, clock
+}
+
+// wrongCode returns a valid-looking code different from code
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestOTP_RequestAndVerify(t *testing.T) {
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		request     authUsecases.CodeDestination
+		verify      authUsecases.CodeDestination
+		channel     authModels.CodeChannel
+		destination string
+	}{
+		"email":                 {authUsecases.CodeDestination{Email: "ana@example.com"}, authUsecases.CodeDestination{Email: "ANA@example.com"}, authModels.CodeChannelEmail, "ana@example.com"},
+		"phone in other format": {authUsecases.CodeDestination{Phone: "+54 (11) 5555 0000"}, authUsecases.CodeDestination{Phone: "+541155550000"}, authModels.CodeChannelSMS, "+541155550000"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			useCase, sender, _ := newOTPUseCase(t)
+
+			if err := useCase.RequestCode(ctx, tc.request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			code := sender.last(t)
+			if len(code) != 6 {
+				t.Errorf("expected a 6-digit code, got %q", code)
+			}
+			if msg := sender.messages[0]; msg.Channel != tc.channel || msg.Destination != tc.destination {
+				t.Errorf("expected the code sent by %s to %s, got %s to %s", tc.channel, tc.destination, msg.Channel, msg.Destination)
+			}
+
+			tokens, err := useCase.VerifyCode(ctx, tc.verify, code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+				t.Errorf("expected a token pair, got %+v", tokens)
+			}
+
+			// Codes are single-use
+			if _, err := useCase.VerifyCode(ctx, tc.verify, code); !errors.Is(err, common.ErrUnauthorized) {
+				t.Errorf("expected a used code to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOTP_UnknownDestination(t *testing.T) {
+	useCase, sender, _ := newOTPUseCase(t)
+	ctx := context.Background()
+
+	if err := useCase.RequestCode(ctx, authUsecases.CodeDestination{Email: "nadie@example.com"}); err != nil {
+		t.Fatalf("expected unknown emails to look like a success, got %v", err)
+	}
+	if len(sender.messages) != 0 {
+		t.Errorf("expected no code sent to an unknown email, got %d", len(sender.messages))
+	}
+
+	if _, err := useCase.VerifyCode(ctx, authUsecases.CodeDestination{Email: "nadie@example.com"}, "123456"); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestOTP_Validation(t *testing.T) {
+	useCase, _, _ := newOTPUseCase(t)
+	ctx := context.Background()
+
+	if err := useCase.RequestCode(ctx, authUsecases.CodeDestination{}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected a missing destination to be rejected, got %v", err)
+	}
+	both := authUsecases.CodeDestination{Email: "ana@example.com", Phone: "+541155550000"}
+	if err := useCase.RequestCode(ctx, both); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected email and phone together to be rejected, got %v", err)
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "12a456"} {
+		if _, err := useCase.VerifyCode(ctx, authUsecases.CodeDestination{Email: "ana@example.com"}, code); !errors.Is(err, common.ErrInvalidInput) {
+			t.Errorf("expected code %q to be rejected as malformed, got %v", code, err)
+		}
+	}
+}
+
+func TestOTP_LocksAfterMaxAttempts(t *testing.T) {
+	useCase, sender, _ := newOTPUseCase(t)
+	ctx := context.Background()
+	ana := authUsecases.CodeDestination{Email: "ana@example.com"}
+
+	if err := useCase.RequestCode(ctx, ana); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code := sender.last(t)
+
+	for i := 0; i < testOTPMaxAttempts; i++ {
+		if _, err := useCase.VerifyCode(ctx, ana, wrongCode(code)); !errors.Is(err, common.ErrUnauthorized) {
+			t.Fatalf("attempt %d: expected ErrUnauthorized, got %v", i+1, err)
+		}
+	}
+
+	// The right code no longer works once the attempts are spent
+	if _, err := useCase.VerifyCode(ctx, ana, code); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected the locked code to be rejected, got %v", err)
+	}
+}
+
+func TestOTP_Expiry(t *testing.T) {
+	useCase, sender, clock := newOTPUseCase(t)
+	ctx := context.Background()
+	ana := authUsecases.CodeDestination{Email: "ana@example.com"}
+
+	if err := useCase.RequestCode(ctx, ana); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.now = clock.now.Add(11 * time.Minute)
+
+	if _, err := useCase.VerifyCode(ctx, ana, sender.last(t)); !errors.Is(err, common.ErrUnauthorized) {
+		t.Errorf("expected an expired code to be rejected, got %v", err)
+	}
+}
+
+func TestOTP_ResendReplacesPendingCode(t *testing.T) {
+	useCase, sender, clock := newOTPUseCase(t)
+	ctx := context.Background()
+	ana := authUsecases.CodeDestination{Email: "ana@example.com"}
+
+	if err := useCase.RequestCode(ctx, ana); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := sender.last(t)
+
+	// An immediate resend succeeds like a request for an unknown destination, but sends nothing
+	if err := useCase.RequestCode(ctx, ana); err != nil {
+		t.Fatalf("expected an immediate resend to succeed, got %v", err)
+	}
+	if len(sender.messages) != 1 {
+		t.Fatalf("expected an immediate resend to be throttled, got %d messages", len(sender.messages))
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if err := useCase.RequestCode(ctx, ana); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second := sender.last(t)
+
+	if first != second {
+		if _, err := useCase.VerifyCode(ctx, ana, first); !errors.Is(err, common.ErrUnauthorized) {
+			t.Errorf("expected the replaced code to be rejected, got %v", err)
+		}
+	}
+	if _, err := useCase.VerifyCode(ctx, ana, second); err != nil {
+		t.Errorf("expected the new code to work, got %v", err)
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	sender := authSenders.NewFileSender(path)
+	ctx := context.Background()
+
+	for _, code := range []string{"123456", "654321"} {
+		if err := sender.SendCode(ctx, authModels.CodeMessage{
+			Channel:     authModels.CodeChannelEmail,
+			Destination: "ana@example.com",
+			Code:        code,
+			ExpiresAt:   time.Now(),
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open outbox: %v", err)
+	}
+	defer file.Close()
+
+	var codes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var message authSenders.OutboxMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			t.Fatalf("invalid outbox line %q: %v", scanner.Text(), err)
+		}
+		codes = append(codes, message.Code)
+	}
+
+	if len(codes) != 2 || codes[0] != "123456" || codes[1] != "654321" {
+		t.Errorf("expected both codes in order, got %v", codes)
+	}
+}
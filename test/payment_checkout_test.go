@@ -0,0 +1,294 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago/mercadopagotest"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// fakeMercadoPagoToken is the access token of fake Mercado Pago servers in tests
+const fakeMercadoPagoToken = "test-access-token"
+
+// newFakeMercadoPago serves a fake Mercado Pago for the test and returns it with a provider using it
+func newFakeMercadoPago(t *testing.T) (*mercadopagotest.Server, *mercadopago.Provider) {
+	t.Helper()
+
+	fake := mercadopagotest.NewServer(fakeMercadoPagoToken)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, mercadopago.NewProvider(mercadopago.Config{
+		BaseURL:     server.URL,
+		AccessToken: fakeMercadoPagoToken,
+		ReturnURL:   "https://lavalo.example/pago",
+	})
+}
+
+// newCheckoutTest returns a checkout use case over a fake Mercado Pago and a reservation priced at price
+func newCheckoutTest(t *testing.T, price common.Money) (*paymentUsecases.CheckoutUseCase, *mercadopagotest.Server, *gorm.DB, uint) {
+	t.Helper()
+
+	db := newTestDB(t)
+	reservations := newReservationUseCase(db)
+	reservation, err := reservations.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("failed to create reservation: %v", err)
+	}
+	if err := db.Model(&reservationModels.Reservation{}).Where("id = ?", reservation.ID).Updates(reservationModels.Reservation{Price: price}).Error; err != nil {
+		t.Fatalf("failed to price reservation: %v", err)
+	}
+
+	fake, provider := newFakeMercadoPago(t)
+	checkout := paymentUsecases.NewCheckoutUseCase(paymentRepos.NewPaymentRepository(db), provider, reservations, paymentModels.DepositPolicy{Percent: 30})
+	return checkout, fake, db, reservation.ID
+}
+
+func TestCheckout_CreatesProviderCheckout(t *testing.T) {
+	checkout, fake, db, reservationID := newCheckoutTest(t, ars(2500))
+
+	payment, err := checkout.CreateCheckout(context.Background(), reservationID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if payment.Status != paymentModels.PaymentStatusPending || !payment.Amount.Equal(ars(2500)) || payment.Provider != paymentModels.ProviderMercadoPago {
+		t.Errorf("expected a pending Mercado Pago payment of 2500, got %+v", payment)
+	}
+	if payment.CheckoutURL == "" || payment.ExternalID == "" {
+		t.Fatalf("expected a checkout URL and external ID, got %+v", payment)
+	}
+
+	preference, ok := fake.Preference(payment.ExternalID)
+	if !ok {
+		t.Fatalf("expected preference %s at the provider", payment.ExternalID)
+	}
+	if preference.Amount != 2500 || preference.ExternalReference != strconv.Itoa(int(payment.ID)) {
+		t.Errorf("expected a preference of 2500 referencing payment %d, got %+v", payment.ID, preference)
+	}
+
+	var stored paymentModels.Payment
+	if err := db.First(&stored, payment.ID).Error; err != nil {
+		t.Fatalf("failed to load payment: %v", err)
+	}
+	if stored.ExternalID != payment.ExternalID || stored.CheckoutURL != payment.CheckoutURL {
+		t.Errorf("expected the checkout to be recorded, got %+v", stored)
+	}
+}
+
+func TestCheckout_ReusesPendingCheckout(t *testing.T) {
+	checkout, _, _, reservationID := newCheckoutTest(t, ars(2500))
+	ctx := context.Background()
+
+	first, err := checkout.CreateCheckout(ctx, reservationID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := checkout.CreateCheckout(ctx, reservationID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if second.ID != first.ID || second.ExternalID != first.ExternalID {
+		t.Errorf("expected the pending checkout %d to be returned again, got %d", first.ID, second.ID)
+	}
+}
+
+func TestCheckout_ChargesWhatIsOwed(t *testing.T) {
+	checkout, _, db, reservationID := newCheckoutTest(t, ars(2500))
+	ctx := context.Background()
+
+	deposit := paymentModels.Payment{ReservationID: reservationID, Amount: ars(1000), Status: paymentModels.PaymentStatusCompleted}
+	if err := db.Create(&deposit).Error; err != nil {
+		t.Fatalf("failed to create payment: %v", err)
+	}
+
+	payment, err := checkout.CreateCheckout(ctx, reservationID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !payment.Amount.Equal(ars(1500)) {
+		t.Errorf("expected the 1500 left to be charged, got %s", payment.Amount)
+	}
+
+	if err := db.Model(&deposit).Update("amount_cents", ars(2500).Cents).Error; err != nil {
+		t.Fatalf("failed to update payment: %v", err)
+	}
+	if _, err := checkout.CreateCheckout(ctx, reservationID); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict for a paid reservation, got %v", err)
+	}
+}
+
+func TestCheckout_RejectsUnpayableReservations(t *testing.T) {
+	t.Run("cancelled", func(t *testing.T) {
+		checkout, _, db, reservationID := newCheckoutTest(t, ars(2500))
+		if err := db.Model(&reservationModels.Reservation{}).Where("id = ?", reservationID).Update("status", reservationModels.ReservationStatusCancelled).Error; err != nil {
+			t.Fatalf("failed to cancel reservation: %v", err)
+		}
+
+		if _, err := checkout.CreateCheckout(context.Background(), reservationID); !errors.Is(err, common.ErrConflict) {
+			t.Errorf("expected ErrConflict, got %v", err)
+		}
+	})
+
+	t.Run("without price", func(t *testing.T) {
+		checkout, _, _, reservationID := newCheckoutTest(t, common.Money{})
+
+		if _, err := checkout.CreateCheckout(context.Background(), reservationID); !errors.Is(err, common.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		checkout, _, _, _ := newCheckoutTest(t, ars(2500))
+
+		if _, err := checkout.CreateCheckout(context.Background(), 999); !errors.Is(err, common.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestCheckout_ProviderFailure(t *testing.T) {
+	db := newTestDB(t)
+	reservations := newReservationUseCase(db)
+	reservation, err := reservations.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("failed to create reservation: %v", err)
+	}
+	db.Model(reservation).Updates(reservationModels.Reservation{Price: ars(2500)})
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"internal error"}`, http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	provider := mercadopago.NewProvider(mercadopago.Config{BaseURL: down.URL, AccessToken: fakeMercadoPagoToken})
+	checkout := paymentUsecases.NewCheckoutUseCase(paymentRepos.NewPaymentRepository(db), provider, reservations, paymentModels.DepositPolicy{Percent: 30})
+
+	if _, err := checkout.CreateCheckout(context.Background(), reservation.ID); !errors.Is(err, common.ErrUpstream) {
+		t.Fatalf("expected ErrUpstream, got %v", err)
+	}
+
+	var payments []paymentModels.Payment
+	db.Where("reservation_id = ?", reservation.ID).Find(&payments)
+	if len(payments) != 1 || payments[0].Status != paymentModels.PaymentStatusFailed {
+		t.Errorf("expected the attempt to be recorded as failed, got %+v", payments)
+	}
+}
+
+func TestMercadoPago_PaymentLifecycle(t *testing.T) {
+	fake, provider := newFakeMercadoPago(t)
+	ctx := context.Background()
+
+	checkout, err := provider.CreateCheckout(ctx, paymentModels.CheckoutRequest{Reference: "7", Title: "Lavado", Amount: ars(2500)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	status := func(want paymentModels.PaymentStatus, paid, refunded common.Money) {
+		t.Helper()
+		payment, err := provider.GetPayment(ctx, checkout.ExternalID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if payment.Status != want || !payment.PaidAmount.Equal(paid) || !payment.RefundedAmount.Equal(refunded) {
+			t.Errorf("expected %s paid %s refunded %s, got %+v", want, paid, refunded, payment)
+		}
+	}
+
+	status(paymentModels.PaymentStatusPending, ars(0), ars(0))
+
+	if _, err := fake.Pay(checkout.ExternalID, mercadopagotest.StatusRejected); err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	status(paymentModels.PaymentStatusFailed, ars(0), ars(0))
+
+	if _, err := provider.Refund(ctx, checkout.ExternalID, ars(100)); err == nil {
+		t.Error("expected refunding a rejected payment to fail")
+	}
+
+	if _, err := fake.Pay(checkout.ExternalID, mercadopagotest.StatusApproved); err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	status(paymentModels.PaymentStatusCompleted, ars(2500), ars(0))
+
+	refund, err := provider.Refund(ctx, checkout.ExternalID, ars(1000))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refund.ID == "" || !refund.Amount.Equal(ars(1000)) {
+		t.Errorf("expected a refund of 1000, got %+v", refund)
+	}
+	status(paymentModels.PaymentStatusPartiallyRefunded, ars(2500), ars(1000))
+
+	if _, err := provider.Refund(ctx, checkout.ExternalID, ars(2000)); err == nil {
+		t.Error("expected refunding more than was paid to fail")
+	}
+	if _, err := provider.Refund(ctx, checkout.ExternalID, ars(1500)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	status(paymentModels.PaymentStatusRefunded, ars(2500), ars(2500))
+}
+
+func TestMercadoPago_CheckoutPage(t *testing.T) {
+	fake, provider := newFakeMercadoPago(t)
+
+	checkout, err := provider.CreateCheckout(context.Background(), paymentModels.CheckoutRequest{Reference: "7", Title: "Lavado", Amount: ars(2500)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	page, err := http.Get(checkout.URL)
+	if err != nil {
+		t.Fatalf("failed to open checkout: %v", err)
+	}
+	page.Body.Close()
+	if page.StatusCode != http.StatusOK {
+		t.Fatalf("expected the checkout page, got %d", page.StatusCode)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	payURL := strings.Replace(checkout.URL, "/redirect?", "/pay?status=approved&", 1)
+	resp, err := client.Post(payURL, "", nil)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if resp.StatusCode != http.StatusSeeOther || !strings.HasPrefix(location, "https://lavalo.example/pago?") || !strings.Contains(location, "external_reference=7") {
+		t.Errorf("expected a redirect back to the return URL, got %d %s", resp.StatusCode, location)
+	}
+	if payments := fake.Payments(checkout.ExternalID); len(payments) != 1 || payments[0].Status != mercadopagotest.StatusApproved {
+		t.Errorf("expected an approved payment, got %+v", payments)
+	}
+}
+
+func TestMercadoPago_RejectsWrongAccessToken(t *testing.T) {
+	fake := mercadopagotest.NewServer(fakeMercadoPagoToken)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	provider := mercadopago.NewProvider(mercadopago.Config{BaseURL: server.URL, AccessToken: "wrong"})
+	if _, err := provider.CreateCheckout(context.Background(), paymentModels.CheckoutRequest{Reference: "7", Title: "Lavado", Amount: ars(2500)}); err == nil {
+		t.Error("expected an invalid access token to be rejected")
+	}
+}
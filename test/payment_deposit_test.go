@@ -0,0 +1,227 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago/mercadopagotest"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+func TestDepositCheckout_ThenCashAtTheBay(t *testing.T) {
+	wt := newWebhookTest(t)
+	reservation := wt.hold(t)
+	ctx := context.Background()
+
+	deposit, err := wt.checkout.CreateDepositCheckout(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !deposit.Amount.Equal(ars(750)) || deposit.Method != paymentModels.PaymentMethodCheckout || deposit.CheckoutURL == "" {
+		t.Fatalf("expected a checkout for a deposit of 750, got %+v", deposit)
+	}
+	if again, err := wt.checkout.CreateDepositCheckout(ctx, reservation.ID); err != nil || again.ID != deposit.ID {
+		t.Errorf("expected the pending deposit checkout to be reused, got %+v (%v)", again, err)
+	}
+
+	// Paying the deposit confirms the reservation
+	wt.pay(t, deposit, mercadopagotest.StatusApproved)
+	if got, err := wt.reservations.GetReservation(ctx, reservation.ID); err != nil || got.Status != reservationModels.ReservationStatusConfirmed {
+		t.Fatalf("expected the reservation to be confirmed, got %+v (%v)", got, err)
+	}
+	if _, err := wt.checkout.CreateDepositCheckout(ctx, reservation.ID); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict for a paid deposit, got %v", err)
+	}
+
+	balance, err := wt.reservations.GetBalance(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !balance.Total.Equal(ars(2500)) || !balance.Paid.Equal(ars(750)) || !balance.Outstanding.Equal(ars(1750)) {
+		t.Errorf("expected 750 of 2500 paid, got %+v", balance)
+	}
+
+	if _, err := wt.reservations.CompleteReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{ChangedBy: "staff:3"}); !errors.Is(err, common.ErrConflict) {
+		t.Fatalf("expected ErrConflict completing with a balance outstanding, got %v", err)
+	}
+
+	// Without an amount, the whole balance is taken
+	cash, err := wt.checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservation.ID, RecordedBy: "staff:3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !cash.Amount.Equal(ars(1750)) || cash.Method != paymentModels.PaymentMethodCash || cash.Status != paymentModels.PaymentStatusCompleted || cash.RecordedBy != "staff:3" {
+		t.Errorf("expected a completed cash payment of 1750 by staff:3, got %+v", cash)
+	}
+
+	if balance, err := wt.reservations.GetBalance(ctx, reservation.ID); err != nil || !balance.Settled() || !balance.Paid.Equal(ars(2500)) {
+		t.Errorf("expected the balance to be settled, got %+v (%v)", balance, err)
+	}
+	if _, err := wt.reservations.CompleteReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{ChangedBy: "staff:3"}); err != nil {
+		t.Errorf("expected a paid reservation to complete, got %v", err)
+	}
+}
+
+func TestRecordCashPayment_Validation(t *testing.T) {
+	checkout, _, db, reservationID := newCheckoutTest(t, ars(2500))
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		reservationID uint
+		amount        common.Money
+		want          error
+	}{
+		"over the balance": {reservationID, ars(2501), common.ErrInvalidInput},
+		"negative":         {reservationID, common.Money{Cents: -100}, common.ErrInvalidInput},
+		"other currency":   {reservationID, common.NewMoney(100, "USD"), common.ErrInvalidInput},
+		"unknown":          {999, ars(100), common.ErrNotFound},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: tc.reservationID, Amount: tc.amount}); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if _, err := checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservationID, Amount: ars(2500)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservationID, Amount: ars(1)}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict for a paid reservation, got %v", err)
+	}
+
+	_, provider := newFakeMercadoPago(t)
+	disabled := paymentUsecases.NewCheckoutUseCase(paymentRepos.NewPaymentRepository(db), provider, newReservationUseCase(db), paymentModels.DepositPolicy{})
+	if _, err := disabled.CreateDepositCheckout(ctx, reservationID); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput with deposits disabled, got %v", err)
+	}
+}
+
+// slowPaymentRepository takes a while to return a reservation's payments, so concurrent
+// payments all read the balance before any of them is recorded unless they are serialized
+type slowPaymentRepository struct {
+	*paymentRepos.PaymentRepository
+}
+
+func (r slowPaymentRepository) FindByReservationID(ctx context.Context, reservationID uint) ([]paymentModels.Payment, error) {
+	payments, err := r.PaymentRepository.FindByReservationID(ctx, reservationID)
+	time.Sleep(20 * time.Millisecond)
+	return payments, err
+}
+
+func TestRecordCashPayment_ConcurrentPaymentsDoNotOverpay(t *testing.T) {
+	_, _, db, reservationID := newCheckoutTest(t, ars(2500))
+	_, provider := newFakeMercadoPago(t)
+	repo := slowPaymentRepository{paymentRepos.NewPaymentRepository(db)}
+	checkout := paymentUsecases.NewCheckoutUseCase(repo, provider, newReservationUseCase(db), paymentModels.DepositPolicy{})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservationID, Amount: ars(1000)})
+		}()
+	}
+	wg.Wait()
+
+	recorded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			recorded++
+		case !errors.Is(err, common.ErrInvalidInput):
+			t.Errorf("expected payments over the balance to be rejected with ErrInvalidInput, got %v", err)
+		}
+	}
+	if recorded != 2 {
+		t.Errorf("expected 2 payments of 1000 to fit in 2500, got %d", recorded)
+	}
+
+	balance, err := newReservationUseCase(db).GetBalance(ctx, reservationID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !balance.Paid.Equal(ars(2000)) {
+		t.Errorf("expected 2000 paid, got %s", balance.Paid)
+	}
+}
+
+func TestCancelReservation_CountsConcurrentCashPayment(t *testing.T) {
+	_, _, db, reservationID := newCheckoutTest(t, ars(2500))
+	_, provider := newFakeMercadoPago(t)
+	repo := slowPaymentRepository{paymentRepos.NewPaymentRepository(db)}
+	reservations := newReservationUseCaseWithLedger(db, paymentUsecases.NewRefundUseCase(repo, paymentRepos.NewRefundRepository(db), provider), time.Now)
+	checkout := paymentUsecases.NewCheckoutUseCase(repo, provider, reservations, paymentModels.DepositPolicy{})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var payErr, cancelErr error
+	var cancelled *reservationModels.CancellationResult
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, payErr = checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservationID, Amount: ars(1000)})
+	}()
+	go func() {
+		defer wg.Done()
+		cancelled, cancelErr = reservations.CancelReservation(ctx, reservationID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"})
+	}()
+	wg.Wait()
+
+	if cancelErr != nil {
+		t.Fatalf("expected no error, got %v", cancelErr)
+	}
+	// Either the payment went first and the cancellation counted it, or it was rejected
+	counted := ars(1000)
+	if payErr != nil {
+		if !errors.Is(payErr, common.ErrConflict) {
+			t.Errorf("expected a payment on a cancelled reservation to conflict, got %v", payErr)
+		}
+		counted = ars(0)
+	}
+	if !cancelled.PaidAmount.Equal(counted) {
+		t.Errorf("expected the cancellation to count %s paid, got %s", counted, cancelled.PaidAmount)
+	}
+}
+
+func TestCompleteReservation_OverrideBalance(t *testing.T) {
+	checkout, _, db, reservationID := newCheckoutTest(t, ars(2500))
+	reservations := newReservationUseCase(db)
+	ctx := context.Background()
+
+	if _, err := reservations.ConfirmReservation(ctx, reservationID, reservationUsecases.StatusChangeInput{ChangedBy: "staff:3"}); err != nil {
+		t.Fatalf("failed to confirm reservation: %v", err)
+	}
+
+	if _, err := reservations.CompleteReservation(ctx, reservationID, reservationUsecases.StatusChangeInput{ChangedBy: "staff:3", OverrideBalance: true}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput overriding without a reason, got %v", err)
+	}
+
+	completed, err := reservations.CompleteReservation(ctx, reservationID, reservationUsecases.StatusChangeInput{ChangedBy: "staff:3", Reason: "Paga el lunes", OverrideBalance: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if completed.Status != reservationModels.ReservationStatusCompleted {
+		t.Errorf("expected the reservation to be completed, got %s", completed.Status)
+	}
+
+	// The balance can still be paid after completing
+	if _, err := checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservationID, RecordedBy: "staff:3"}); err != nil {
+		t.Errorf("expected no error paying a completed reservation, got %v", err)
+	}
+	if balance, err := reservations.GetBalance(ctx, reservationID); err != nil || !balance.Settled() {
+		t.Errorf("expected the balance to be settled, got %+v (%v)", balance, err)
+	}
+}
@@ -0,0 +1,306 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago/mercadopagotest"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// newRefundTest returns a refund use case over a fake Mercado Pago and a reservation to record payments for
+func newRefundTest(t *testing.T) (*paymentUsecases.RefundUseCase, *gorm.DB, uint) {
+	t.Helper()
+
+	db := newTestDB(t)
+	reservation, err := newReservationUseCase(db).CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("failed to create reservation: %v", err)
+	}
+
+	_, provider := newFakeMercadoPago(t)
+	refunds := paymentUsecases.NewRefundUseCase(paymentRepos.NewPaymentRepository(db), paymentRepos.NewRefundRepository(db), provider)
+	return refunds, db, reservation.ID
+}
+
+func TestRefundPayment_PartialThenFull(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+	ctx := context.Background()
+
+	partial, err := wt.refunds.RefundPayment(ctx, payment.ID, paymentUsecases.RefundInput{Amount: common.Money{Cents: 100000}, Reason: "Lavado incompleto", RequestedBy: "staff:3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if partial.Status != paymentModels.RefundStatusSucceeded || partial.ProviderRefundID == "" || !partial.Amount.Equal(ars(1000)) {
+		t.Errorf("expected a succeeded provider refund of 1000, got %+v", partial)
+	}
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusPartiallyRefunded || !got.RefundedAmount.Equal(ars(1000)) {
+		t.Errorf("expected 1000 refunded, got %s %s", got.Status, got.RefundedAmount)
+	}
+
+	// Without an amount, everything left is refunded
+	rest, err := wt.refunds.RefundPayment(ctx, payment.ID, paymentUsecases.RefundInput{Reason: "Cliente insatisfecho", RequestedBy: "staff:3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !rest.Amount.Equal(ars(1500)) {
+		t.Errorf("expected the remaining 1500 to be refunded, got %s", rest.Amount)
+	}
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusRefunded || !got.RefundedAmount.Equal(ars(2500)) {
+		t.Errorf("expected a full refund, got %s %s", got.Status, got.RefundedAmount)
+	}
+	if attempts := wt.fake.Payments(payment.ExternalID); attempts[0].AmountRefunded != 2500 {
+		t.Errorf("expected the provider to have refunded 2500, got %v", attempts[0].AmountRefunded)
+	}
+
+	if _, err := wt.refunds.RefundPayment(ctx, payment.ID, paymentUsecases.RefundInput{Reason: "Otra vez"}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict for a refunded payment, got %v", err)
+	}
+
+	refunds, err := wt.refunds.ListPaymentRefunds(ctx, payment.ID)
+	if err != nil || len(refunds) != 2 || refunds[0].ID != partial.ID || refunds[1].ID != rest.ID {
+		t.Errorf("expected both refunds oldest first, got %+v (%v)", refunds, err)
+	}
+}
+
+func TestRefundPayment_Validation(t *testing.T) {
+	refunds, db, reservation := newRefundTest(t)
+	ctx := context.Background()
+
+	completed := paymentModels.Payment{ReservationID: reservation, Amount: ars(1000), Status: paymentModels.PaymentStatusCompleted}
+	pending := paymentModels.Payment{ReservationID: reservation, Amount: ars(1000), Status: paymentModels.PaymentStatusPending}
+	for _, payment := range []*paymentModels.Payment{&completed, &pending} {
+		if err := db.Create(payment).Error; err != nil {
+			t.Fatalf("failed to seed payment: %v", err)
+		}
+	}
+
+	cases := map[string]struct {
+		paymentID uint
+		amount    common.Money
+		want      error
+	}{
+		"over the balance": {completed.ID, ars(1001), common.ErrInvalidInput},
+		"negative":         {completed.ID, common.Money{Cents: -100}, common.ErrInvalidInput},
+		"other currency":   {completed.ID, common.NewMoney(100, "USD"), common.ErrInvalidInput},
+		"not captured":     {pending.ID, ars(100), common.ErrConflict},
+		"unknown payment":  {999, ars(100), common.ErrNotFound},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := refunds.RefundPayment(ctx, tc.paymentID, paymentUsecases.RefundInput{Amount: tc.amount, Reason: "Prueba"}); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	// Payments taken without a checkout are refunded without the provider
+	refund, err := refunds.RefundPayment(ctx, completed.ID, paymentUsecases.RefundInput{Amount: ars(400), Reason: "Pago en efectivo"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if refund.Status != paymentModels.RefundStatusSucceeded || refund.ProviderRefundID != "" {
+		t.Errorf("expected a succeeded refund without a provider ID, got %+v", refund)
+	}
+}
+
+func TestRefundPayment_ProviderFailure(t *testing.T) {
+	refunds, db, reservation := newRefundTest(t)
+	ctx := context.Background()
+
+	// The provider has no payments for this checkout, so it cannot refund it
+	payment := paymentModels.Payment{
+		ReservationID: reservation,
+		Amount:        ars(1000),
+		Status:        paymentModels.PaymentStatusCompleted,
+		Provider:      paymentModels.ProviderMercadoPago,
+		ExternalID:    "unknown-preference",
+	}
+	if err := db.Create(&payment).Error; err != nil {
+		t.Fatalf("failed to seed payment: %v", err)
+	}
+
+	if _, err := refunds.RefundPayment(ctx, payment.ID, paymentUsecases.RefundInput{Reason: "Prueba"}); !errors.Is(err, common.ErrUpstream) {
+		t.Fatalf("expected ErrUpstream, got %v", err)
+	}
+
+	recorded, err := refunds.ListPaymentRefunds(ctx, payment.ID)
+	if err != nil || len(recorded) != 1 || recorded[0].Status != paymentModels.RefundStatusFailed || recorded[0].Failure == "" {
+		t.Errorf("expected the refund to be recorded as failed, got %+v (%v)", recorded, err)
+	}
+
+	var stored paymentModels.Payment
+	if err := db.First(&stored, payment.ID).Error; err != nil {
+		t.Fatalf("failed to reload payment: %v", err)
+	}
+	if stored.Status != paymentModels.PaymentStatusCompleted || !stored.RefundedAmount.IsZero() {
+		t.Errorf("expected the payment to be unchanged, got %s %s", stored.Status, stored.RefundedAmount)
+	}
+}
+
+// slowRefundRepository takes a while to record a refund, so concurrent refunds of a payment
+// all check what is left to refund before any of them is recorded unless they are serialized
+type slowRefundRepository struct {
+	*paymentRepos.RefundRepository
+}
+
+func (r slowRefundRepository) Create(ctx context.Context, refund *paymentModels.Refund) error {
+	err := r.RefundRepository.Create(ctx, refund)
+	time.Sleep(20 * time.Millisecond)
+	return err
+}
+
+func TestRefundPayment_ConcurrentRefundsDoNotOverRefund(t *testing.T) {
+	_, db, reservation := newRefundTest(t)
+	_, provider := newFakeMercadoPago(t)
+	refunds := paymentUsecases.NewRefundUseCase(paymentRepos.NewPaymentRepository(db), slowRefundRepository{paymentRepos.NewRefundRepository(db)}, provider)
+	ctx := context.Background()
+
+	payment := paymentModels.Payment{ReservationID: reservation, Amount: ars(2500), Status: paymentModels.PaymentStatusCompleted, Method: paymentModels.PaymentMethodCash}
+	if err := db.Create(&payment).Error; err != nil {
+		t.Fatalf("failed to seed payment: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = refunds.RefundPayment(ctx, payment.ID, paymentUsecases.RefundInput{Amount: ars(1000), Reason: "Prueba", RequestedBy: "staff:3"})
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, common.ErrInvalidInput) && !errors.Is(err, common.ErrConflict):
+			t.Errorf("expected the refunds over the balance to be rejected, got %v", err)
+		}
+	}
+	if succeeded != 2 {
+		t.Errorf("expected 2 refunds to succeed, got %d", succeeded)
+	}
+
+	var stored paymentModels.Payment
+	if err := db.First(&stored, payment.ID).Error; err != nil {
+		t.Fatalf("failed to reload payment: %v", err)
+	}
+	if stored.Status != paymentModels.PaymentStatusPartiallyRefunded || !stored.RefundedAmount.Equal(ars(2000)) {
+		t.Errorf("expected 2000 refunded, got %s %s", stored.Status, stored.RefundedAmount)
+	}
+	recorded, err := refunds.ListPaymentRefunds(ctx, payment.ID)
+	if err != nil || len(recorded) != 2 {
+		t.Errorf("expected only the 2 refunds that went through to be recorded, got %+v (%v)", recorded, err)
+	}
+}
+
+func TestCancelReservation_RefundsThroughProvider(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+	ctx := context.Background()
+
+	result, err := wt.reservations.CancelReservation(ctx, payment.ReservationID, reservationUsecases.StatusChangeInput{ChangedBy: "customer:1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.RefundAmount.IsPositive() {
+		t.Fatalf("expected part of the payment to be refunded, got %s", result.RefundAmount)
+	}
+
+	if attempts := wt.fake.Payments(payment.ExternalID); common.MoneyFromMajor(attempts[0].AmountRefunded, "ARS") != result.RefundAmount {
+		t.Errorf("expected the provider to have refunded %s, got %v", result.RefundAmount, attempts[0].AmountRefunded)
+	}
+
+	refunds, err := wt.refunds.ListPaymentRefunds(ctx, payment.ID)
+	if err != nil || len(refunds) != 1 {
+		t.Fatalf("expected a refund to be recorded, got %+v (%v)", refunds, err)
+	}
+	if refunds[0].Status != paymentModels.RefundStatusSucceeded || refunds[0].RequestedBy != fmt.Sprintf("reservation:%d", payment.ReservationID) {
+		t.Errorf("expected a succeeded refund requested by the cancellation, got %+v", refunds[0])
+	}
+}
+
+// failingProvider is a payment provider whose refunds fail while failRefunds is set
+type failingProvider struct {
+	paymentUsecases.PaymentProvider
+	failRefunds bool
+}
+
+func (p *failingProvider) Refund(ctx context.Context, externalID string, amount common.Money) (*paymentModels.ProviderRefund, error) {
+	if p.failRefunds {
+		return nil, errors.New("provider unavailable")
+	}
+	return p.PaymentProvider.Refund(ctx, externalID, amount)
+}
+
+func TestCancelReservation_RetriesFailedRefund(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+	ctx := context.Background()
+
+	provider := &failingProvider{PaymentProvider: wt.provider, failRefunds: true}
+	reservations := newReservationUseCaseWithProvider(wt.db, provider, time.Now)
+
+	// The cancellation stands even though the refund fails, and the refund owed is kept
+	result, err := reservations.CancelReservation(ctx, payment.ReservationID, reservationUsecases.StatusChangeInput{ChangedBy: "customer:1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Reservation.Status != reservationModels.ReservationStatusCancelled || result.RefundStatus != reservationModels.RefundStatusPending {
+		t.Fatalf("expected a cancelled reservation with a pending refund, got %s %s", result.Reservation.Status, result.RefundStatus)
+	}
+	if _, err := reservations.CancelReservation(ctx, payment.ReservationID, reservationUsecases.StatusChangeInput{}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict cancelling again, got %v", err)
+	}
+
+	refunded, err := reservations.RetryPendingRefunds(ctx)
+	if err != nil || len(refunded) != 0 {
+		t.Fatalf("expected nothing refunded while the provider fails, got %+v (%v)", refunded, err)
+	}
+
+	provider.failRefunds = false
+	refunded, err = reservations.RetryPendingRefunds(ctx)
+	if err != nil || len(refunded) != 1 {
+		t.Fatalf("expected the refund to be retried, got %+v (%v)", refunded, err)
+	}
+	if refunded[0].RefundStatus != reservationModels.RefundStatusRefunded || refunded[0].RefundAttempts != 3 || refunded[0].RefundedAt == nil {
+		t.Errorf("expected a refunded cancellation after 3 attempts, got %+v", refunded[0])
+	}
+	if attempts := wt.fake.Payments(payment.ExternalID); common.MoneyFromMajor(attempts[0].AmountRefunded, "ARS") != result.RefundAmount {
+		t.Errorf("expected the provider to have refunded %s, got %v", result.RefundAmount, attempts[0].AmountRefunded)
+	}
+
+	// Refunded cancellations are not retried
+	if refunded, err := reservations.RetryPendingRefunds(ctx); err != nil || len(refunded) != 0 {
+		t.Errorf("expected nothing left to retry, got %+v (%v)", refunded, err)
+	}
+
+	// Refunding the reservation again does not refund it twice
+	if err := wt.refunds.RefundReservation(ctx, payment.ReservationID, result.RefundAmount); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := wt.payment(t, payment.ID); !got.RefundedAmount.Equal(result.RefundAmount) {
+		t.Errorf("expected %s refunded once, got %s", result.RefundAmount, got.RefundedAmount)
+	}
+}
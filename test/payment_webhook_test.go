@@ -0,0 +1,459 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentHttp "github.com/Jose-Ig/lavalo-backend/internal/payments/application/http"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago/mercadopagotest"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+// webhookSecret signs the fake Mercado Pago's webhooks in tests
+const webhookSecret = "test-webhook-secret"
+
+// webhookTest is an API serving the payment webhook to a fake Mercado Pago that notifies it
+type webhookTest struct {
+	db           *gorm.DB
+	fake         *mercadopagotest.Server
+	provider     *mercadopago.Provider
+	checkout     *paymentUsecases.CheckoutUseCase
+	refunds      *paymentUsecases.RefundUseCase
+	reservations *reservationUsecases.ReservationUseCase
+	webhookURL   string
+}
+
+// newWebhookTest serves the webhook over HTTP, so the fake can deliver notifications to it
+func newWebhookTest(t *testing.T) *webhookTest {
+	t.Helper()
+
+	var router *gin.Engine
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(api.Close)
+
+	fake := mercadopagotest.NewServer(fakeMercadoPagoToken)
+	fake.EnableWebhooks(webhookSecret)
+	mp := httptest.NewServer(fake)
+	t.Cleanup(mp.Close)
+
+	wt := &webhookTest{db: newTestDB(t), fake: fake, webhookURL: api.URL + "/payments/webhook"}
+	wt.provider = mercadopago.NewProvider(mercadopago.Config{
+		BaseURL:         mp.URL,
+		AccessToken:     fakeMercadoPagoToken,
+		NotificationURL: wt.webhookURL,
+		WebhookSecret:   webhookSecret,
+	})
+
+	repo := paymentRepos.NewPaymentRepository(wt.db)
+	wt.reservations = newReservationUseCaseWithProvider(wt.db, wt.provider, time.Now)
+	wt.refunds = paymentUsecases.NewRefundUseCase(repo, paymentRepos.NewRefundRepository(wt.db), wt.provider)
+	wt.checkout = paymentUsecases.NewCheckoutUseCase(repo, wt.provider, wt.reservations, paymentModels.DepositPolicy{Percent: 30})
+	webhooks := paymentUsecases.NewWebhookUseCase(repo, paymentRepos.NewWebhookEventRepository(wt.db), wt.provider, wt.reservations, wt.refunds)
+
+	gin.SetMode(gin.TestMode)
+	router = gin.New()
+	handler := paymentHttp.NewPaymentHandler(paymentUsecases.NewPaymentUseCase(repo), wt.checkout, webhooks, wt.refunds, wt.reservations)
+	handler.RegisterWebhookRoutes(&router.RouterGroup)
+	return wt
+}
+
+// hold books a reservation priced at 2500, held until it is paid
+func (wt *webhookTest) hold(t *testing.T) *reservationModels.Reservation {
+	t.Helper()
+
+	reservation, err := wt.reservations.HoldReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("failed to hold reservation: %v", err)
+	}
+	if err := wt.db.Model(reservation).Updates(reservationModels.Reservation{Price: ars(2500)}).Error; err != nil {
+		t.Fatalf("failed to price reservation: %v", err)
+	}
+	return reservation
+}
+
+// book books a reservation priced at 2500 that is not held
+func (wt *webhookTest) book(t *testing.T) *reservationModels.Reservation {
+	t.Helper()
+
+	reservation, err := wt.reservations.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("failed to book reservation: %v", err)
+	}
+	if err := wt.db.Model(reservation).Updates(reservationModels.Reservation{Price: ars(2500)}).Error; err != nil {
+		t.Fatalf("failed to price reservation: %v", err)
+	}
+	return reservation
+}
+
+// startCheckout books a reservation priced at 2500 and starts paying it
+func (wt *webhookTest) startCheckout(t *testing.T) *paymentModels.Payment {
+	t.Helper()
+
+	payment, err := wt.checkout.CreateCheckout(context.Background(), wt.hold(t).ID)
+	if err != nil {
+		t.Fatalf("failed to create checkout: %v", err)
+	}
+	return payment
+}
+
+// pay pays a checkout at the fake, which notifies the webhook
+func (wt *webhookTest) pay(t *testing.T, payment *paymentModels.Payment, status string) int64 {
+	t.Helper()
+
+	id, err := wt.fake.Pay(payment.ExternalID, status)
+	if err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	if notifications := wt.fake.Notifications(); notifications[len(notifications)-1].StatusCode != http.StatusOK {
+		t.Fatalf("expected the webhook to be acknowledged, got %d", notifications[len(notifications)-1].StatusCode)
+	}
+	return id
+}
+
+// payment reloads a payment
+func (wt *webhookTest) payment(t *testing.T, id uint) paymentModels.Payment {
+	t.Helper()
+
+	var payment paymentModels.Payment
+	if err := wt.db.First(&payment, id).Error; err != nil {
+		t.Fatalf("failed to load payment: %v", err)
+	}
+	return payment
+}
+
+// post sends a webhook to the API, returning the status code and body
+func (wt *webhookTest) post(t *testing.T, url string, header http.Header, body []byte) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header = header.Clone()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to post webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response bytes.Buffer
+	response.ReadFrom(resp.Body)
+	return resp.StatusCode, response.String()
+}
+
+func TestWebhook_CompletedPaymentConfirmsReservation(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusCompleted {
+		t.Errorf("expected the payment to complete, got %s", got.Status)
+	}
+
+	reservation, err := wt.reservations.GetReservation(context.Background(), payment.ReservationID)
+	if err != nil {
+		t.Fatalf("failed to load reservation: %v", err)
+	}
+	if reservation.Status != reservationModels.ReservationStatusConfirmed || reservation.ExpiresAt != nil {
+		t.Errorf("expected the hold to be confirmed, got %s expiring %v", reservation.Status, reservation.ExpiresAt)
+	}
+
+	history, err := wt.reservations.GetStatusHistory(context.Background(), reservation.ID)
+	if err != nil {
+		t.Fatalf("failed to load history: %v", err)
+	}
+	if len(history) == 0 || history[len(history)-1].ChangedBy != "payment:"+strconv.Itoa(int(payment.ID)) {
+		t.Errorf("expected the confirmation to be attributed to the payment, got %+v", history)
+	}
+}
+
+func TestWebhook_PaymentForReleasedHoldIsRefunded(t *testing.T) {
+	cases := map[string]func(t *testing.T, wt *webhookTest, reservationID uint){
+		"expired": func(t *testing.T, wt *webhookTest, reservationID uint) {},
+		"released": func(t *testing.T, wt *webhookTest, reservationID uint) {
+			if _, err := wt.reservations.ReleaseExpiredHolds(context.Background()); err != nil {
+				t.Fatalf("failed to release holds: %v", err)
+			}
+		},
+	}
+	for name, release := range cases {
+		t.Run(name, func(t *testing.T) {
+			wt := newWebhookTest(t)
+			payment := wt.startCheckout(t)
+			if err := wt.db.Model(&reservationModels.Reservation{}).Where("id = ?", payment.ReservationID).
+				Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+				t.Fatalf("failed to expire hold: %v", err)
+			}
+			release(t, wt, payment.ReservationID)
+
+			wt.pay(t, payment, mercadopagotest.StatusApproved)
+
+			if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusRefunded || !got.RefundedAmount.Equal(ars(2500)) {
+				t.Errorf("expected the payment to be refunded, got %s %s", got.Status, got.RefundedAmount)
+			}
+			refunds, err := wt.refunds.ListPaymentRefunds(context.Background(), payment.ID)
+			if err != nil {
+				t.Fatalf("failed to list refunds: %v", err)
+			}
+			if len(refunds) != 1 || refunds[0].Status != paymentModels.RefundStatusSucceeded || refunds[0].RequestedBy != "payment:"+strconv.Itoa(int(payment.ID)) {
+				t.Errorf("expected a single refund requested by the payment, got %+v", refunds)
+			}
+
+			reservation, err := wt.reservations.GetReservation(context.Background(), payment.ReservationID)
+			if err != nil {
+				t.Fatalf("failed to load reservation: %v", err)
+			}
+			if reservation.Status == reservationModels.ReservationStatusConfirmed {
+				t.Errorf("expected the released hold not to be confirmed")
+			}
+		})
+	}
+}
+
+func TestWebhook_PaymentForCancelledReservationIsRefunded(t *testing.T) {
+	cases := map[string]struct {
+		cash         common.Money
+		cashRefunded common.Money
+	}{
+		// Nothing was paid when it was cancelled, so the checkout is refunded in full
+		"unpaid": {},
+		// The late cancellation keeps half the cash as a fee but refunds all of the checkout
+		"partly paid in cash": {cash: ars(1000), cashRefunded: ars(500)},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wt := newWebhookTest(t)
+			ctx := context.Background()
+			reservation := wt.book(t)
+			// Starting within the free cancellation window, the cancellation keeps a fee
+			if err := wt.db.Model(reservation).Update("start_time", time.Now().Add(2*time.Hour)).Error; err != nil {
+				t.Fatalf("failed to move reservation: %v", err)
+			}
+
+			var cash *paymentModels.Payment
+			if tc.cash.IsPositive() {
+				var err error
+				if cash, err = wt.checkout.RecordCashPayment(ctx, paymentUsecases.CashPaymentInput{ReservationID: reservation.ID, Amount: tc.cash}); err != nil {
+					t.Fatalf("failed to record cash: %v", err)
+				}
+			}
+			payment, err := wt.checkout.CreateCheckout(ctx, reservation.ID)
+			if err != nil {
+				t.Fatalf("failed to create checkout: %v", err)
+			}
+			if _, err := wt.reservations.CancelReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"}); err != nil {
+				t.Fatalf("failed to cancel: %v", err)
+			}
+
+			wt.pay(t, payment, mercadopagotest.StatusApproved)
+
+			if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusRefunded || !got.RefundedAmount.Equal(payment.Amount) {
+				t.Errorf("expected the checkout to be refunded, got %s %s", got.Status, got.RefundedAmount)
+			}
+			if cash != nil {
+				if got := wt.payment(t, cash.ID); !got.RefundedAmount.Equal(tc.cashRefunded) {
+					t.Errorf("expected %s of the cash to be refunded, got %s", tc.cashRefunded, got.RefundedAmount)
+				}
+			}
+		})
+	}
+}
+
+func TestPaymentRepository_UpdateStateRejectsStalePayment(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+	repo := paymentRepos.NewPaymentRepository(wt.db)
+	ctx := context.Background()
+
+	stale := wt.payment(t, payment.ID)
+	if _, err := wt.refunds.RefundPayment(ctx, payment.ID, paymentUsecases.RefundInput{Amount: ars(1000)}); err != nil {
+		t.Fatalf("failed to refund: %v", err)
+	}
+
+	if err := repo.UpdateState(ctx, &stale, paymentModels.PaymentStatusCompleted, ars(0)); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if got := wt.payment(t, payment.ID); !got.RefundedAmount.Equal(ars(1000)) {
+		t.Errorf("expected the refund to be kept, got %s", got.RefundedAmount)
+	}
+}
+
+func TestWebhook_RejectedPaymentFails(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+
+	wt.pay(t, payment, mercadopagotest.StatusRejected)
+
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusFailed {
+		t.Errorf("expected the payment to fail, got %s", got.Status)
+	}
+	reservation, _ := wt.reservations.GetReservation(context.Background(), payment.ReservationID)
+	if reservation.Status != reservationModels.ReservationStatusPending {
+		t.Errorf("expected the reservation to stay pending, got %s", reservation.Status)
+	}
+
+	// The client may retry on the same checkout
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusCompleted {
+		t.Errorf("expected the retried payment to complete, got %s", got.Status)
+	}
+}
+
+func TestWebhook_Refunds(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	wt.pay(t, payment, mercadopagotest.StatusApproved)
+	ctx := context.Background()
+
+	if _, err := wt.provider.Refund(ctx, payment.ExternalID, ars(1000)); err != nil {
+		t.Fatalf("failed to refund: %v", err)
+	}
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusPartiallyRefunded || !got.RefundedAmount.Equal(ars(1000)) {
+		t.Errorf("expected 1000 refunded, got %s %s", got.Status, got.RefundedAmount)
+	}
+
+	if _, err := wt.provider.Refund(ctx, payment.ExternalID, ars(1500)); err != nil {
+		t.Fatalf("failed to refund: %v", err)
+	}
+	if got := wt.payment(t, payment.ID); got.Status != paymentModels.PaymentStatusRefunded || !got.RefundedAmount.Equal(ars(2500)) {
+		t.Errorf("expected a full refund, got %s %s", got.Status, got.RefundedAmount)
+	}
+}
+
+func TestWebhook_IgnoresRepeatedNotifications(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	paymentID := wt.pay(t, payment, mercadopagotest.StatusApproved)
+
+	if err := wt.fake.Notify(paymentID); err != nil {
+		t.Fatalf("failed to notify: %v", err)
+	}
+
+	notifications := wt.fake.Notifications()
+	if len(notifications) != 2 || notifications[1].StatusCode != http.StatusOK {
+		t.Fatalf("expected the repeat to be acknowledged, got %+v", notifications)
+	}
+
+	status, body := wt.post(t, notifications[0].URL, notifications[0].Header, notifications[0].Body)
+	if status != http.StatusOK || !strings.Contains(body, "already processed") {
+		t.Errorf("expected a replay to be acknowledged as already processed, got %d %s", status, body)
+	}
+
+	var events []paymentModels.WebhookEvent
+	wt.db.Find(&events)
+	if len(events) != 1 || events[0].PaymentID == nil || *events[0].PaymentID != payment.ID {
+		t.Errorf("expected a single event linked to the payment, got %+v", events)
+	}
+}
+
+func TestWebhook_RejectsInvalidSignatures(t *testing.T) {
+	wt := newWebhookTest(t)
+	payment := wt.startCheckout(t)
+	paymentID := wt.pay(t, payment, mercadopagotest.StatusApproved)
+	sent := wt.fake.Notifications()[0]
+	dataID := strconv.FormatInt(paymentID, 10)
+
+	signed := func(secret string, at time.Time) http.Header {
+		ts := strconv.FormatInt(at.Unix(), 10)
+		header := http.Header{}
+		header.Set("Content-Type", "application/json")
+		header.Set("x-request-id", "replay")
+		header.Set("x-signature", "ts="+ts+",v1="+mercadopagotest.Sign(secret, dataID, "replay", ts))
+		return header
+	}
+
+	otherPayment, _ := json.Marshal(map[string]interface{}{"id": 1, "type": "payment", "data": map[string]string{"id": "999"}})
+
+	tests := map[string]struct {
+		header http.Header
+		body   []byte
+		want   int
+	}{
+		"missing signature": {http.Header{"Content-Type": {"application/json"}}, sent.Body, http.StatusUnauthorized},
+		"wrong secret":      {signed("guessed", time.Now()), sent.Body, http.StatusUnauthorized},
+		"expired":           {signed(webhookSecret, time.Now().Add(-10*time.Minute)), sent.Body, http.StatusUnauthorized},
+		"from the future":   {signed(webhookSecret, time.Now().Add(10*time.Minute)), sent.Body, http.StatusUnauthorized},
+		"body swapped":      {signed(webhookSecret, time.Now()), otherPayment, http.StatusBadRequest},
+		"malformed body":    {signed(webhookSecret, time.Now()), []byte("{"), http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if status, body := wt.post(t, sent.URL, tt.header, tt.body); status != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, status, body)
+			}
+		})
+	}
+
+	var events int64
+	wt.db.Model(&paymentModels.WebhookEvent{}).Count(&events)
+	if events != 1 {
+		t.Errorf("expected only the genuine notification to be recorded, got %d events", events)
+	}
+}
+
+func TestWebhook_UnknownCheckoutIsAcknowledged(t *testing.T) {
+	wt := newWebhookTest(t)
+
+	// A checkout created outside this API
+	checkout, err := wt.provider.CreateCheckout(context.Background(), paymentModels.CheckoutRequest{Reference: "other", Title: "Otro", Amount: ars(100)})
+	if err != nil {
+		t.Fatalf("failed to create checkout: %v", err)
+	}
+	if _, err := wt.fake.Pay(checkout.ExternalID, mercadopagotest.StatusApproved); err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+
+	if notifications := wt.fake.Notifications(); len(notifications) != 1 || notifications[0].StatusCode != http.StatusOK {
+		t.Errorf("expected the notification to be acknowledged, got %+v", notifications)
+	}
+}
+
+func TestPaymentStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to paymentModels.PaymentStatus
+		want     bool
+	}{
+		{paymentModels.PaymentStatusPending, paymentModels.PaymentStatusCompleted, true},
+		{paymentModels.PaymentStatusPending, paymentModels.PaymentStatusFailed, true},
+		{paymentModels.PaymentStatusFailed, paymentModels.PaymentStatusCompleted, true},
+		{paymentModels.PaymentStatusCompleted, paymentModels.PaymentStatusRefunded, true},
+		{paymentModels.PaymentStatusPartiallyRefunded, paymentModels.PaymentStatusRefunded, true},
+		{paymentModels.PaymentStatusCompleted, paymentModels.PaymentStatusPending, false},
+		{paymentModels.PaymentStatusCompleted, paymentModels.PaymentStatusFailed, false},
+		{paymentModels.PaymentStatusRefunded, paymentModels.PaymentStatusCompleted, false},
+		{paymentModels.PaymentStatusPartiallyRefunded, paymentModels.PaymentStatusCompleted, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
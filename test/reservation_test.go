@@ -0,0 +1,518 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	addressModels "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/models"
+	addressUsecases "github.com/Jose-Ig/lavalo-backend/internal/addresses/domain/usecases"
+	addressRepos "github.com/Jose-Ig/lavalo-backend/internal/addresses/infrastructure/repositories"
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	paymentUsecases "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/usecases"
+	"github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/providers/mercadopago"
+	paymentRepos "github.com/Jose-Ig/lavalo-backend/internal/payments/infrastructure/repositories"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	reservationRepos "github.com/Jose-Ig/lavalo-backend/internal/reservations/infrastructure/repositories"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
+	serviceUsecases "github.com/Jose-Ig/lavalo-backend/internal/services/domain/usecases"
+	serviceRepos "github.com/Jose-Ig/lavalo-backend/internal/services/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+)
+
+// testClients is how many clients newTestDB registers, with IDs 1 to testClients.
+// Each client has an address with the same ID.
+const testClients = 10
+
+// newTestDB opens a migrated SQLite database in a temporary directory,
+// enforcing foreign keys like the server does
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger:         gormlogger.Default.LogMode(gormlogger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(
+		&clientModels.Client{},
+		&clientModels.Vehicle{},
+		&addressModels.Address{},
+		&reservationModels.Reservation{},
+		&reservationModels.ReservationStatusChange{},
+		&reservationModels.ReservationReschedule{},
+		&reservationModels.ReservationSeries{},
+		&reservationModels.ReservationCancellation{},
+		&models.Slot{},
+		&paymentModels.Payment{},
+		&paymentModels.WebhookEvent{},
+		&paymentModels.Refund{},
+		&serviceModels.Service{},
+	); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	for id := uint(1); id <= testClients; id++ {
+		client := clientModels.Client{ID: id, Name: fmt.Sprintf("Client %d", id), Email: fmt.Sprintf("client%d@example.com", id)}
+		if err := db.Create(&client).Error; err != nil {
+			t.Fatalf("failed to seed clients: %v", err)
+		}
+		address := addressModels.Address{ID: id, UserID: id, Street: "Av. Santa Fe", Number: fmt.Sprint(1000 + id), City: "CABA"}
+		if err := db.Create(&address).Error; err != nil {
+			t.Fatalf("failed to seed addresses: %v", err)
+		}
+	}
+
+	return db
+}
+
+// tomorrowAt returns tomorrow's date at the given local hour and minute
+func tomorrowAt(hour, minute int) time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, hour, minute, 0, 0, now.Location())
+}
+
+// newReservationUseCase returns a reservation use case for payments recorded without a checkout,
+// whose refunds never reach a payment provider
+func newReservationUseCase(db *gorm.DB) *reservationUsecases.ReservationUseCase {
+	return newReservationUseCaseAt(db, time.Now)
+}
+
+// newReservationUseCaseAt returns a reservation use case like newReservationUseCase, reading the time from now
+func newReservationUseCaseAt(db *gorm.DB, now common.Clock) *reservationUsecases.ReservationUseCase {
+	return newReservationUseCaseWithProvider(db, mercadopago.NewProvider(mercadopago.Config{BaseURL: "http://mercadopago.invalid"}), now)
+}
+
+// newReservationUseCaseWithProvider returns a reservation use case refunding cancellations through provider
+func newReservationUseCaseWithProvider(db *gorm.DB, provider paymentUsecases.PaymentProvider, now common.Clock) *reservationUsecases.ReservationUseCase {
+	return newReservationUseCaseWithLedger(db, paymentUsecases.NewRefundUseCase(paymentRepos.NewPaymentRepository(db), paymentRepos.NewRefundRepository(db), provider), now)
+}
+
+// newReservationUseCaseWithLedger returns a reservation use case reading and refunding payments through payments
+func newReservationUseCaseWithLedger(db *gorm.DB, payments reservationUsecases.PaymentLedger, now common.Clock) *reservationUsecases.ReservationUseCase {
+	availability := usecases.NewAvailabilityUseCase(&mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+	}, time.Local, time.Hour, time.Now)
+	services := serviceUsecases.NewServiceUseCase(serviceRepos.NewServiceRepository(db))
+	vehicles := clientUsecases.NewVehicleUseCase(clientRepos.NewVehicleRepository(db))
+	addresses := addressUsecases.NewAddressUseCase(addressRepos.NewAddressRepository(db), clientUsecases.NewClientUseCase(clientRepos.NewClientRepository(db)))
+	policy := reservationModels.CancellationPolicy{
+		FreeCancellationWindow:     24 * time.Hour,
+		LateCancellationFeePercent: 50,
+	}
+	pricing := reservationModels.PricingPolicy{
+		SizeSurchargePercent: map[clientModels.VehicleSize]int{
+			clientModels.VehicleSizeSUV: 20,
+			clientModels.VehicleSizeVan: 40,
+		},
+	}
+	return reservationUsecases.NewReservationUseCase(reservationRepos.NewReservationRepository(db), availability, services, vehicles, addresses, payments, policy, pricing, 10*time.Minute, now)
+}
+
+func TestCreateReservation_Success(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+
+	reservation, err := uc.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 30),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reservation.ID == 0 {
+		t.Error("expected reservation to be persisted with an ID")
+	}
+	if reservation.Status != reservationModels.ReservationStatusPending {
+		t.Errorf("expected status pending, got %s", reservation.Status)
+	}
+}
+
+func TestCreateReservation_InvalidStartTime(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+
+	cases := map[string]time.Time{
+		"before opening": tomorrowAt(7, 30),
+		"at closing":     tomorrowAt(22, 0),
+		"off grid":       tomorrowAt(10, 15),
+		"in the past":    tomorrowAt(10, 0).AddDate(0, 0, -2),
+	}
+
+	for name, startTime := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+				UserID:    1,
+				SlotID:    1,
+				AddressID: 1,
+				StartTime: startTime,
+			})
+			if !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateReservation_UnknownSlot(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+
+	_, err := uc.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    99,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+	})
+	if !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Errorf("expected ErrSlotNotAvailable, got %v", err)
+	}
+}
+
+func TestCreateReservation_RejectsOthersAddress(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+
+	cases := map[string]uint{
+		"another client's": 2,
+		"unknown":          999,
+	}
+
+	for name, addressID := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := uc.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+				UserID:    1,
+				SlotID:    1,
+				AddressID: addressID,
+				StartTime: tomorrowAt(10, 0),
+			})
+			if !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateReservation_DoubleBooking(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+
+	input := reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(9, 0),
+	}
+
+	if _, err := uc.CreateReservation(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error on first booking: %v", err)
+	}
+
+	input.UserID, input.AddressID = 2, 2
+	_, err := uc.CreateReservation(context.Background(), input)
+	if !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Errorf("expected ErrSlotNotAvailable, got %v", err)
+	}
+	if common.MapErrorToHTTPStatus(err) != 409 {
+		t.Errorf("expected 409, got %d", common.MapErrorToHTTPStatus(err))
+	}
+}
+
+func TestCreateReservation_ConcurrentRequestsOnlyOneWins(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	startTime := tomorrowAt(11, 0)
+
+	const attempts = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			_, err := uc.CreateReservation(context.Background(), reservationUsecases.CreateReservationInput{
+				UserID:    userID,
+				SlotID:    1,
+				AddressID: userID,
+				StartTime: startTime,
+			})
+			errs <- err
+		}(uint(i + 1))
+	}
+
+	wg.Wait()
+	close(errs)
+
+	successes := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			successes++
+		case errors.Is(err, common.ErrSlotNotAvailable):
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if successes != 1 {
+		t.Errorf("expected exactly 1 successful booking, got %d", successes)
+	}
+}
+
+func TestReservationStatus_Transitions(t *testing.T) {
+	cases := []struct {
+		from, to reservationModels.ReservationStatus
+		allowed  bool
+	}{
+		{reservationModels.ReservationStatusPending, reservationModels.ReservationStatusConfirmed, true},
+		{reservationModels.ReservationStatusPending, reservationModels.ReservationStatusCancelled, true},
+		{reservationModels.ReservationStatusPending, reservationModels.ReservationStatusCompleted, false},
+		{reservationModels.ReservationStatusConfirmed, reservationModels.ReservationStatusCompleted, true},
+		{reservationModels.ReservationStatusConfirmed, reservationModels.ReservationStatusCancelled, true},
+		{reservationModels.ReservationStatusConfirmed, reservationModels.ReservationStatusPending, false},
+		{reservationModels.ReservationStatusCancelled, reservationModels.ReservationStatusPending, false},
+		{reservationModels.ReservationStatusCancelled, reservationModels.ReservationStatusConfirmed, false},
+		{reservationModels.ReservationStatusCompleted, reservationModels.ReservationStatusCancelled, false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.allowed {
+			t.Errorf("%s -> %s: expected allowed=%v, got %v", tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
+
+func TestReservationStatus_LifecycleRecordsHistory(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	reservation, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(12, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actor := reservationUsecases.StatusChangeInput{ChangedBy: "staff@lavalo", Reason: "paid"}
+	if _, err := uc.ConfirmReservation(ctx, reservation.ID, actor); err != nil {
+		t.Fatalf("confirm failed: %v", err)
+	}
+	if _, err := uc.CompleteReservation(ctx, reservation.ID, actor); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+
+	// Completed reservations cannot be cancelled
+	_, err = uc.CancelReservation(ctx, reservation.ID, actor)
+	if !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	history, err := uc.GetStatusHistory(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 status changes, got %d", len(history))
+	}
+	if history[1].FromStatus != reservationModels.ReservationStatusConfirmed ||
+		history[1].ToStatus != reservationModels.ReservationStatusCompleted {
+		t.Errorf("unexpected change: %s -> %s", history[1].FromStatus, history[1].ToStatus)
+	}
+	if history[0].ChangedBy != "staff@lavalo" || history[0].ChangedAt.IsZero() {
+		t.Errorf("expected change to record who and when, got %+v", history[0])
+	}
+}
+
+func TestReservationStatus_CancelledIsNotResurrected(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	reservation, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(13, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := uc.CancelReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{}); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+
+	_, err = uc.ConfirmReservation(ctx, reservation.ID, reservationUsecases.StatusChangeInput{})
+	if !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+
+	_, err = uc.ConfirmReservation(ctx, 999, reservationUsecases.StatusChangeInput{})
+	if !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRescheduleReservation_MovesAndKeepsHistory(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	ctx := context.Background()
+
+	reservation, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(14, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	payment := paymentModels.Payment{ReservationID: reservation.ID, Amount: common.NewMoney(50000, "ARS"), Status: paymentModels.PaymentStatusCompleted}
+	if err := db.Create(&payment).Error; err != nil {
+		t.Fatalf("failed to seed payment: %v", err)
+	}
+
+	newStart := tomorrowAt(16, 30)
+	moved, err := uc.RescheduleReservation(ctx, reservation.ID, reservationUsecases.RescheduleReservationInput{
+		SlotID:    1,
+		StartTime: newStart,
+		ChangedBy: "customer",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved.ID != reservation.ID || !moved.StartTime.Equal(newStart) {
+		t.Errorf("expected reservation %d at %s, got %d at %s", reservation.ID, newStart, moved.ID, moved.StartTime)
+	}
+
+	// The old time is free again
+	if _, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    2,
+		SlotID:    1,
+		AddressID: 2,
+		StartTime: tomorrowAt(14, 0),
+	}); err != nil {
+		t.Errorf("expected previous time to be free, got %v", err)
+	}
+
+	var stored paymentModels.Payment
+	if err := db.First(&stored, payment.ID).Error; err != nil || stored.ReservationID != reservation.ID {
+		t.Errorf("expected payment to stay linked to reservation %d", reservation.ID)
+	}
+
+	history, err := uc.GetRescheduleHistory(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || !history[0].FromStartTime.Equal(tomorrowAt(14, 0)) {
+		t.Errorf("expected one history entry from 14:00, got %+v", history)
+	}
+}
+
+func TestRescheduleReservation_Conflicts(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	first, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, StartTime: tomorrowAt(15, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 2, SlotID: 1, AddressID: 2, StartTime: tomorrowAt(15, 30),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = uc.RescheduleReservation(ctx, first.ID, reservationUsecases.RescheduleReservationInput{
+		SlotID: 1, StartTime: second.StartTime,
+	})
+	if !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Errorf("expected ErrSlotNotAvailable, got %v", err)
+	}
+
+	if _, err := uc.CancelReservation(ctx, first.ID, reservationUsecases.StatusChangeInput{}); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+
+	_, err = uc.RescheduleReservation(ctx, first.ID, reservationUsecases.RescheduleReservationInput{
+		SlotID: 1, StartTime: tomorrowAt(17, 0),
+	})
+	if !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected ErrConflict for cancelled reservation, got %v", err)
+	}
+}
+
+func TestCreateReservation_ServiceDurationOverlaps(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	ctx := context.Background()
+
+	detail := serviceModels.Service{Name: "Detallado completo", DurationMins: 120, BasePrice: common.NewMoney(3000000, "ARS"), IsActive: true}
+	if err := db.Create(&detail).Error; err != nil {
+		t.Fatalf("failed to seed service: %v", err)
+	}
+
+	reservation, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, ServiceID: detail.ID, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reservation.EndTime.Equal(tomorrowAt(12, 0)) {
+		t.Errorf("expected end time 12:00, got %s", reservation.EndTime)
+	}
+
+	// 11:30 falls inside the 10:00-12:00 detail
+	_, err = uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 2, SlotID: 1, AddressID: 2, StartTime: tomorrowAt(11, 30),
+	})
+	if !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Errorf("expected ErrSlotNotAvailable, got %v", err)
+	}
+
+	// A detail at 09:00 would run into the 10:00 booking
+	_, err = uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 2, SlotID: 1, AddressID: 2, ServiceID: detail.ID, StartTime: tomorrowAt(9, 0),
+	})
+	if !errors.Is(err, common.ErrSlotNotAvailable) {
+		t.Errorf("expected ErrSlotNotAvailable, got %v", err)
+	}
+
+	// A detail at 20:30 would end after closing
+	_, err = uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 2, SlotID: 1, AddressID: 2, ServiceID: detail.ID, StartTime: tomorrowAt(20, 30),
+	})
+	if !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+
+	// 12:00 starts right as the detail ends
+	if _, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 2, SlotID: 1, AddressID: 2, StartTime: tomorrowAt(12, 0),
+	}); err != nil {
+		t.Errorf("expected 12:00 to be free, got %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	paymentModels "github.com/Jose-Ig/lavalo-backend/internal/payments/domain/models"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+)
+
+func TestCreateSeries_ReportsConflicts(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	start := tomorrowAt(10, 0)
+	taken := start.AddDate(0, 0, 14)
+	if _, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    2,
+		SlotID:    1,
+		AddressID: 2,
+		StartTime: taken,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := uc.CreateSeries(ctx, reservationUsecases.CreateSeriesInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: start,
+		Frequency: reservationModels.RecurrenceWeekly,
+		Count:     4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Reservations) != 3 {
+		t.Fatalf("expected 3 booked occurrences, got %d", len(result.Reservations))
+	}
+	for i, want := range []int{0, 7, 21} {
+		got := result.Reservations[i]
+		if !got.StartTime.Equal(start.AddDate(0, 0, want)) || got.SeriesID == nil || *got.SeriesID != result.Series.ID {
+			t.Errorf("occurrence %d: unexpected reservation %+v", i, got)
+		}
+	}
+	if len(result.Conflicts) != 1 || !result.Conflicts[0].StartTime.Equal(taken) {
+		t.Errorf("expected the taken week to be reported, got %+v", result.Conflicts)
+	}
+
+	stored, err := uc.GetSeries(ctx, result.Series.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Series.Status != reservationModels.SeriesStatusActive || len(stored.Reservations) != 3 {
+		t.Errorf("expected an active series with 3 occurrences, got %+v", stored)
+	}
+}
+
+func TestCreateSeries_Until(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	start := tomorrowAt(9, 0)
+	until := start.AddDate(0, 0, 42)
+	result, err := uc.CreateSeries(ctx, reservationUsecases.CreateSeriesInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: start,
+		Frequency: reservationModels.RecurrenceBiweekly,
+		Until:     &until,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Days 0, 14, 28 and 42: until is inclusive
+	if len(result.Reservations) != 4 || len(result.Conflicts) != 0 {
+		t.Fatalf("expected 4 occurrences without conflicts, got %d (%+v)", len(result.Reservations), result.Conflicts)
+	}
+	if last := result.Reservations[3].StartTime; !last.Equal(until) {
+		t.Errorf("expected the last occurrence on until, got %s", last)
+	}
+}
+
+func TestCreateSeries_Validation(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	start := tomorrowAt(10, 0)
+	before := start.AddDate(0, 0, -1)
+	farAway := start.AddDate(2, 0, 0)
+	valid := reservationUsecases.CreateSeriesInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: start,
+		Frequency: reservationModels.RecurrenceWeekly,
+		Count:     2,
+	}
+
+	cases := map[string]func(in *reservationUsecases.CreateSeriesInput){
+		"missing user":       func(in *reservationUsecases.CreateSeriesInput) { in.UserID = 0 },
+		"unknown frequency":  func(in *reservationUsecases.CreateSeriesInput) { in.Frequency = "daily" },
+		"no end":             func(in *reservationUsecases.CreateSeriesInput) { in.Count = 0 },
+		"until and count":    func(in *reservationUsecases.CreateSeriesInput) { in.Until = &farAway },
+		"count too large":    func(in *reservationUsecases.CreateSeriesInput) { in.Count = 53 },
+		"until before start": func(in *reservationUsecases.CreateSeriesInput) { in.Count = 0; in.Until = &before },
+		"too many until":     func(in *reservationUsecases.CreateSeriesInput) { in.Count = 0; in.Until = &farAway },
+		"off the grid":       func(in *reservationUsecases.CreateSeriesInput) { in.StartTime = tomorrowAt(10, 15) },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := valid
+			mutate(&input)
+			if _, err := uc.CreateSeries(ctx, input); !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCancelSeries(t *testing.T) {
+	uc := newReservationUseCase(newTestDB(t))
+	ctx := context.Background()
+
+	result, err := uc.CreateSeries(ctx, reservationUsecases.CreateSeriesInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+		Frequency: reservationModels.RecurrenceMonthly,
+		Count:     3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Cancelling one occurrence leaves the rest of the series booked
+	single := result.Reservations[1].ID
+	if _, err := uc.CancelReservation(ctx, single, reservationUsecases.StatusChangeInput{ChangedBy: "customer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := uc.GetSeries(ctx, result.Series.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Series.Status != reservationModels.SeriesStatusActive || !stored.Reservations[2].IsActive() {
+		t.Fatalf("expected the series to stay active, got %+v", stored)
+	}
+
+	cancelled, err := uc.CancelSeries(ctx, result.Series.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Series.Status != reservationModels.SeriesStatusCancelled {
+		t.Errorf("expected the series to be cancelled, got %s", cancelled.Series.Status)
+	}
+	for _, r := range cancelled.Reservations {
+		if r.Status != reservationModels.ReservationStatusCancelled {
+			t.Errorf("reservation %d: expected cancelled, got %s", r.ID, r.Status)
+		}
+	}
+
+	if _, err := uc.CancelSeries(ctx, result.Series.ID, reservationUsecases.StatusChangeInput{}); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected cancelling twice to conflict, got %v", err)
+	}
+	if _, err := uc.GetSeries(ctx, 999); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRecurrenceMonthly_ClampsToMonthEnd(t *testing.T) {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	start := time.Date(2027, time.January, 31, 9, 30, 0, 0, loc)
+	for n, want := range []time.Time{
+		start,
+		time.Date(2027, time.February, 28, 9, 30, 0, 0, loc),
+		time.Date(2027, time.March, 31, 9, 30, 0, 0, loc),
+		time.Date(2027, time.April, 30, 9, 30, 0, 0, loc),
+		time.Date(2027, time.May, 31, 9, 30, 0, 0, loc),
+	} {
+		if got := reservationModels.RecurrenceMonthly.Occurrence(start, n); !got.Equal(want) {
+			t.Errorf("occurrence %d: expected %s, got %s", n, want, got)
+		}
+	}
+
+	leap := time.Date(2028, time.January, 30, 9, 30, 0, 0, loc)
+	if got, want := reservationModels.RecurrenceMonthly.Occurrence(leap, 1), time.Date(2028, time.February, 29, 9, 30, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("expected %s in a leap year, got %s", want, got)
+	}
+	if got, want := reservationModels.RecurrenceMonthly.Occurrence(start, 12), time.Date(2028, time.January, 31, 9, 30, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("expected %s a year later, got %s", want, got)
+	}
+}
+
+func TestCancelSeries_ResumesAfterFailure(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	ctx := context.Background()
+
+	result, err := uc.CreateSeries(ctx, reservationUsecases.CreateSeriesInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+		Frequency: reservationModels.RecurrenceWeekly,
+		Count:     3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without the cancellations table no occurrence can be cancelled
+	if err := db.Migrator().DropTable(&reservationModels.ReservationCancellation{}); err != nil {
+		t.Fatalf("failed to drop table: %v", err)
+	}
+	partial, err := uc.CancelSeries(ctx, result.Series.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"})
+	if err != nil {
+		t.Fatalf("expected the failures to be reported, got %v", err)
+	}
+	if len(partial.Failed) != 3 || partial.Failed[0].ReservationID != result.Reservations[0].ID {
+		t.Fatalf("expected every occurrence to be reported as failed, got %+v", partial.Failed)
+	}
+	if partial.Series.Status != reservationModels.SeriesStatusActive || !partial.Reservations[0].IsActive() {
+		t.Fatalf("expected the series and its occurrences to stay active, got %+v", partial)
+	}
+
+	// Cancelling again once the cause is fixed finishes the job
+	if err := db.AutoMigrate(&reservationModels.ReservationCancellation{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	cancelled, err := uc.CancelSeries(ctx, result.Series.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cancelled.Failed) != 0 || cancelled.Series.Status != reservationModels.SeriesStatusCancelled {
+		t.Fatalf("expected the series to be cancelled, got %+v", cancelled)
+	}
+	for _, r := range cancelled.Reservations {
+		if r.Status != reservationModels.ReservationStatusCancelled {
+			t.Errorf("reservation %d: expected cancelled, got %s", r.ID, r.Status)
+		}
+	}
+}
+
+func TestCancelSeries_ReportsConflictsOnActiveOccurrences(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	ctx := context.Background()
+
+	result, err := uc.CreateSeries(ctx, reservationUsecases.CreateSeriesInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+		Frequency: reservationModels.RecurrenceWeekly,
+		Count:     2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Payments in two currencies cannot be added up, so the occurrence conflicts while still active
+	stuck := result.Reservations[0].ID
+	for _, amount := range []common.Money{ars(1000), common.NewMoney(1000, "USD")} {
+		payment := paymentModels.Payment{ReservationID: stuck, Amount: amount, Status: paymentModels.PaymentStatusCompleted}
+		if err := db.Create(&payment).Error; err != nil {
+			t.Fatalf("failed to create payment: %v", err)
+		}
+	}
+
+	partial, err := uc.CancelSeries(ctx, result.Series.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"})
+	if err != nil {
+		t.Fatalf("expected the conflict to be reported, got %v", err)
+	}
+	if len(partial.Failed) != 1 || partial.Failed[0].ReservationID != stuck {
+		t.Fatalf("expected reservation %d to be reported as failed, got %+v", stuck, partial.Failed)
+	}
+	if partial.Series.Status != reservationModels.SeriesStatusActive {
+		t.Errorf("expected the series to stay active, got %s", partial.Series.Status)
+	}
+	for _, r := range partial.Reservations {
+		if want := r.ID == stuck; r.IsActive() != want {
+			t.Errorf("reservation %d: unexpected status %s", r.ID, r.Status)
+		}
+	}
+}
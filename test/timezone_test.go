@@ -0,0 +1,161 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	reservationModels "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/models"
+	"github.com/Jose-Ig/lavalo-backend/internal/slots/domain/usecases"
+	slotRepos "github.com/Jose-Ig/lavalo-backend/internal/slots/infrastructure/repositories"
+)
+
+// buenosAires loads the default business location, skipping the test without tz data
+func buenosAires(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestGetWeekAvailability_UsesBusinessDate(t *testing.T) {
+	loc := buenosAires(t)
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+	}
+
+	// 01:00 UTC on March 10 is still 22:00 on March 9 in Buenos Aires
+	now := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	uc := usecases.NewAvailabilityUseCase(repo, loc, 0, fixedClock(now))
+
+	result, err := uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, dateKey := range []string{"2026-03-09", "2026-03-16"} {
+		if _, ok := result[dateKey]; !ok {
+			t.Errorf("expected business date %s in response", dateKey)
+		}
+	}
+	if _, ok := result["2026-03-17"]; ok {
+		t.Error("did not expect 2026-03-17, the window starts on the Buenos Aires date")
+	}
+}
+
+func TestGetWeekAvailability_UTCReservationKeyedInBusinessZone(t *testing.T) {
+	loc := buenosAires(t)
+
+	// 21:30 on March 10 in Buenos Aires is 00:30 on March 11 in UTC
+	local := time.Date(2026, 3, 10, 21, 30, 0, 0, loc)
+	start := local.UTC()
+
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+		reservations: []reservationModels.Reservation{
+			{SlotID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: reservationModels.ReservationStatusConfirmed},
+		},
+	}
+
+	uc := usecases.NewAvailabilityUseCase(repo, loc, 0, fixedClock(time.Date(2026, 3, 9, 6, 0, 0, 0, loc)))
+	result, err := uc.GetWeekAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dayAvail := result["2026-03-10"]
+	if hourAvailable(t, dayAvail, "21:30") {
+		t.Error("expected 21:30 to be reserved on the Buenos Aires date")
+	}
+	if !hourAvailable(t, dayAvail, "21:00") {
+		t.Error("expected 21:00 to stay available")
+	}
+
+	if !hourAvailable(t, result["2026-03-11"], "08:00") {
+		t.Error("expected the reservation not to leak into the next day")
+	}
+}
+
+func TestValidateStartTime_UsesBusinessWeekday(t *testing.T) {
+	loc := buenosAires(t)
+	repo := &mockAvailabilityRepository{
+		slots: []models.Slot{
+			{ID: 1, Label: "Espacio 1", IsAvailable: true},
+		},
+		hours: []models.BusinessHours{
+			{Weekday: time.Sunday, IsClosed: true},
+		},
+	}
+
+	uc := usecases.NewAvailabilityUseCase(repo, loc, 0, fixedClock(time.Date(2026, 3, 2, 6, 0, 0, 0, loc)))
+	ctx := context.Background()
+
+	// Monday 01:00 UTC is Sunday 22:00 in Buenos Aires
+	sundayNight := time.Date(2026, 3, 9, 1, 0, 0, 0, time.UTC)
+	if err := uc.ValidateStartTime(ctx, 1, sundayNight, 30*time.Minute); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected Sunday in Buenos Aires to be rejected, got %v", err)
+	}
+
+	// Monday 12:00 UTC is Monday 09:00 in Buenos Aires
+	mondayMorning := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	if err := uc.ValidateStartTime(ctx, 1, mondayMorning, 30*time.Minute); err != nil {
+		t.Errorf("expected Monday 09:00 in Buenos Aires to be bookable, got %v", err)
+	}
+
+	// Tuesday 00:30 UTC is Monday 21:30 in Buenos Aires: a one hour booking ends after closing
+	lateMonday := time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC)
+	if err := uc.ValidateStartTime(ctx, 1, lateMonday, time.Hour); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected a booking past 22:00 in Buenos Aires to be rejected, got %v", err)
+	}
+}
+
+func TestFindReservationsByDateRange_AcrossUTCMidnight(t *testing.T) {
+	loc := buenosAires(t)
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// Stored as 00:30 UTC on March 11
+	start := time.Date(2026, 3, 10, 21, 30, 0, 0, loc)
+	if err := db.Create(&reservationModels.Reservation{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: start,
+		EndTime:   start.Add(30 * time.Minute),
+		Status:    reservationModels.ReservationStatusPending,
+	}).Error; err != nil {
+		t.Fatalf("failed to create reservation: %v", err)
+	}
+
+	repo := slotRepos.NewAvailabilityRepository(db)
+
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, loc)
+	found, err := repo.FindReservationsByDateRange(ctx, day, day.AddDate(0, 0, 1), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected the reservation on March 10 in Buenos Aires, got %d", len(found))
+	}
+	if !found[0].StartTime.Equal(start) {
+		t.Errorf("expected start %s, got %s", start, found[0].StartTime)
+	}
+
+	next := day.AddDate(0, 0, 1)
+	found, err = repo.FindReservationsByDateRange(ctx, next, next.AddDate(0, 0, 1), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("expected no reservations on March 11 in Buenos Aires, got %d", len(found))
+	}
+}
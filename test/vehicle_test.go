@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	clientModels "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/models"
+	clientUsecases "github.com/Jose-Ig/lavalo-backend/internal/clients/domain/usecases"
+	clientRepos "github.com/Jose-Ig/lavalo-backend/internal/clients/infrastructure/repositories"
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
+)
+
+func TestCreateVehicle(t *testing.T) {
+	uc := clientUsecases.NewVehicleUseCase(clientRepos.NewVehicleRepository(newTestDB(t)))
+	ctx := context.Background()
+
+	vehicle, err := uc.CreateVehicle(ctx, clientUsecases.VehicleInput{ClientID: 1, Plate: "ab 123-cd", Make: "Toyota", Model: "Hilux"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vehicle.Plate != "AB123CD" || vehicle.Size != clientModels.VehicleSizeCar {
+		t.Errorf("expected a normalized plate and the default size, got %q %q", vehicle.Plate, vehicle.Size)
+	}
+
+	cases := map[string]struct {
+		input clientUsecases.VehicleInput
+		want  error
+	}{
+		"missing client":  {clientUsecases.VehicleInput{Plate: "ABC123"}, common.ErrInvalidInput},
+		"unknown client":  {clientUsecases.VehicleInput{ClientID: 999, Plate: "ABC123"}, common.ErrInvalidInput},
+		"short plate":     {clientUsecases.VehicleInput{ClientID: 1, Plate: "AB12"}, common.ErrInvalidInput},
+		"symbols":         {clientUsecases.VehicleInput{ClientID: 1, Plate: "AB#123"}, common.ErrInvalidInput},
+		"unknown size":    {clientUsecases.VehicleInput{ClientID: 1, Plate: "ABC123", Size: "truck"}, common.ErrInvalidInput},
+		"duplicate plate": {clientUsecases.VehicleInput{ClientID: 2, Plate: "AB123CD"}, common.ErrConflict},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.CreateVehicle(ctx, tc.input); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	vehicles, err := uc.ListClientVehicles(ctx, 1)
+	if err != nil || len(vehicles) != 1 {
+		t.Errorf("expected client 1 to have 1 vehicle, got %d (%v)", len(vehicles), err)
+	}
+}
+
+func TestCreateReservation_PricedByVehicleSize(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	vehicles := clientUsecases.NewVehicleUseCase(clientRepos.NewVehicleRepository(db))
+	ctx := context.Background()
+
+	wash := serviceModels.Service{Name: "Lavado básico", DurationMins: 30, BasePrice: common.NewMoney(1000000, "ARS"), IsActive: true}
+	if err := db.Create(&wash).Error; err != nil {
+		t.Fatalf("failed to seed service: %v", err)
+	}
+
+	van, err := vehicles.CreateVehicle(ctx, clientUsecases.VehicleInput{ClientID: 1, Plate: "AA111AA", Size: clientModels.VehicleSizeVan})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherClients, err := vehicles.CreateVehicle(ctx, clientUsecases.VehicleInput{ClientID: 2, Plate: "BB222BB"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]struct {
+		hour      int
+		vehicleID uint
+		want      common.Money
+	}{
+		"no vehicle": {10, 0, common.NewMoney(1000000, "ARS")},
+		"van":        {11, van.ID, common.NewMoney(1400000, "ARS")},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			booked, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+				UserID: 1, SlotID: 1, AddressID: 1, ServiceID: wash.ID, VehicleID: tc.vehicleID, StartTime: tomorrowAt(tc.hour, 0),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !booked.Price.Equal(tc.want) {
+				t.Errorf("expected price %s, got %s", tc.want, booked.Price)
+			}
+		})
+	}
+
+	_, err = uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, VehicleID: otherClients.ID, StartTime: tomorrowAt(12, 0),
+	})
+	if !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected booking another client's vehicle to be rejected, got %v", err)
+	}
+}
+
+func TestGetReservation_IncludesVehicle(t *testing.T) {
+	db := newTestDB(t)
+	uc := newReservationUseCase(db)
+	vehicles := clientUsecases.NewVehicleUseCase(clientRepos.NewVehicleRepository(db))
+	ctx := context.Background()
+
+	suv, err := vehicles.CreateVehicle(ctx, clientUsecases.VehicleInput{ClientID: 1, Plate: "AC333CC", Make: "Jeep", Size: clientModels.VehicleSizeSUV})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	booked, err := uc.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, VehicleID: suv.ID, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Staff still see the vehicle after the client removes it
+	if err := vehicles.DeleteVehicle(ctx, suv.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err := uc.GetReservation(ctx, booked.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found.Vehicle == nil || found.Vehicle.Plate != "AC333CC" || found.Vehicle.Make != "Jeep" {
+		t.Errorf("expected the reservation to include the vehicle, got %+v", found.Vehicle)
+	}
+}
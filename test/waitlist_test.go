@@ -0,0 +1,252 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Jose-Ig/lavalo-backend/internal/common"
+	reservationUsecases "github.com/Jose-Ig/lavalo-backend/internal/reservations/domain/usecases"
+	serviceModels "github.com/Jose-Ig/lavalo-backend/internal/services/domain/models"
+	waitlistModels "github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/models"
+	waitlistUsecases "github.com/Jose-Ig/lavalo-backend/internal/waitlist/domain/usecases"
+	waitlistRepos "github.com/Jose-Ig/lavalo-backend/internal/waitlist/infrastructure/repositories"
+)
+
+// recordingNotifier collects waitlist offers instead of delivering them
+type recordingNotifier struct {
+	offers []waitlistModels.WaitlistOfferEvent
+}
+
+func (n *recordingNotifier) NotifyOffer(ctx context.Context, event waitlistModels.WaitlistOfferEvent) error {
+	n.offers = append(n.offers, event)
+	return nil
+}
+
+// newWaitlistTest wires a waitlist to a reservation use case over one test database
+func newWaitlistTest(t *testing.T) (*gorm.DB, *reservationUsecases.ReservationUseCase, *waitlistUsecases.WaitlistUseCase, *recordingNotifier) {
+	t.Helper()
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&waitlistModels.WaitlistEntry{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	reservations := newReservationUseCase(db)
+	notifier := &recordingNotifier{}
+	waitlist := waitlistUsecases.NewWaitlistUseCase(waitlistRepos.NewWaitlistRepository(db), reservations, notifier, time.Now)
+	reservations.AddSlotReleaseListener(waitlist)
+	reservations.AddConfirmationListener(waitlist)
+
+	return db, reservations, waitlist, notifier
+}
+
+func TestJoinWaitlist_Validation(t *testing.T) {
+	db, reservations, waitlist, _ := newWaitlistTest(t)
+	ctx := context.Background()
+
+	zero := uint(0)
+	cases := map[string]waitlistUsecases.JoinWaitlistInput{
+		"missing user":    {AddressID: 1, StartTime: tomorrowAt(10, 0)},
+		"missing time":    {UserID: 1, AddressID: 1},
+		"in the past":     {UserID: 1, AddressID: 1, StartTime: time.Now().Add(-time.Hour)},
+		"zero slot ID":    {UserID: 1, AddressID: 1, SlotID: &zero, StartTime: tomorrowAt(10, 0)},
+		"missing address": {UserID: 1, StartTime: tomorrowAt(10, 0)},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := waitlist.JoinWaitlist(ctx, input); !errors.Is(err, common.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+
+	input := waitlistUsecases.JoinWaitlistInput{UserID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0)}
+	if _, err := waitlist.JoinWaitlist(ctx, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := waitlist.JoinWaitlist(ctx, input); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected joining twice to conflict, got %v", err)
+	}
+
+	// The start time is judged against the injected clock
+	later := waitlistUsecases.NewWaitlistUseCase(waitlistRepos.NewWaitlistRepository(db), reservations, &recordingNotifier{}, fixedClock(tomorrowAt(12, 0)))
+	if _, err := later.JoinWaitlist(ctx, waitlistUsecases.JoinWaitlistInput{UserID: 2, AddressID: 2, StartTime: tomorrowAt(11, 0)}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("expected a start time before the clock to be rejected, got %v", err)
+	}
+}
+
+func TestWaitlist_CancellationOffersHoldInOrder(t *testing.T) {
+	db, reservations, waitlist, notifier := newWaitlistTest(t)
+	ctx := context.Background()
+
+	booked, err := reservations.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID:    1,
+		SlotID:    1,
+		AddressID: 1,
+		StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	otherSlot := uint(2)
+	sameSlot := uint(1)
+	join := func(userID uint, slotID *uint) *waitlistModels.WaitlistEntry {
+		entry, err := waitlist.JoinWaitlist(ctx, waitlistUsecases.JoinWaitlistInput{
+			UserID:    userID,
+			AddressID: userID,
+			SlotID:    slotID,
+			StartTime: tomorrowAt(10, 0),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return entry
+	}
+
+	wrongSlot := join(2, &otherSlot)
+	first := join(3, nil)
+	second := join(4, &sameSlot)
+
+	if _, err := reservations.CancelReservation(ctx, booked.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.offers) != 1 || notifier.offers[0].EntryID != first.ID {
+		t.Fatalf("expected entry %d to be offered the slot, got %+v", first.ID, notifier.offers)
+	}
+
+	offered, err := waitlist.GetEntry(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if offered.Status != waitlistModels.WaitlistStatusOffered || offered.OfferedReservationID == nil {
+		t.Fatalf("expected entry to be offered, got %+v", offered)
+	}
+
+	hold, err := reservations.GetReservation(ctx, *offered.OfferedReservationID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hold.IsHold() || hold.UserID != 3 || hold.SlotID != 1 || !hold.StartTime.Equal(tomorrowAt(10, 0)) {
+		t.Errorf("expected a hold for user 3 on slot 1 at 10:00, got %+v", hold)
+	}
+	if !notifier.offers[0].ExpiresAt.Equal(*hold.ExpiresAt) {
+		t.Errorf("expected the offer to carry the hold expiry")
+	}
+
+	// The offer lapses: the next customer in line gets the slot
+	expireHold(t, db, hold.ID)
+	if _, err := reservations.ReleaseExpiredHolds(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.offers) != 2 || notifier.offers[1].EntryID != second.ID {
+		t.Fatalf("expected entry %d to be offered the slot next, got %+v", second.ID, notifier.offers)
+	}
+
+	for id, want := range map[uint]waitlistModels.WaitlistStatus{
+		wrongSlot.ID: waitlistModels.WaitlistStatusWaiting,
+		first.ID:     waitlistModels.WaitlistStatusExpired,
+		second.ID:    waitlistModels.WaitlistStatusOffered,
+	} {
+		entry, err := waitlist.GetEntry(ctx, id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry.Status != want {
+			t.Errorf("entry %d: expected %s, got %s", id, want, entry.Status)
+		}
+	}
+
+	// Confirming the offered hold fulfils the entry
+	next, err := waitlist.GetEntry(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := reservations.ConfirmReservation(ctx, *next.OfferedReservationID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fulfilled, err := waitlist.GetEntry(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fulfilled.Status != waitlistModels.WaitlistStatusFulfilled {
+		t.Errorf("expected the entry to be fulfilled, got %s", fulfilled.Status)
+	}
+}
+
+func TestWaitlist_CancellationOffersEveryFreedTime(t *testing.T) {
+	db, reservations, waitlist, notifier := newWaitlistTest(t)
+	ctx := context.Background()
+
+	detail := serviceModels.Service{Name: "Detallado completo", DurationMins: 120, BasePrice: common.NewMoney(3000000, "ARS"), IsActive: true}
+	if err := db.Create(&detail).Error; err != nil {
+		t.Fatalf("failed to seed service: %v", err)
+	}
+
+	booked, err := reservations.CreateReservation(ctx, reservationUsecases.CreateReservationInput{
+		UserID: 1, SlotID: 1, AddressID: 1, ServiceID: detail.ID, StartTime: tomorrowAt(10, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	join := func(userID uint, start time.Time) *waitlistModels.WaitlistEntry {
+		entry, err := waitlist.JoinWaitlist(ctx, waitlistUsecases.JoinWaitlistInput{UserID: userID, AddressID: userID, StartTime: start})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return entry
+	}
+
+	first := join(2, tomorrowAt(10, 0))
+	overlapping := join(3, tomorrowAt(10, 0))
+	later := join(4, tomorrowAt(11, 0))
+
+	// The two hours freed fit two 30 minute washes at different times
+	if _, err := reservations.CancelReservation(ctx, booked.ID, reservationUsecases.StatusChangeInput{ChangedBy: "customer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.offers) != 2 || notifier.offers[0].EntryID != first.ID || notifier.offers[1].EntryID != later.ID {
+		t.Fatalf("expected entries %d and %d to be offered the slot, got %+v", first.ID, later.ID, notifier.offers)
+	}
+
+	entry, err := waitlist.GetEntry(ctx, overlapping.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Status != waitlistModels.WaitlistStatusWaiting {
+		t.Errorf("expected the entry whose time went to an earlier one to keep waiting, got %s", entry.Status)
+	}
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	_, _, waitlist, _ := newWaitlistTest(t)
+	ctx := context.Background()
+
+	entry, err := waitlist.JoinWaitlist(ctx, waitlistUsecases.JoinWaitlistInput{UserID: 1, AddressID: 1, StartTime: tomorrowAt(10, 0)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	left, err := waitlist.LeaveWaitlist(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if left.Status != waitlistModels.WaitlistStatusCancelled {
+		t.Errorf("expected cancelled, got %s", left.Status)
+	}
+
+	if _, err := waitlist.LeaveWaitlist(ctx, entry.ID); !errors.Is(err, common.ErrConflict) {
+		t.Errorf("expected leaving twice to conflict, got %v", err)
+	}
+	if _, err := waitlist.LeaveWaitlist(ctx, 999); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}